@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"log"
 
 	"github.com/spf13/viper"
@@ -15,4 +16,15 @@ func InitConfig() {
 	if err := viper.ReadInConfig(); err != nil {
 		log.Panicf("Error reading config file: %v", err)
 	}
+
+	// config.local.yml is not committed, it overrides the defaults on a developer machine
+	viper.SetConfigName("config.local")
+	if err := viper.MergeInConfig(); err != nil && !errors.As(err, &viper.ConfigFileNotFoundError{}) {
+		log.Panicf("Error reading local config file: %v", err)
+	}
+
+	// secrets are not committed, the environment overrides the config files
+	if err := viper.BindEnv("consistency.pinSecret", "CONSISTENCY_PIN_SECRET"); err != nil {
+		log.Panicf("Error binding environment variables: %v", err)
+	}
 }
@@ -6,7 +6,9 @@ const (
 	Consistency         = "consistency"
 	EventualConsistency = "eventual"
 	StrongConsistency   = "strong"
+	Transaction         = "transaction"
 	PrivateUserDetails  = "private_user_details"
+	TokenExpiresAt      = "token_expires_at"
 	ID                  = "id"
 	Email               = "email"
 	UserID              = "user_id"
@@ -20,4 +22,52 @@ const (
 	Description         = "description"
 	IsActive            = "is_active"
 	DeletedAt           = "deleted_at"
+	CreatedAt           = "created_at"
+	PaidAmount          = "paid_amount"
+	Category            = "category"
+	Currency            = "currency"
+	ToPayUserID         = "to_pay_user_id"
+	AmountDue           = "amount_due"
+	IsPaid              = "is_paid"
+	OwnerID             = "owner_id"
+	ContactUserID       = "contact_user_id"
+	AcceptedAt          = "accepted_at"
+	InviteID            = "invite_id"
+	SearchTerm          = "q"
+	DryRun              = "dry_run"
+	File                = "file"
+	FailedLoginAttempts = "failed_login_attempts"
+	LockedUntil         = "locked_until"
+	Key                 = "key"
+	StatusCode          = "status_code"
+	ContentType         = "content_type"
+	ResponseBody        = "response_body"
+	ResponseHeaders     = "response_headers"
+	CompletedAt         = "completed_at"
+	ExpiresAt           = "expires_at"
+	FromUserID          = "from_user_id"
+	ToUserID            = "to_user_id"
+	Amount              = "amount"
+	ActorID             = "actor_id"
+	Type                = "type"
+	URL                 = "url"
+	WebhookID           = "webhook_id"
+	DeliveryID          = "delivery_id"
+	Status              = "status"
+	Event               = "event"
+	Attempts            = "attempts"
+	NextAttemptAt       = "next_attempt_at"
+	DeliveredAt         = "delivered_at"
+	LastError           = "last_error"
+	Subscriber          = "subscriber"
+	EventID             = "event_id"
+	SplitID             = "split_id"
+	Enabled             = "enabled"
+	IntervalDays        = "interval_days"
+	UpdatedBy           = "updated_by"
+	LastRemindedAt      = "last_reminded_at"
+	SnoozedUntil        = "snoozed_until"
+	LastNudgedAt        = "last_nudged_at"
+	Month               = "month"
+	Format              = "format"
 )
@@ -4,14 +4,21 @@ import (
 	"context"
 	"fmt"
 	config "github.com/spf13/viper"
-	"main/internal/model"
 	opostgres "main/pkg/db/postgres"
+	"main/pkg/metrics"
+	"main/pkg/tracing"
 	"strings"
 	"time"
 )
 
 func Initialize(ctx context.Context) {
 	initializeDB(ctx)
+
+	if config.GetBool("postgresql.migrateOnStartup") {
+		if err := runMigrations(ctx, []string{migrateUp}); err != nil {
+			panic("failed to run migrations: " + err.Error())
+		}
+	}
 }
 
 func initializeDB(ctx context.Context) {
@@ -64,18 +71,31 @@ func initializeDB(ctx context.Context) {
 		slavesConfig = append(slavesConfig, slaveConfig)
 	}
 
-	db := opostgres.InitializeDBInstance(masterConfig, &slavesConfig)
+	backoff := opostgres.Backoff{
+		InitialInterval: config.GetDuration("postgresql.connectBackoff.initialInterval"),
+		MaxInterval:     config.GetDuration("postgresql.connectBackoff.maxInterval"),
+		MaxElapsedTime:  config.GetDuration("postgresql.connectBackoff.maxElapsedTime"),
+	}
+
+	db, err := opostgres.InitializeDBInstance(ctx, masterConfig, &slavesConfig, backoff)
+	if err != nil {
+		panic("failed to connect to postgres: " + err.Error())
+	}
 	fmt.Println("Initialized Postgres DB client")
 
-	db.GetSlaveDB(ctx).AutoMigrate(&model.AuthToken{})
-	db.GetSlaveDB(ctx).AutoMigrate(&model.OTP{})
-	db.GetSlaveDB(ctx).AutoMigrate(&model.User{})
+	for node, conn := range db.Nodes() {
+		if err := metrics.InstrumentDB(node, conn); err != nil {
+			panic("failed to instrument database node " + node + ": " + err.Error())
+		}
 
-	db.GetSlaveDB(ctx).AutoMigrate(&model.Groups{})
-	db.GetSlaveDB(ctx).AutoMigrate(&model.GroupUserPermission{})
-	db.GetSlaveDB(ctx).AutoMigrate(&model.User{})
-	db.GetSlaveDB(ctx).AutoMigrate(&model.Bill{})
-	db.GetSlaveDB(ctx).AutoMigrate(&model.BillSplit{})
+		role := "slave"
+		if node == opostgres.MasterNode {
+			role = opostgres.MasterNode
+		}
+		if err := tracing.InstrumentDB(node, role, conn); err != nil {
+			panic("failed to trace database node " + node + ": " + err.Error())
+		}
+	}
 
 	opostgres.SetCluster(db)
 }
@@ -0,0 +1,15 @@
+package init
+
+import (
+	config "github.com/spf13/viper"
+	"main/pkg/logger"
+)
+
+func InitLogger() {
+	logger.Init(logger.Config{
+		Level:        config.GetString("log.level"),
+		Format:       config.GetString("log.format"),
+		RedactFields: config.GetStringSlice("log.redact.fields"),
+		MaskEmails:   config.GetBool("log.redact.emails"),
+	})
+}
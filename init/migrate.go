@@ -0,0 +1,72 @@
+package init
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"main/migrations"
+	"main/pkg/db/migrate"
+	opostgres "main/pkg/db/postgres"
+	"strconv"
+)
+
+const (
+	migrateUp     = "up"
+	migrateDown   = "down"
+	migrateStatus = "status"
+)
+
+// Migrate backs the `migrate` subcommand: `migrate up`, `migrate down [steps]` and `migrate status`
+func Migrate(ctx context.Context, args []string) error {
+	initializeDB(ctx)
+
+	return runMigrations(ctx, args)
+}
+
+func runMigrations(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate command, expected one of %s, %s, %s", migrateUp, migrateDown, migrateStatus)
+	}
+
+	sqlDB, err := opostgres.GetCluster().GetMasterSqlDB()
+	if err != nil {
+		return fmt.Errorf("get master connection: %w", err)
+	}
+
+	migrator, err := migrate.New(sqlDB, migrations.FS, migrations.Dir)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case migrateUp:
+		return migrator.Up(ctx)
+	case migrateDown:
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+
+		return migrator.Down(ctx, steps)
+	case migrateStatus:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied"
+			}
+			log.Printf("%06d_%s: %s", status.Version, status.Name, state)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+}
@@ -0,0 +1,12 @@
+package init
+
+import (
+	config "github.com/spf13/viper"
+	"main/pkg/stream"
+)
+
+// InitStream installs the hub live group updates go through. The memory broker only reaches clients connected to
+// this instance, running several instances takes a shared stream.Broker here.
+func InitStream() {
+	stream.SetDefault(stream.NewHub(stream.NewMemoryBroker(), config.GetInt("stream.buffer")))
+}
@@ -0,0 +1,18 @@
+package init
+
+import (
+	"context"
+	config "github.com/spf13/viper"
+	"main/pkg/tracing"
+)
+
+// InitTracing installs the tracer provider, the returned func flushes buffered spans on shutdown
+func InitTracing(ctx context.Context) (func(context.Context) error, error) {
+	return tracing.Init(ctx, tracing.Config{
+		ServiceName: config.GetString("service.name"),
+		Exporter:    config.GetString("tracing.exporter"),
+		Endpoint:    config.GetString("tracing.endpoint"),
+		Insecure:    config.GetBool("tracing.insecure"),
+		SampleRatio: config.GetFloat64("tracing.sampleRatio"),
+	})
+}
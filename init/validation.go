@@ -0,0 +1,19 @@
+package init
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"main/pkg/validation"
+)
+
+// InitValidation adds the custom rules to the validator behind gin's binding
+func InitValidation() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		panic("unexpected validator engine")
+	}
+
+	if err := validation.Register(validate); err != nil {
+		panic("failed to register validation rules: " + err.Error())
+	}
+}
@@ -0,0 +1,69 @@
+package init
+
+import (
+	"context"
+	config "github.com/spf13/viper"
+	"log/slog"
+	activityService "main/internal/activity/service"
+	groupService "main/internal/group/service"
+	idempotencyService "main/internal/idempotency/service"
+	outboxService "main/internal/outbox/service"
+	reminderService "main/internal/reminder/service"
+	statementService "main/internal/statement/service"
+	webhookService "main/internal/webhook/service"
+	opostgres "main/pkg/db/postgres"
+	"main/pkg/stream"
+	"main/pkg/worker"
+	"time"
+)
+
+// StartWorkers launches the background jobs, they all stop once ctx is cancelled
+func StartWorkers(ctx context.Context, workers *worker.Group) {
+	workers.Go(ctx, "stream-hub", func(ctx context.Context) {
+		if err := stream.Default().Run(ctx); err != nil {
+			slog.Error("live group updates are unavailable", slog.Any("error", err))
+		}
+	})
+
+	workers.Go(ctx, "replica-health", opostgres.GetCluster().MonitorReplicas(opostgres.ReplicaCheckConfig{
+		Interval:          config.GetDuration("postgresql.replicaCheck.interval"),
+		Timeout:           config.GetDuration("postgresql.replicaCheck.timeout"),
+		MaxLag:            config.GetDuration("postgresql.replicaCheck.maxLag"),
+		FailureThreshold:  config.GetInt("postgresql.replicaCheck.failureThreshold"),
+		RecoveryThreshold: config.GetInt("postgresql.replicaCheck.recoveryThreshold"),
+	}))
+
+	idempotencyKeys := idempotencyService.Wire(ctx, opostgres.GetCluster().DbCluster)
+	workers.Go(ctx, "idempotency-purge", worker.Every(config.GetDuration("idempotency.purgeInterval"), func(ctx context.Context) {
+		idempotencyKeys.PurgeExpired(ctx)
+	}))
+
+	groups := groupService.Wire(ctx, opostgres.GetCluster().DbCluster)
+	retention := config.GetDuration("trash.retention")
+	workers.Go(ctx, "trash-purge", worker.Every(config.GetDuration("trash.purgeInterval"), func(ctx context.Context) {
+		groups.PurgeTrash(ctx, time.Now().Add(-retention))
+	}))
+
+	webhooks := webhookService.Wire(ctx, opostgres.GetCluster().DbCluster)
+	workers.Go(ctx, "webhook-dispatch", worker.Every(config.GetDuration("webhook.dispatchInterval"), webhooks.Dispatch))
+
+	// the names key the relay positions, renaming one replays the outbox to it
+	activities := activityService.Wire(ctx, opostgres.GetCluster().DbCluster)
+	outbox := outboxService.Wire(ctx, opostgres.GetCluster().DbCluster)
+	outbox.Subscribe("activity-feed", activities.Record)
+	outbox.Subscribe("live-stream", activities.Broadcast)
+	outbox.Subscribe("webhooks", webhooks.Enqueue)
+
+	workers.Go(ctx, "outbox-relay", worker.Every(config.GetDuration("outbox.relayInterval"), outbox.Relay))
+
+	outboxRetention := config.GetDuration("outbox.retention")
+	workers.Go(ctx, "outbox-purge", worker.Every(config.GetDuration("outbox.purgeInterval"), func(ctx context.Context) {
+		outbox.PurgeRelayed(ctx, time.Now().Add(-outboxRetention))
+	}))
+
+	reminders := reminderService.Wire(ctx, opostgres.GetCluster().DbCluster)
+	workers.Go(ctx, "payment-reminders", worker.Every(config.GetDuration("reminder.interval"), reminders.SendDueReminders))
+
+	statements := statementService.Wire(ctx, opostgres.GetCluster().DbCluster)
+	workers.Go(ctx, "monthly-statements", worker.Every(config.GetDuration("statement.interval"), statements.GenerateStatements))
+}
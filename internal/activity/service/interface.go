@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/repository/query"
+)
+
+type Interface interface {
+	Record(ctx context.Context, event model.OutboxEvent) apperror.Error
+	Broadcast(ctx context.Context, event model.OutboxEvent) apperror.Error
+	GetGroupActivity(ctx context.Context, groupID uint64, q *query.Query) (model.Activities, query.Page, apperror.Error)
+}
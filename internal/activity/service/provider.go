@@ -0,0 +1,15 @@
+package service
+
+import (
+	"github.com/google/wire"
+	activityRepo "main/internal/activity/repository"
+)
+
+var ProviderSet = wire.NewSet(
+	NewService,
+	activityRepo.NewRepository,
+
+	// bind each one of the interfaces
+	wire.Bind(new(Interface), new(*Service)),
+	wire.Bind(new(activityRepo.Interface), new(*activityRepo.Repository)),
+)
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"main/constants"
+	activityRepo "main/internal/activity/repository"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/logger"
+	"main/pkg/stream"
+	"main/pkg/tracing"
+	"main/repository/query"
+	"sync"
+)
+
+type Service struct {
+	activityRepo activityRepo.Interface
+}
+
+var (
+	syncOnce sync.Once
+	svc      *Service
+)
+
+func NewService(activityRepo activityRepo.Interface) *Service {
+	syncOnce.Do(func() {
+		svc = &Service{activityRepo: activityRepo}
+	})
+
+	return svc
+}
+
+// Record appends an event to the feed of its group, it subscribes the feed to the outbox
+func (s *Service) Record(ctx context.Context, event model.OutboxEvent) apperror.Error {
+	ctx, span := tracing.Start(ctx, "ActivityService.Record")
+	defer span.End()
+
+	log := logger.With(ctx, "Record")
+
+	activity := model.Activity{
+		GroupID:   event.GroupID,
+		ActorID:   event.ActorID,
+		Type:      event.Type,
+		Data:      event.Data,
+		CreatedAt: event.CreatedAt,
+	}
+	if err := s.activityRepo.Create(ctx, &activity); err.Exists() {
+		log.Errorf("failed to record %s activity in group %d: %v", event.Type, event.GroupID, err)
+		return apperror.NewCode(apperror.Internal, "Failed to record activity")
+	}
+
+	return apperror.Error{}
+}
+
+// Broadcast pushes an event to the members streaming its group, those that miss it see it in the feed
+func (s *Service) Broadcast(ctx context.Context, event model.OutboxEvent) apperror.Error {
+	ctx, span := tracing.Start(ctx, "ActivityService.Broadcast")
+	defer span.End()
+
+	log := logger.With(ctx, "Broadcast")
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Errorf("failed to encode event %d for streaming: %v", event.ID, err)
+		return apperror.NewCode(apperror.Internal, "Failed to encode event")
+	}
+
+	if err = stream.Default().Publish(ctx, stream.Event{GroupID: event.GroupID, Type: string(event.Type), Data: data}); err != nil {
+		log.Errorf("failed to publish event %d to group %d streams: %v", event.ID, event.GroupID, err)
+		return apperror.NewCode(apperror.Internal, "Failed to publish event")
+	}
+
+	return apperror.Error{}
+}
+
+// GetGroupActivity returns one page of the feed of a group, newest first unless q sorts otherwise
+func (s *Service) GetGroupActivity(
+	ctx context.Context,
+	groupID uint64,
+	q *query.Query,
+) (model.Activities, query.Page, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "ActivityService.GetGroupActivity")
+	defer span.End()
+
+	log := logger.With(ctx, "GetGroupActivity")
+
+	activities, page, err := s.activityRepo.GetAllWithPagination(ctx, map[string]any{
+		constants.GroupID: groupID,
+	}, q)
+	if err.Exists() {
+		log.Errorf("failed to fetch activity of group %d: %v", groupID, err)
+		return nil, page, apperror.NewCode(apperror.Internal, "Failed to fetch activity")
+	}
+
+	return activities, page, apperror.Error{}
+}
@@ -4,14 +4,13 @@ import (
 	"context"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/spf13/viper"
-	"log"
 	"main/constants"
 	"main/internal/auth/repository"
 	"main/internal/jwt/private"
 	"main/internal/model"
 	"main/pkg/apperror"
-	"main/util"
-	"net/http"
+	"main/pkg/logger"
+	"main/pkg/tracing"
 	"sync"
 	"time"
 )
@@ -34,14 +33,17 @@ func NewService(r repository.Interface) *Service {
 }
 
 func (s *Service) GenerateOrUpdateAuthToken(ctx context.Context, userID uint64) (model.AuthToken, apperror.Error) {
-	logTag := util.LogPrefix(ctx, "GenerateOrUpdateAuthToken")
+	ctx, span := tracing.Start(ctx, "AuthService.GenerateOrUpdateAuthToken")
+	defer span.End()
+
+	log := logger.With(ctx, "GenerateOrUpdateAuthToken")
 
 	// Generate access + refresh token pair
 	token, tokenErr := generateTokenPair(userID)
 	if tokenErr != nil {
-		log.Println(logTag, "Failed to generate token pair:", tokenErr)
+		log.Errorf("failed to generate token pair: %v", tokenErr)
 
-		return model.AuthToken{}, apperror.NewWithMessage("Failed to generate tokens", http.StatusBadRequest)
+		return model.AuthToken{}, apperror.NewCode(apperror.Internal, "Failed to generate tokens")
 	}
 
 	// Prepare AuthToken model
@@ -58,9 +60,9 @@ func (s *Service) GenerateOrUpdateAuthToken(ctx context.Context, userID uint64)
 		constants.UserID: userID,
 	})
 	if err.Exists() {
-		log.Println(logTag, "Failed to check existing tokens:", err)
+		log.Errorf("failed to check existing tokens: %v", err)
 
-		return model.AuthToken{}, apperror.NewWithMessage("Failed to update token", http.StatusBadRequest)
+		return model.AuthToken{}, apperror.NewCode(apperror.Internal, "Failed to update token")
 	}
 
 	if len(existingTokens) > 0 {
@@ -68,9 +70,9 @@ func (s *Service) GenerateOrUpdateAuthToken(ctx context.Context, userID uint64)
 			constants.UserID: userID,
 		}, &authToken)
 		if updateErr.Exists() {
-			log.Println(logTag, "Failed to update existing token:", updateErr)
+			log.Errorf("failed to update existing token: %v", updateErr)
 
-			return model.AuthToken{}, apperror.NewWithMessage("Failed to update token", http.StatusBadRequest)
+			return model.AuthToken{}, apperror.NewCode(apperror.Internal, "Failed to update token")
 		}
 
 		return authToken, apperror.Error{}
@@ -78,24 +80,27 @@ func (s *Service) GenerateOrUpdateAuthToken(ctx context.Context, userID uint64)
 
 	createErr := s.Create(ctx, &authToken)
 	if createErr.Exists() {
-		log.Println(logTag, "Failed to create new token:", createErr)
+		log.Errorf("failed to create new token: %v", createErr)
 
-		return model.AuthToken{}, apperror.NewWithMessage("Failed to create token", http.StatusBadRequest)
+		return model.AuthToken{}, apperror.NewCode(apperror.Internal, "Failed to create token")
 	}
 
 	return authToken, apperror.Error{}
 }
 
 func (s *Service) MarkTokenExpired(ctx context.Context, userID uint64) apperror.Error {
-	logTag := util.LogPrefix(ctx, "MarkTokenExpired")
+	ctx, span := tracing.Start(ctx, "AuthService.MarkTokenExpired")
+	defer span.End()
+
+	log := logger.With(ctx, "MarkTokenExpired")
 
 	existingTokens, err := s.GetAll(ctx, map[string]any{
 		constants.UserID: userID,
 	})
 	if err.Exists() {
-		log.Println(logTag, "Failed to check existing tokens:", err)
+		log.Errorf("failed to check existing tokens: %v", err)
 
-		return apperror.NewWithMessage("Failed to update token", http.StatusBadRequest)
+		return apperror.NewCode(apperror.Internal, "Failed to update token")
 	}
 
 	if len(existingTokens) > 0 {
@@ -109,9 +114,9 @@ func (s *Service) MarkTokenExpired(ctx context.Context, userID uint64) apperror.
 		constants.ID: token.ID,
 	}, &token)
 	if err.Exists() {
-		log.Println(logTag, "Failed to update existing token:", token, err)
+		log.Errorf("failed to expire token %d: %v", token.ID, err)
 
-		return apperror.NewWithMessage("Failed to update token", http.StatusBadRequest)
+		return apperror.NewCode(apperror.Internal, "Failed to update token")
 	}
 
 	return apperror.Error{}
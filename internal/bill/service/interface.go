@@ -4,11 +4,20 @@ import (
 	"context"
 	"main/internal/model"
 	"main/pkg/apperror"
+	"main/repository/query"
+	"time"
 )
 
 type Interface interface {
 	GetBills(ctx context.Context, filter map[string]any) (model.Bills, apperror.Error)
-	CreateBill(ctx context.Context, bill model.Bill) apperror.Error
-	UpdateBill(ctx context.Context, billID uint64, updates any) apperror.Error
+	GetGroupBills(ctx context.Context, groupID uint64, q *query.Query) (model.Bills, query.Page, apperror.Error)
+	CreateBill(ctx context.Context, bill *model.Bill) apperror.Error
+	CreateBills(ctx context.Context, bills model.Bills) apperror.Error
+	GetBillShares(ctx context.Context, billIDs []uint64) (model.BillShares, apperror.Error)
+	GetBill(ctx context.Context, groupID, billID uint64) (model.Bill, apperror.Error)
+	UpdateBill(ctx context.Context, groupID, billID, version uint64, updates map[string]any) apperror.Error
 	DeleteBill(ctx context.Context, billID uint64) apperror.Error
+	GetDeletedGroupBills(ctx context.Context, groupID uint64, q *query.Query) (model.Bills, query.Page, apperror.Error)
+	RestoreBill(ctx context.Context, groupID, billID uint64) apperror.Error
+	PurgeDeletedBills(ctx context.Context, before time.Time) apperror.Error
 }
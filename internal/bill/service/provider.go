@@ -3,13 +3,16 @@ package service
 import (
 	"github.com/google/wire"
 	"main/internal/bill/repository"
+	billShareRepo "main/internal/bill_share/repository"
 )
 
 var ProviderSet = wire.NewSet(
 	NewService,
 	repository.NewRepository,
+	billShareRepo.NewRepository,
 
 	// bind each one of the interfaces
 	wire.Bind(new(Interface), new(*Service)),
 	wire.Bind(new(repository.Interface), new(*repository.Repository)),
+	wire.Bind(new(billShareRepo.Interface), new(*billShareRepo.Repository)),
 )
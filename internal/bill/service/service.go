@@ -2,19 +2,26 @@ package service
 
 import (
 	"context"
-	"log"
+	"errors"
+	"gorm.io/gorm"
 	"main/constants"
 	"main/internal/bill/repository"
+	billShareRepo "main/internal/bill_share/repository"
 	"main/internal/model"
 	"main/pkg/apperror"
-	"main/util"
-	"net/http"
+	"main/pkg/db/postgres"
+	"main/pkg/logger"
+	"main/pkg/metrics"
+	"main/pkg/tracing"
+	baseRepository "main/repository"
+	"main/repository/query"
 	"sync"
 	"time"
 )
 
 type Service struct {
 	repository.Interface
+	billShareRepo billShareRepo.Interface
 }
 
 var (
@@ -22,64 +29,192 @@ var (
 	svc      *Service
 )
 
-func NewService(r repository.Interface) *Service {
+func NewService(r repository.Interface, billShareRepo billShareRepo.Interface) *Service {
 	syncOnce.Do(func() {
-		svc = &Service{r}
+		svc = &Service{r, billShareRepo}
 	})
 
 	return svc
 }
 
 func (s *Service) GetBills(ctx context.Context, filter map[string]any) (model.Bills, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "BillService.GetBills")
+	defer span.End()
+
 	return s.GetAll(ctx, filter)
 }
 
-func (s *Service) CreateBill(ctx context.Context, bill model.Bill) apperror.Error {
-	logTag := util.LogPrefix(ctx, "CreateBillForGroup")
+// GetBillShares returns the shares of the bills, bills shared equally have none
+func (s *Service) GetBillShares(ctx context.Context, billIDs []uint64) (model.BillShares, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "BillService.GetBillShares")
+	defer span.End()
+
+	if len(billIDs) == 0 {
+		return model.BillShares{}, apperror.Error{}
+	}
+
+	return s.billShareRepo.GetAll(ctx, map[string]any{constants.BillID: billIDs})
+}
+
+func (s *Service) GetGroupBills(ctx context.Context, groupID uint64, q *query.Query) (model.Bills, query.Page, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "BillService.GetGroupBills")
+	defer span.End()
+
+	log := logger.With(ctx, "GetGroupBills")
+
+	bills, page, err := s.GetAllWithPagination(ctx, map[string]any{constants.GroupID: groupID}, q)
+	if err.Exists() {
+		log.Errorf("failed to fetch bills page for group %d: %v", groupID, err)
+		return nil, page, apperror.NewCode(apperror.Internal, "Failed to fetch bills")
+	}
+
+	return bills, page, apperror.Error{}
+}
+
+// CreateBill stores bill and fills in its ID
+func (s *Service) CreateBill(ctx context.Context, bill *model.Bill) apperror.Error {
+	ctx, span := tracing.Start(ctx, "BillService.CreateBill")
+	defer span.End()
+
+	log := logger.With(ctx, "CreateBillForGroup")
+
+	err := s.Create(ctx, bill)
+	if err.Exists() {
+		log.Errorf("failed to create bill for bill %v: %v", *bill, err)
+		return translateConstraintError(err, "Failed to create bill")
+	}
+
+	metrics.BillsCreated.Inc()
+	return apperror.Error{}
+}
+
+func (s *Service) CreateBills(ctx context.Context, bills model.Bills) apperror.Error {
+	ctx, span := tracing.Start(ctx, "BillService.CreateBills")
+	defer span.End()
 
-	err := s.Create(ctx, &bill)
+	log := logger.With(ctx, "CreateBills")
+
+	if len(bills) == 0 {
+		return apperror.Error{}
+	}
+
+	records := make([]*model.Bill, 0, len(bills))
+	for i := range bills {
+		records = append(records, &bills[i])
+	}
+
+	// either every bill is stored or none is
+	err := s.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		if err := s.CreateMany(txCtx, records); err.Exists() {
+			return err
+		}
+
+		shares := make([]*model.BillShare, 0)
+		for _, bill := range records {
+			for i := range bill.Shares {
+				bill.Shares[i].BillID = bill.ID
+				shares = append(shares, &bill.Shares[i])
+			}
+		}
+		if len(shares) == 0 {
+			return apperror.Error{}
+		}
+
+		return s.billShareRepo.CreateMany(txCtx, shares)
+	})
 	if err.Exists() {
-		log.Printf("%s failed to create bill for bill %v: %v", logTag, bill, err)
-		return apperror.NewWithMessage("Failed to create bill", http.StatusBadRequest)
+		log.Errorf("failed to create %d bills: %v", len(bills), err)
+		return translateConstraintError(err, "Failed to create bills")
 	}
 
+	metrics.BillsCreated.Add(float64(len(bills)))
 	return apperror.Error{}
 }
 
-func (s *Service) UpdateBill(ctx context.Context, billID uint64, updates any) apperror.Error {
-	logTag := util.LogPrefix(ctx, "UpdateUserBill")
+func (s *Service) GetBill(ctx context.Context, groupID, billID uint64) (model.Bill, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "BillService.GetBill")
+	defer span.End()
+
+	log := logger.With(ctx, "GetBill")
 
 	bill, err := s.Get(ctx, map[string]any{
-		constants.ID: billID,
+		constants.ID:      billID,
+		constants.GroupID: groupID,
 	})
-	if err.Exists() || bill.ID == 0 {
-		log.Printf("%s attempted to update invalid or non-owned bill %d: %v", logTag, billID, err)
+	if errors.Is(err, apperror.NotFound) || (!err.Exists() && bill.ID == 0) {
+		return model.Bill{}, apperror.NewCode(apperror.BillNotFound, "Bill not found")
+	}
+	if err.Exists() {
+		log.Errorf("failed to fetch bill %d of group %d: %v", billID, groupID, err)
+		return model.Bill{}, apperror.NewCode(apperror.Internal, "Failed to fetch bill")
+	}
+
+	return bill, apperror.Error{}
+}
 
-		return apperror.NewWithMessage("Bill not found or unauthorized", http.StatusForbidden)
+// UpdateBill overwrites the bill of the group only if it is still at version, see repository.UpdateWithVersion
+func (s *Service) UpdateBill(ctx context.Context, groupID, billID, version uint64, updates map[string]any) apperror.Error {
+	ctx, span := tracing.Start(ctx, "BillService.UpdateBill")
+	defer span.End()
+
+	log := logger.With(ctx, "UpdateUserBill")
+
+	bill, err := s.GetBill(ctx, groupID, billID)
+	if err.Exists() {
+		log.Warnf("attempted to update bill %d outside of group %d: %v", billID, groupID, err)
+
+		return err
 	}
 
-	err = s.Update(ctx, map[string]any{
-		constants.ID: billID,
-	}, updates)
+	// shares are amounts, they would no longer add up to a different cost
+	if amount, ok := updates[constants.PaidAmount]; ok && amount != bill.PaidAmount {
+		shares, err := s.GetBillShares(ctx, []uint64{billID})
+		if err.Exists() {
+			log.Errorf("failed to fetch the shares of bill %d: %v", billID, err)
+			return apperror.NewCode(apperror.Internal, "Failed to update bill")
+		}
+
+		if len(shares) > 0 {
+			return apperror.NewValidation(apperror.FieldError{
+				Field:   constants.PaidAmount,
+				Rule:    "shares",
+				Message: "cannot change on a bill split by shares",
+			})
+		}
+	}
+
+	err = s.UpdateWithVersion(ctx, map[string]any{
+		constants.ID:      billID,
+		constants.GroupID: groupID,
+	}, version, updates)
 	if err.Exists() {
-		log.Printf("%s failed to update bill %d %v", logTag, billID, err)
+		if errors.Is(err, baseRepository.ErrVersionConflict) {
+			log.Warnf("bill %d is no longer at version %d", billID, version)
+
+			return apperror.NewCode(apperror.VersionConflict, "Bill was changed by someone else, fetch it again and retry")
+		}
+
+		log.Errorf("failed to update bill %d %v", billID, err)
 
-		return apperror.NewWithMessage("Failed to update bill", http.StatusBadRequest)
+		return translateConstraintError(err, "Failed to update bill")
 	}
 
 	return apperror.Error{}
 }
 
 func (s *Service) DeleteBill(ctx context.Context, billID uint64) apperror.Error {
-	logTag := util.LogPrefix(ctx, "DeleteBillByID")
+	ctx, span := tracing.Start(ctx, "BillService.DeleteBill")
+	defer span.End()
+
+	log := logger.With(ctx, "DeleteBillByID")
 
 	bill, err := s.Get(ctx, map[string]any{
 		constants.ID: billID,
 	})
 	if err.Exists() || bill.ID == 0 {
-		log.Printf("%s bill with ID %d not found or already deleted: %v", logTag, billID, err)
+		log.Warnf("bill with ID %d not found or already deleted: %v", billID, err)
 
-		return apperror.NewWithMessage("Bill not found", http.StatusNotFound)
+		return apperror.NewCode(apperror.BillNotFound, "Bill not found")
 	}
 
 	err = s.Update(ctx, map[string]any{
@@ -88,10 +223,97 @@ func (s *Service) DeleteBill(ctx context.Context, billID uint64) apperror.Error
 		constants.DeletedAt: time.Now(),
 	})
 	if err.Exists() {
-		log.Printf("%s failed to soft delete bill ID %d: %v", logTag, billID, err)
+		log.Errorf("failed to soft delete bill ID %d: %v", billID, err)
 
-		return apperror.NewWithMessage("Failed to delete bill", http.StatusBadRequest)
+		return apperror.NewCode(apperror.Internal, "Failed to delete bill")
 	}
 
 	return apperror.Error{}
 }
+
+// GetDeletedGroupBills lists the trash of a group, the bills deleted from it that were not purged yet
+func (s *Service) GetDeletedGroupBills(
+	ctx context.Context,
+	groupID uint64,
+	q *query.Query,
+) (model.Bills, query.Page, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "BillService.GetDeletedGroupBills")
+	defer span.End()
+
+	log := logger.With(ctx, "GetDeletedGroupBills")
+
+	bills, page, err := s.GetAllWithPagination(ctx, map[string]any{
+		constants.GroupID: groupID,
+	}, q, baseRepository.OnlyDeleted)
+	if err.Exists() {
+		log.Errorf("failed to fetch deleted bills of group %d: %v", groupID, err)
+		return nil, page, apperror.NewCode(apperror.Internal, "Failed to fetch deleted bills")
+	}
+
+	return bills, page, apperror.Error{}
+}
+
+func (s *Service) RestoreBill(ctx context.Context, groupID, billID uint64) apperror.Error {
+	ctx, span := tracing.Start(ctx, "BillService.RestoreBill")
+	defer span.End()
+
+	log := logger.With(ctx, "RestoreBill")
+
+	err := s.Restore(ctx, map[string]any{
+		constants.ID:      billID,
+		constants.GroupID: groupID,
+	})
+	if errors.Is(err, apperror.NotFound) {
+		log.Warnf("bill %d is not in the trash of group %d", billID, groupID)
+
+		return apperror.NewCode(apperror.BillNotFound, "Bill not found in trash")
+	}
+	if err.Exists() {
+		log.Errorf("failed to restore bill %d: %v", billID, err)
+
+		return apperror.NewCode(apperror.Internal, "Failed to restore bill")
+	}
+
+	return apperror.Error{}
+}
+
+// PurgeDeletedBills permanently deletes the bills that were deleted before the given time
+func (s *Service) PurgeDeletedBills(ctx context.Context, before time.Time) apperror.Error {
+	ctx, span := tracing.Start(ctx, "BillService.PurgeDeletedBills")
+	defer span.End()
+
+	log := logger.With(ctx, "PurgeDeletedBills")
+
+	purged, err := s.Purge(ctx, map[string]any{}, baseRepository.DeletedBefore(before))
+	if err.Exists() {
+		log.Errorf("failed to purge bills deleted before %s: %v", before, err)
+		return apperror.NewCode(apperror.Internal, "Failed to purge deleted bills")
+	}
+
+	if purged > 0 {
+		log.Infof("purged %d bills deleted before %s", purged, before)
+	}
+
+	return apperror.Error{}
+}
+
+// translateConstraintError tells which rule of the bills table a write broke, going by the constraint name
+func translateConstraintError(err apperror.Error, fallback string) apperror.Error {
+	switch postgres.ConstraintName(err) {
+	case "fk_bills_user":
+		return apperror.NewCode(apperror.InvalidReference, "Bill payer does not exist")
+	case "fk_bills_group":
+		return apperror.NewCode(apperror.InvalidReference, "Group does not exist")
+	case "chk_bills_paid_amount":
+		return apperror.NewCode(apperror.InvalidAmount, "Paid amount must be greater than zero")
+	}
+
+	switch {
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return apperror.NewCode(apperror.InvalidReference, "Bill references a row that does not exist")
+	case errors.Is(err, gorm.ErrCheckConstraintViolated):
+		return apperror.NewCode(apperror.UnprocessableEntity, "Bill breaks a data constraint")
+	default:
+		return apperror.NewCode(apperror.Internal, fallback)
+	}
+}
@@ -9,6 +9,7 @@ package service
 import (
 	"context"
 	"main/internal/bill/repository"
+	repository2 "main/internal/bill_share/repository"
 	"main/pkg/db/postgres"
 )
 
@@ -16,6 +17,7 @@ import (
 
 func Wire(ctx context.Context, db *postgres.DbCluster) *Service {
 	repositoryRepository := repository.NewRepository(db)
-	service := NewService(repositoryRepository)
+	repository3 := repository2.NewRepository(db)
+	service := NewService(repositoryRepository, repository3)
 	return service
 }
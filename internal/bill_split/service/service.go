@@ -2,23 +2,32 @@ package service
 
 import (
 	"context"
-	"log"
+	"errors"
+	"gorm.io/gorm"
 	"main/constants"
 	billSvc "main/internal/bill/service"
 	billSplitRepo "main/internal/bill_split/repository"
 	groupSvc "main/internal/group/service"
 	"main/internal/model"
+	outboxSvc "main/internal/outbox/service"
+	settlementRepo "main/internal/settlement/repository"
 	"main/pkg/apperror"
-	"main/util"
+	"main/pkg/logger"
+	"main/pkg/metrics"
+	"main/pkg/tracing"
 	"math"
-	"net/http"
 	"sync"
 )
 
+// settledTolerance absorbs float rounding, balances within half a cent are settled
+const settledTolerance = 0.005
+
 type Service struct {
-	billSplitRepo billSplitRepo.Interface
-	billSvc       billSvc.Interface
-	groupSvc      groupSvc.Interface
+	billSplitRepo  billSplitRepo.Interface
+	billSvc        billSvc.Interface
+	groupSvc       groupSvc.Interface
+	settlementRepo settlementRepo.Interface
+	outboxSvc      outboxSvc.Interface
 }
 
 var (
@@ -30,20 +39,34 @@ func NewService(
 	billSplitRepo billSplitRepo.Interface,
 	billSvc billSvc.Interface,
 	groupSvc groupSvc.Interface,
+	settlementRepo settlementRepo.Interface,
+	outboxSvc outboxSvc.Interface,
 ) *Service {
 	syncOnce.Do(func() {
-		svc = &Service{billSplitRepo: billSplitRepo, billSvc: billSvc, groupSvc: groupSvc}
+		svc = &Service{
+			billSplitRepo:  billSplitRepo,
+			billSvc:        billSvc,
+			groupSvc:       groupSvc,
+			settlementRepo: settlementRepo,
+			outboxSvc:      outboxSvc,
+		}
 	})
 
 	return svc
 }
 
 func (s *Service) GetBillSplitsByFilter(ctx context.Context, filter map[string]any) ([]model.BillSplit, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "BillSplitService.GetBillSplitsByFilter")
+	defer span.End()
+
 	return s.billSplitRepo.GetAll(ctx, filter)
 }
 
 func (s *Service) CalculateAndSaveBillSplits(ctx context.Context, userID, groupID uint64) (model.BillSplits, apperror.Error) {
-	logTag := util.LogPrefix(ctx, "CalculateAndSaveBillSplits")
+	ctx, span := tracing.Start(ctx, "BillSplitService.CalculateAndSaveBillSplits")
+	defer span.End()
+
+	log := logger.With(ctx, "CalculateAndSaveBillSplits")
 
 	isValid, err := s.validateUserGroupBillSplitsAccess(ctx, userID, groupID, model.Create)
 	if err.Exists() {
@@ -51,44 +74,85 @@ func (s *Service) CalculateAndSaveBillSplits(ctx context.Context, userID, groupI
 	}
 
 	if !isValid {
-		return nil, apperror.NewWithMessage("User is not authorized or no bill splits exist for this group", http.StatusForbidden)
+		return nil, apperror.NewCode(apperror.PermissionDenied, "User is not authorized or no bill splits exist for this group")
 	}
 
 	bills, err := s.billSvc.GetBills(ctx, map[string]any{
 		constants.GroupID: groupID,
 	})
 	if err.Exists() {
-		log.Printf("%s failed to retrieve bills for group %d: %v", logTag, groupID, err)
+		log.Errorf("failed to retrieve bills for group %d: %v", groupID, err)
 
-		return nil, apperror.NewWithMessage("Failed to fetch bills", http.StatusBadRequest)
+		return nil, apperror.NewCode(apperror.Internal, "Failed to fetch bills")
 	}
 
 	if len(bills) == 0 {
-		return nil, apperror.NewWithMessage("No bills found for group", http.StatusNotFound)
+		return nil, apperror.NewCode(apperror.NoBillsToSplit, "No bills found for group")
 	}
 
-	memberSpend := make(map[uint64]float64)
-	var total float64
-	for _, bill := range bills {
-		memberSpend[bill.UserID] += bill.PaidAmount
-		total += bill.PaidAmount
+	shares, err := s.billSvc.GetBillShares(ctx, bills.IDs())
+	if err.Exists() {
+		log.Errorf("failed to retrieve bill shares for group %d: %v", groupID, err)
+
+		return nil, apperror.NewCode(apperror.Internal, "Failed to fetch bills")
 	}
+	bills = bills.WithShares(shares)
 
-	numMembers := len(memberSpend)
-	if numMembers == 0 {
-		return nil, apperror.NewWithMessage("No members in group", http.StatusBadRequest)
+	settlements, err := s.settlementRepo.GetAll(ctx, map[string]any{
+		constants.GroupID: groupID,
+	})
+	if err.Exists() {
+		log.Errorf("failed to retrieve settlements for group %d: %v", groupID, err)
+
+		return nil, apperror.NewCode(apperror.Internal, "Failed to fetch settlements")
 	}
 
-	perHead := total / float64(numMembers)
+	// a debt is paid back in the currency it was run up in, each currency is split on its own
+	balances := model.CurrencyBalances(bills, settlements)
+	if len(balances) == 0 {
+		return nil, apperror.NewCode(apperror.GroupHasNoMembers, "No members in group")
+	}
 
+	billSplits := make(model.BillSplits, 0)
+	for currency, currencyBalances := range balances {
+		billSplits = append(billSplits, splitBalances(groupID, currency, currencyBalances)...)
+	}
+
+	err = s.billSplitRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		if err := s.billSplitRepo.CreateMany(txCtx, billSplits); err.Exists() {
+			log.Errorf("failed to save bill splits: %v", err)
+
+			if errors.Is(err, gorm.ErrForeignKeyViolated) || errors.Is(err, gorm.ErrCheckConstraintViolated) {
+				return apperror.NewCode(apperror.InvalidReference, "Bill splits reference invalid users or amounts")
+			}
+
+			return apperror.NewCode(apperror.Internal, "Failed to store bill splits")
+		}
+
+		return s.outboxSvc.Publish(txCtx, model.OutboxEvent{
+			GroupID: groupID,
+			ActorID: userID,
+			Type:    model.SplitCalculated,
+		})
+	})
+	if err.Exists() {
+		return nil, err
+	}
+
+	metrics.SplitsCalculated.Inc()
+
+	return billSplits, apperror.Error{}
+}
+
+// splitBalances pairs the debtors with the creditors of one currency until every balance is settled
+func splitBalances(groupID uint64, currency string, balances map[uint64]float64) model.BillSplits {
 	debtors := make(map[uint64]float64)
 	creditors := make(map[uint64]float64)
 
-	for uid, paid := range memberSpend {
-		diff := paid - perHead
-		if diff < 0 {
+	for uid, diff := range balances {
+		if diff < -settledTolerance {
 			debtors[uid] = -diff
-		} else if diff > 0 {
+		} else if diff > settledTolerance {
 			creditors[uid] = diff
 		}
 	}
@@ -97,10 +161,10 @@ func (s *Service) CalculateAndSaveBillSplits(ctx context.Context, userID, groupI
 
 	for debtorID, due := range debtors {
 		for creditorID, credit := range creditors {
-			if due == 0 {
+			if due < settledTolerance {
 				break
 			}
-			if credit == 0 {
+			if credit < settledTolerance {
 				continue
 			}
 
@@ -111,6 +175,7 @@ func (s *Service) CalculateAndSaveBillSplits(ctx context.Context, userID, groupI
 				UserID:      debtorID,
 				ToPayUserID: creditorID,
 				AmountDue:   amount,
+				Currency:    currency,
 			})
 
 			debtors[debtorID] -= amount
@@ -119,30 +184,42 @@ func (s *Service) CalculateAndSaveBillSplits(ctx context.Context, userID, groupI
 		}
 	}
 
-	err = s.billSplitRepo.CreateMany(ctx, billSplits)
-	if err.Exists() {
-		log.Printf("%s failed to save bill splits: %v", logTag, err)
-
-		return nil, apperror.NewWithMessage("Failed to store bill splits", http.StatusBadRequest)
-	}
-
-	return billSplits, apperror.Error{}
+	return billSplits
 }
 
 func (s *Service) RecalculateBillSplits(ctx context.Context, userID, groupID uint64) (model.BillSplits, apperror.Error) {
-	logTag := util.LogPrefix(ctx, "RecalculateBillSplits")
+	ctx, span := tracing.Start(ctx, "BillSplitService.RecalculateBillSplits")
+	defer span.End()
+
+	log := logger.With(ctx, "RecalculateBillSplits")
+
+	var splits model.BillSplits
+
+	// old splits are only dropped if the new ones are stored in the same transaction
+	err := s.billSplitRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		clearErr := s.ClearBillSplitsForGroup(txCtx, groupID)
+		if clearErr.Exists() {
+			log.Errorf("failed to clear old bill splits for group %d: %v", groupID, clearErr)
+			return apperror.NewCode(apperror.Internal, "Failed to clear old bill splits")
+		}
+
+		var calcErr apperror.Error
+		splits, calcErr = s.CalculateAndSaveBillSplits(txCtx, userID, groupID)
 
-	err := s.ClearBillSplitsForGroup(ctx, groupID)
+		return calcErr
+	})
 	if err.Exists() {
-		log.Printf("%s failed to clear old bill splits for group %d: %v", logTag, groupID, err)
-		return nil, apperror.NewWithMessage("Failed to clear old bill splits", http.StatusBadRequest)
+		return nil, err
 	}
 
-	return s.CalculateAndSaveBillSplits(ctx, userID, groupID)
+	return splits, apperror.Error{}
 }
 
 func (s *Service) ClearBillSplitsForGroup(ctx context.Context, groupID uint64) apperror.Error {
-	logTag := util.LogPrefix(ctx, "ClearBillSplitsForGroup")
+	ctx, span := tracing.Start(ctx, "BillSplitService.ClearBillSplitsForGroup")
+	defer span.End()
+
+	log := logger.With(ctx, "ClearBillSplitsForGroup")
 
 	err := s.billSplitRepo.Delete(ctx,
 		map[string]any{
@@ -150,9 +227,9 @@ func (s *Service) ClearBillSplitsForGroup(ctx context.Context, groupID uint64) a
 		},
 	)
 	if err.Exists() {
-		log.Printf("%s failed to soft delete bill splits for group %d : %v", logTag, groupID, err)
+		log.Errorf("failed to soft delete bill splits for group %d : %v", groupID, err)
 
-		return apperror.NewWithMessage("Failed to clear bill splits", http.StatusBadRequest)
+		return apperror.NewCode(apperror.Internal, "Failed to clear bill splits")
 	}
 
 	return apperror.Error{}
@@ -164,26 +241,26 @@ func (s *Service) validateUserGroupBillSplitsAccess(
 	groupID uint64,
 	permissionType model.PermissionType,
 ) (bool, apperror.Error) {
-	logTag := util.LogPrefix(ctx, "validateUserGroupBillSplitsAccess")
+	log := logger.With(ctx, "validateUserGroupBillSplitsAccess")
 
 	hasPermission, err := s.groupSvc.ValidateUserGroupPermission(ctx, userID, groupID, permissionType)
 	if err.Exists() || !hasPermission {
-		log.Printf("%s user %d does not have '%s' permission on group %d: %v", logTag, userID, permissionType, groupID, err)
+		log.Warnf("user %d does not have '%s' permission on group %d: %v", userID, permissionType, groupID, err)
 
-		return false, apperror.NewWithMessage("Permission denied for accessing bill splits", http.StatusForbidden)
+		return false, apperror.NewCode(apperror.PermissionDenied, "Permission denied for accessing bill splits")
 	}
 
 	bills, err := s.billSplitRepo.GetAll(ctx, map[string]interface{}{constants.GroupID: groupID})
 	if err.Exists() {
-		log.Printf("%s failed to retrieve bill splits for group %d: %v", logTag, groupID, err)
+		log.Errorf("failed to retrieve bill splits for group %d: %v", groupID, err)
 
-		return false, apperror.NewWithMessage("Failed to retrieve bill splits for group", http.StatusBadRequest)
+		return false, apperror.NewCode(apperror.Internal, "Failed to retrieve bill splits for group")
 	}
 
 	if len(bills) > 0 {
-		log.Printf("%s bill splits already exist for group %d", logTag, groupID)
+		log.Warnf("bill splits already exist for group %d", groupID)
 
-		return false, apperror.NewWithMessage("Bill has already been split for this group", http.StatusBadRequest)
+		return false, apperror.NewCode(apperror.SplitAlreadyExists, "Bill has already been split for this group")
 	}
 
 	return len(bills) == 0, apperror.Error{}
@@ -8,19 +8,30 @@ package service
 
 import (
 	"context"
-	repository6 "main/internal/auth/repository"
+	repository9 "main/internal/activity/repository"
+	service6 "main/internal/activity/service"
+	repository7 "main/internal/auth/repository"
 	service3 "main/internal/auth/service"
 	repository2 "main/internal/bill/repository"
 	"main/internal/bill/service"
+	repository3 "main/internal/bill_share/repository"
 	"main/internal/bill_split/repository"
-	repository3 "main/internal/group/repository"
-	service6 "main/internal/group/service"
-	repository4 "main/internal/group_permission/repository"
+	repository4 "main/internal/group/repository"
+	service9 "main/internal/group/service"
+	repository5 "main/internal/group_permission/repository"
 	service2 "main/internal/group_permission/service"
-	repository7 "main/internal/otp/repository"
+	repository8 "main/internal/otp/repository"
 	service4 "main/internal/otp/service"
-	repository5 "main/internal/user/repository"
+	repository12 "main/internal/outbox/repository"
+	service8 "main/internal/outbox/service"
+	repository14 "main/internal/outbox_delivery/repository"
+	repository13 "main/internal/outbox_position/repository"
+	repository15 "main/internal/settlement/repository"
+	repository6 "main/internal/user/repository"
 	service5 "main/internal/user/service"
+	repository10 "main/internal/webhook/repository"
+	service7 "main/internal/webhook/service"
+	repository11 "main/internal/webhook_delivery/repository"
 	"main/pkg/db/postgres"
 )
 
@@ -28,18 +39,29 @@ import (
 
 func Wire(ctx context.Context, db *postgres.DbCluster) *Service {
 	repositoryRepository := repository.NewRepository(db)
-	repository8 := repository2.NewRepository(db)
-	serviceService := service.NewService(repository8)
-	repository9 := repository3.NewRepository(db)
-	repository10 := repository4.NewRepository(db)
-	service7 := service2.NewService(repository10)
-	repository11 := repository5.NewRepository(db)
-	repository12 := repository6.NewRepository(db)
-	service8 := service3.NewService(repository12)
-	repository13 := repository7.NewRepository(db)
-	service9 := service4.NewService(repository13)
-	service10 := service5.NewService(repository11, service8, service9)
-	service11 := service6.NewService(repository9, service7, serviceService, service10)
-	service12 := NewService(repositoryRepository, serviceService, service11)
-	return service12
+	repository16 := repository2.NewRepository(db)
+	repository17 := repository3.NewRepository(db)
+	serviceService := service.NewService(repository16, repository17)
+	repository18 := repository4.NewRepository(db)
+	repository19 := repository5.NewRepository(db)
+	service10 := service2.NewService(repository19)
+	repository20 := repository6.NewRepository(db)
+	repository21 := repository7.NewRepository(db)
+	service11 := service3.NewService(repository21)
+	repository22 := repository8.NewRepository(db)
+	service12 := service4.NewService(repository22)
+	service13 := service5.NewService(repository20, service11, service12)
+	repository23 := repository9.NewRepository(db)
+	service14 := service6.NewService(repository23)
+	repository24 := repository10.NewRepository(db)
+	repository25 := repository11.NewRepository(db)
+	service15 := service7.NewService(repository24, repository25)
+	repository26 := repository12.NewRepository(db)
+	repository27 := repository13.NewRepository(db)
+	repository28 := repository14.NewRepository(db)
+	service16 := service8.NewService(repository26, repository27, repository28)
+	service17 := service9.NewService(repository18, service10, serviceService, service13, service14, service15, service16)
+	repository29 := repository15.NewRepository(db)
+	service18 := NewService(repositoryRepository, serviceService, service17, repository29, service16)
+	return service18
 }
@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"main/constants"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/logger"
+	"main/pkg/notify"
+	"main/pkg/tracing"
+	baseRepository "main/repository"
+	"main/repository/query"
+	"main/util"
+	"strings"
+	"time"
+)
+
+const (
+	minSearchTermLength = 2
+	maxSearchResults    = 10
+
+	// kind of the notification telling a user about a contact invite
+	contactInviteKind = "contact.invite"
+)
+
+func (s *Service) ListContacts(ctx context.Context, userID uint64, q *query.Query) (model.Users, query.Page, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "ContactService.ListContacts")
+	defer span.End()
+
+	log := logger.With(ctx, "ListContacts")
+
+	contacts, err := s.contactRepo.GetAll(ctx, map[string]any{constants.UserID: userID})
+	if err.Exists() {
+		log.Errorf("failed to fetch contacts of user %d: %v", userID, err)
+
+		return nil, query.Page{}, apperror.NewCode(apperror.Internal, "Failed to fetch contacts")
+	}
+
+	if len(contacts) == 0 {
+		return make(model.Users, 0), query.Page{}, apperror.Error{}
+	}
+
+	users, page, err := s.userRepo.GetAllWithPagination(ctx, map[string]any{
+		constants.ID: model.Contacts(contacts).GetContactUserIDs(),
+	}, q)
+	if err.Exists() {
+		log.Errorf("failed to fetch contact users of user %d: %v", userID, err)
+
+		return nil, page, apperror.NewCode(apperror.Internal, "Failed to fetch contacts")
+	}
+
+	return users, page, apperror.Error{}
+}
+
+// AddContact invites the owner of email to become a contact. Nobody is connected until the invited user accepts,
+// whether or not the email is registered, and the answer is the same in both cases so the endpoint does not tell
+// which emails have an account.
+func (s *Service) AddContact(ctx context.Context, userID uint64, email string) apperror.Error {
+	ctx, span := tracing.Start(ctx, "ContactService.AddContact")
+	defer span.End()
+
+	log := logger.With(ctx, "AddContact")
+
+	// invites are stored and looked up in lower case, addresses differing in case reach the same mailbox
+	email = strings.ToLower(util.TrimSpace(email))
+	if !util.IsValidEmail(email) {
+		return apperror.NewValidation(apperror.FieldError{
+			Field:   constants.Email,
+			Rule:    "email",
+			Message: "must be a valid email address",
+		})
+	}
+
+	inviter, err := s.userRepo.Get(ctx, map[string]any{constants.ID: userID})
+	if err.Exists() {
+		log.Errorf("failed to fetch user %d: %v", userID, err)
+		return apperror.NewCode(apperror.Internal, "Failed to add contact")
+	}
+
+	if strings.EqualFold(inviter.Email, email) {
+		return apperror.NewCode(apperror.BadRequest, "You cannot add yourself as a contact")
+	}
+
+	users, err := s.userRepo.GetAll(ctx, nil, baseRepository.EqualFold(constants.Email, email))
+	if err.Exists() {
+		log.Errorf("failed to look up user by email for user %d: %v", userID, err)
+		return apperror.NewCode(apperror.Internal, "Failed to add contact")
+	}
+
+	// only a contact of the caller is told apart here, and the caller already knows them
+	var invitee model.User
+	if len(users) > 0 {
+		invitee = users[0]
+
+		existing, err := s.contactRepo.GetAll(ctx, map[string]any{
+			constants.UserID:        userID,
+			constants.ContactUserID: invitee.ID,
+		})
+		if err.Exists() {
+			log.Errorf("failed to check contact %d of user %d: %v", invitee.ID, userID, err)
+			return apperror.NewCode(apperror.Internal, "Failed to add contact")
+		}
+
+		if len(existing) > 0 {
+			return apperror.NewCode(apperror.ContactAlreadyExists, "User is already a contact")
+		}
+	}
+
+	return s.inviteContact(ctx, inviter, email, invitee)
+}
+
+// GetContactInvites lists the pending invites addressed to the caller's email
+func (s *Service) GetContactInvites(ctx context.Context, userID uint64) (model.ContactInvites, model.Users, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "ContactService.GetContactInvites")
+	defer span.End()
+
+	log := logger.With(ctx, "GetContactInvites")
+
+	user, err := s.userRepo.Get(ctx, map[string]any{constants.ID: userID})
+	if err.Exists() {
+		log.Errorf("failed to fetch user %d: %v", userID, err)
+		return nil, nil, apperror.NewCode(apperror.Internal, "Failed to fetch contact invites")
+	}
+
+	invites, err := s.contactInviteRepo.GetAll(ctx, map[string]any{
+		constants.Email:      strings.ToLower(user.Email),
+		constants.AcceptedAt: nil,
+	})
+	if err.Exists() {
+		log.Errorf("failed to fetch contact invites of user %d: %v", userID, err)
+		return nil, nil, apperror.NewCode(apperror.Internal, "Failed to fetch contact invites")
+	}
+
+	if len(invites) == 0 {
+		return make(model.ContactInvites, 0), make(model.Users, 0), apperror.Error{}
+	}
+
+	inviters, err := s.userRepo.GetAll(ctx, map[string]any{
+		constants.ID: model.ContactInvites(invites).GetInviterIDs(),
+	})
+	if err.Exists() {
+		log.Errorf("failed to fetch inviters of user %d: %v", userID, err)
+		return nil, nil, apperror.NewCode(apperror.Internal, "Failed to fetch contact invites")
+	}
+
+	return invites, inviters, apperror.Error{}
+}
+
+// AcceptContactInvite connects the caller with the user who invited them
+func (s *Service) AcceptContactInvite(ctx context.Context, userID, inviteID uint64) apperror.Error {
+	ctx, span := tracing.Start(ctx, "ContactService.AcceptContactInvite")
+	defer span.End()
+
+	log := logger.With(ctx, "AcceptContactInvite")
+
+	invite, err := s.getPendingInvite(ctx, userID, inviteID)
+	if err.Exists() {
+		return err
+	}
+
+	return s.contactInviteRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		// a failed insert would abort the whole transaction, so an existing friendship is checked up front
+		existing, err := s.contactRepo.GetAll(txCtx, map[string]any{
+			constants.UserID:        invite.InviterID,
+			constants.ContactUserID: userID,
+		})
+		if err.Exists() {
+			log.Errorf("failed to check contact %d of user %d: %v", userID, invite.InviterID, err)
+			return apperror.NewCode(apperror.Internal, "Failed to accept contact invite")
+		}
+
+		if len(existing) == 0 {
+			if err = s.connect(txCtx, invite.InviterID, userID); err.Exists() {
+				return err
+			}
+		}
+
+		err = s.contactInviteRepo.Update(txCtx, map[string]any{
+			constants.ID: invite.ID,
+		}, map[string]any{
+			constants.AcceptedAt: time.Now(),
+		})
+		if err.Exists() {
+			log.Errorf("failed to mark invite %d as accepted: %v", invite.ID, err)
+			return apperror.NewCode(apperror.Internal, "Failed to accept contact invite")
+		}
+
+		return apperror.Error{}
+	})
+}
+
+// DeclineContactInvite drops an invite addressed to the caller, the inviter may send a new one later
+func (s *Service) DeclineContactInvite(ctx context.Context, userID, inviteID uint64) apperror.Error {
+	ctx, span := tracing.Start(ctx, "ContactService.DeclineContactInvite")
+	defer span.End()
+
+	log := logger.With(ctx, "DeclineContactInvite")
+
+	invite, err := s.getPendingInvite(ctx, userID, inviteID)
+	if err.Exists() {
+		return err
+	}
+
+	if err = s.contactInviteRepo.Delete(ctx, map[string]any{constants.ID: invite.ID}); err.Exists() {
+		log.Errorf("failed to delete invite %d: %v", invite.ID, err)
+		return apperror.NewCode(apperror.Internal, "Failed to decline contact invite")
+	}
+
+	return apperror.Error{}
+}
+
+func (s *Service) RemoveContact(ctx context.Context, userID, contactUserID uint64) apperror.Error {
+	ctx, span := tracing.Start(ctx, "ContactService.RemoveContact")
+	defer span.End()
+
+	log := logger.With(ctx, "RemoveContact")
+
+	existing, err := s.contactRepo.GetAll(ctx, map[string]any{
+		constants.UserID:        userID,
+		constants.ContactUserID: contactUserID,
+	})
+	if err.Exists() {
+		log.Errorf("failed to fetch contact %d of user %d: %v", contactUserID, userID, err)
+
+		return apperror.NewCode(apperror.Internal, "Failed to remove contact")
+	}
+
+	if len(existing) == 0 {
+		return apperror.NewCode(apperror.ContactNotFound, "Contact not found")
+	}
+
+	// friendships are symmetric, so both directions go together
+	return s.contactRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		for _, pair := range [][2]uint64{{userID, contactUserID}, {contactUserID, userID}} {
+			deleteErr := s.contactRepo.Delete(txCtx, map[string]any{
+				constants.UserID:        pair[0],
+				constants.ContactUserID: pair[1],
+			})
+			if deleteErr.Exists() {
+				log.Errorf("failed to delete contact %d -> %d: %v", pair[0], pair[1], deleteErr)
+
+				return apperror.NewCode(apperror.Internal, "Failed to remove contact")
+			}
+		}
+
+		return apperror.Error{}
+	})
+}
+
+// SearchUsers prefix-matches names and emails, but only among the caller's contacts and the
+// people they share a group with, so the endpoint cannot be used to enumerate accounts
+func (s *Service) SearchUsers(ctx context.Context, userID uint64, term string) (model.Users, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "ContactService.SearchUsers")
+	defer span.End()
+
+	log := logger.With(ctx, "SearchUsers")
+
+	term = util.TrimSpace(term)
+	if len(term) < minSearchTermLength {
+		return nil, apperror.NewValidation(apperror.FieldError{
+			Field:   constants.SearchTerm,
+			Rule:    "min",
+			Message: fmt.Sprintf("must be at least %d characters long", minSearchTermLength),
+		})
+	}
+
+	candidateIDs, err := s.knownUserIDs(ctx, userID)
+	if err.Exists() {
+		log.Errorf("failed to collect known users of user %d: %v", userID, err)
+		return nil, err
+	}
+
+	if len(candidateIDs) == 0 {
+		return make(model.Users, 0), apperror.Error{}
+	}
+
+	users, err := s.userRepo.Find(ctx, query.New().
+		In(constants.ID, candidateIDs).
+		StartsWithAny(term, constants.Name, constants.Email).
+		OrderBy(constants.Name, false).
+		Limit(maxSearchResults),
+	)
+	if err.Exists() {
+		log.Errorf("failed to search users for user %d: %v", userID, err)
+
+		return nil, apperror.NewCode(apperror.Internal, "Failed to search users")
+	}
+
+	return users, apperror.Error{}
+}
+
+// connect stores both directions of a friendship
+func (s *Service) connect(ctx context.Context, userID, contactUserID uint64) apperror.Error {
+	log := logger.With(ctx, "connect")
+
+	contacts := []*model.Contact{
+		{UserID: userID, ContactUserID: contactUserID},
+		{UserID: contactUserID, ContactUserID: userID},
+	}
+
+	err := s.contactRepo.CreateMany(ctx, contacts)
+	if err.Exists() {
+		log.Errorf("failed to connect users %d and %d: %v", userID, contactUserID, err)
+
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return apperror.NewCode(apperror.ContactAlreadyExists, "User is already a contact")
+		}
+
+		return apperror.NewCode(apperror.Internal, "Failed to add contact")
+	}
+
+	return apperror.Error{}
+}
+
+// inviteContact stores the invite and tells the invitee about it, by email when they have no account yet
+func (s *Service) inviteContact(ctx context.Context, inviter model.User, email string, invitee model.User) apperror.Error {
+	log := logger.With(ctx, "inviteContact")
+
+	invite := model.ContactInvite{InviterID: inviter.ID, Email: email}
+	err := s.contactInviteRepo.Create(ctx, &invite)
+	if err.Exists() {
+		log.Errorf("failed to store invite from user %d: %v", inviter.ID, err)
+
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return apperror.NewCode(apperror.InviteAlreadySent, "An invite was already sent to this email")
+		}
+
+		return apperror.NewCode(apperror.Internal, "Failed to send invite")
+	}
+
+	msg := notify.Message{
+		Kind:    contactInviteKind,
+		UserID:  invitee.ID,
+		Email:   email,
+		Subject: fmt.Sprintf("%s wants to add you as a contact", inviter.Name),
+		Body:    fmt.Sprintf("%s wants to add you as a contact. Accept or decline the invite in your contact invites.", inviter.Name),
+	}
+	if invitee.ID == 0 {
+		msg.Body = fmt.Sprintf("%s invited you to split bills with them. Sign up with this email to accept.", inviter.Name)
+	}
+
+	// the invite is stored and shows up in the invitee's list, a failed notification does not undo it
+	if sendErr := notify.Default().Notify(ctx, msg); sendErr != nil {
+		log.Warnf("failed to notify about invite %d: %v", invite.ID, sendErr)
+	}
+
+	return apperror.Error{}
+}
+
+// getPendingInvite fetches an invite addressed to the caller's email that is not accepted yet
+func (s *Service) getPendingInvite(ctx context.Context, userID, inviteID uint64) (model.ContactInvite, apperror.Error) {
+	log := logger.With(ctx, "getPendingInvite")
+
+	user, err := s.userRepo.Get(ctx, map[string]any{constants.ID: userID})
+	if err.Exists() {
+		log.Errorf("failed to fetch user %d: %v", userID, err)
+		return model.ContactInvite{}, apperror.NewCode(apperror.Internal, "Failed to fetch contact invite")
+	}
+
+	invite, err := s.contactInviteRepo.Get(ctx, map[string]any{
+		constants.ID:         inviteID,
+		constants.Email:      strings.ToLower(user.Email),
+		constants.AcceptedAt: nil,
+	})
+	if errors.Is(err, apperror.NotFound) {
+		return model.ContactInvite{}, apperror.NewCode(apperror.InviteNotFound, "Contact invite not found")
+	}
+	if err.Exists() {
+		log.Errorf("failed to fetch invite %d for user %d: %v", inviteID, userID, err)
+		return model.ContactInvite{}, apperror.NewCode(apperror.Internal, "Failed to fetch contact invite")
+	}
+
+	return invite, apperror.Error{}
+}
+
+// knownUserIDs lists the caller's contacts and everyone sharing at least one group with them
+func (s *Service) knownUserIDs(ctx context.Context, userID uint64) ([]uint64, apperror.Error) {
+	contacts, err := s.contactRepo.GetAll(ctx, map[string]any{constants.UserID: userID})
+	if err.Exists() {
+		return nil, apperror.NewCode(apperror.Internal, "Failed to fetch contacts")
+	}
+
+	memberships, err := s.groupPermissionSvc.FetchUserGroup(ctx, userID)
+	if err.Exists() {
+		return nil, err
+	}
+
+	ids := model.Contacts(contacts).GetContactUserIDs()
+	if groupIDs := memberships.GetUniqueGroupIDs(); len(groupIDs) > 0 {
+		coMembers, err := s.groupPermissionSvc.GetGroupUserPermissionsByFilter(ctx, map[string]any{
+			constants.GroupID:  groupIDs,
+			constants.IsActive: true,
+		})
+		if err.Exists() {
+			return nil, apperror.NewCode(apperror.Internal, "Failed to fetch group members")
+		}
+
+		ids = append(ids, coMembers.GetUniqueUserIDs()...)
+	}
+
+	known := make([]uint64, 0, len(ids))
+	for _, id := range util.DeduplicateSlice(ids) {
+		if id != userID {
+			known = append(known, id)
+		}
+	}
+
+	return known, apperror.Error{}
+}
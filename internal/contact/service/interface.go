@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/repository/query"
+)
+
+type Interface interface {
+	ListContacts(ctx context.Context, userID uint64, q *query.Query) (model.Users, query.Page, apperror.Error)
+	AddContact(ctx context.Context, userID uint64, email string) apperror.Error
+	GetContactInvites(ctx context.Context, userID uint64) (model.ContactInvites, model.Users, apperror.Error)
+	AcceptContactInvite(ctx context.Context, userID, inviteID uint64) apperror.Error
+	DeclineContactInvite(ctx context.Context, userID, inviteID uint64) apperror.Error
+	RemoveContact(ctx context.Context, userID, contactUserID uint64) apperror.Error
+	SearchUsers(ctx context.Context, userID uint64, term string) (model.Users, apperror.Error)
+}
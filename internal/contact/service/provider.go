@@ -0,0 +1,27 @@
+package service
+
+import (
+	"github.com/google/wire"
+	contactRepo "main/internal/contact/repository"
+	contactInviteRepo "main/internal/contact_invite/repository"
+	groupPermissionRepo "main/internal/group_permission/repository"
+	groupPermissionSvc "main/internal/group_permission/service"
+	userRepo "main/internal/user/repository"
+)
+
+var ProviderSet = wire.NewSet(
+	NewService,
+	contactRepo.NewRepository,
+	contactInviteRepo.NewRepository,
+	userRepo.NewRepository,
+	groupPermissionRepo.NewRepository,
+	groupPermissionSvc.NewService,
+
+	// bind each one of the interfaces
+	wire.Bind(new(Interface), new(*Service)),
+	wire.Bind(new(contactRepo.Interface), new(*contactRepo.Repository)),
+	wire.Bind(new(contactInviteRepo.Interface), new(*contactInviteRepo.Repository)),
+	wire.Bind(new(userRepo.Interface), new(*userRepo.Repository)),
+	wire.Bind(new(groupPermissionRepo.Interface), new(*groupPermissionRepo.Repository)),
+	wire.Bind(new(groupPermissionSvc.Interface), new(*groupPermissionSvc.Service)),
+)
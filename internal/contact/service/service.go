@@ -0,0 +1,39 @@
+package service
+
+import (
+	contactRepo "main/internal/contact/repository"
+	contactInviteRepo "main/internal/contact_invite/repository"
+	groupPermissionSvc "main/internal/group_permission/service"
+	userRepo "main/internal/user/repository"
+	"sync"
+)
+
+type Service struct {
+	contactRepo        contactRepo.Interface
+	contactInviteRepo  contactInviteRepo.Interface
+	userRepo           userRepo.Interface
+	groupPermissionSvc groupPermissionSvc.Interface
+}
+
+var (
+	syncOnce sync.Once
+	svc      *Service
+)
+
+func NewService(
+	contactRepo contactRepo.Interface,
+	contactInviteRepo contactInviteRepo.Interface,
+	userRepo userRepo.Interface,
+	groupPermissionSvc groupPermissionSvc.Interface,
+) *Service {
+	syncOnce.Do(func() {
+		svc = &Service{
+			contactRepo:        contactRepo,
+			contactInviteRepo:  contactInviteRepo,
+			userRepo:           userRepo,
+			groupPermissionSvc: groupPermissionSvc,
+		}
+	})
+
+	return svc
+}
@@ -0,0 +1,29 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run -mod=mod github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package service
+
+import (
+	"context"
+	"main/internal/contact/repository"
+	repository2 "main/internal/contact_invite/repository"
+	repository4 "main/internal/group_permission/repository"
+	"main/internal/group_permission/service"
+	repository3 "main/internal/user/repository"
+	"main/pkg/db/postgres"
+)
+
+// Injectors from wire.go:
+
+func Wire(ctx context.Context, db *postgres.DbCluster) *Service {
+	repositoryRepository := repository.NewRepository(db)
+	repository5 := repository2.NewRepository(db)
+	repository6 := repository3.NewRepository(db)
+	repository7 := repository4.NewRepository(db)
+	serviceService := service.NewService(repository7)
+	service2 := NewService(repositoryRepository, repository5, repository6, serviceService)
+	return service2
+}
@@ -0,0 +1,10 @@
+package repository
+
+import (
+	"main/internal/model"
+	"main/repository"
+)
+
+type Interface interface {
+	repository.Interface[model.ContactInvite]
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"main/constants"
+	"main/internal/controller/response"
+	"main/internal/jwt/private"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/repository/query"
+	"net/http"
+)
+
+func (ctrl *Controller) GetGroupActivity(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	// set before parsing so cursors of the default order are checked against it
+	values := ctx.Request.URL.Query()
+	// a feed reads newest first
+	if values.Get(query.SortKey) == "" {
+		values.Set(query.SortKey, "-"+constants.ID)
+	}
+
+	q, parseErr := query.Parse(values, model.Activity{}.FilterColumns())
+	if parseErr != nil {
+		apperror.Wrap(parseErr, apperror.ValidationFailed, parseErr.Error()).AbortWithError(ctx)
+		return
+	}
+
+	activities, page, err := ctrl.groupService.GetGroupActivity(ctx, userID, groupID, q)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.Page[response.Activity]{
+		Items:      activities,
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	})
+}
@@ -0,0 +1,122 @@
+package adapter
+
+import (
+	"fmt"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"main/internal/controller/response"
+	"main/internal/model"
+)
+
+// unknownMember names users that no longer exist
+const unknownMember = "Someone"
+
+var moneyPrinter = message.NewPrinter(language.English)
+
+func BuildActivitiesResponse(activities model.Activities, users model.Users) []response.Activity {
+	idMap := users.MapByID()
+
+	result := make([]response.Activity, 0, len(activities))
+	for _, activity := range activities {
+		item := response.Activity{
+			ID:           activity.ID,
+			Type:         string(activity.Type),
+			Actor:        buildUser(idMap, activity.ActorID),
+			BillID:       activity.Data.BillID,
+			SettlementID: activity.Data.SettlementID,
+			Amount:       activity.Data.Amount,
+			Currency:     activity.Data.Currency,
+			CreatedAt:    activity.CreatedAt,
+		}
+		if activity.Data.UserID != 0 {
+			member := buildUser(idMap, activity.Data.UserID)
+			item.Member = &member
+		}
+		item.Message = describeActivity(activity, item.Actor.Name, item.Member)
+
+		result = append(result, item)
+	}
+
+	return result
+}
+
+func BuildSettlementResponse(settlement model.Settlement, users map[uint64]model.User) response.Settlement {
+	return response.Settlement{
+		ID:        settlement.ID,
+		From:      buildUser(users, settlement.FromUserID),
+		To:        buildUser(users, settlement.ToUserID),
+		Amount:    settlement.Amount,
+		Currency:  settlement.Currency,
+		Note:      settlement.Note,
+		CreatedAt: settlement.CreatedAt,
+	}
+}
+
+func BuildSettlementsResponse(settlements model.Settlements, users model.Users) []response.Settlement {
+	idMap := users.MapByID()
+
+	result := make([]response.Settlement, 0, len(settlements))
+	for _, settlement := range settlements {
+		result = append(result, BuildSettlementResponse(settlement, idMap))
+	}
+
+	return result
+}
+
+func buildUser(users map[uint64]model.User, userID uint64) response.User {
+	user, ok := users[userID]
+	if !ok {
+		return response.User{ID: userID, Name: unknownMember}
+	}
+
+	return response.User{ID: user.ID, Name: user.Name, Email: user.Email}
+}
+
+// describeActivity renders an activity as a sentence such as "Alice added 'Dinner' ₹ 1,200.00"
+func describeActivity(activity model.Activity, actor string, member *response.User) string {
+	data := activity.Data
+	memberName := unknownMember
+	if member != nil {
+		memberName = member.Name
+	}
+
+	switch activity.Type {
+	case model.GroupCreated:
+		return fmt.Sprintf("%s created the group '%s'", actor, data.Name)
+	case model.GroupUpdated:
+		return fmt.Sprintf("%s updated the group '%s'", actor, data.Name)
+	case model.GroupDeleted:
+		return fmt.Sprintf("%s deleted the group '%s'", actor, data.Name)
+	case model.GroupRestored:
+		return fmt.Sprintf("%s restored the group '%s'", actor, data.Name)
+	case model.MemberJoined:
+		return fmt.Sprintf("%s joined", memberName)
+	case model.BillCreated:
+		return fmt.Sprintf("%s added '%s' %s", actor, data.Description, formatMoney(data.Amount, data.Currency))
+	case model.BillUpdated:
+		return fmt.Sprintf("%s updated '%s' to %s", actor, data.Description, formatMoney(data.Amount, data.Currency))
+	case model.BillDeleted:
+		return fmt.Sprintf("%s deleted '%s' %s", actor, data.Description, formatMoney(data.Amount, data.Currency))
+	case model.BillRestored:
+		return fmt.Sprintf("%s restored a deleted bill", actor)
+	case model.BillsImported:
+		return fmt.Sprintf("%s imported %d bills", actor, data.Count)
+	case model.SplitCalculated:
+		return fmt.Sprintf("%s split the bills", actor)
+	case model.SettlementRecorded:
+		return fmt.Sprintf("%s settled %s with %s", actor, formatMoney(data.Amount, data.Currency), memberName)
+	default:
+		return fmt.Sprintf("%s: %s", actor, activity.Type)
+	}
+}
+
+// formatMoney prints an amount with the symbol of its currency, or its code when the currency is unknown
+func formatMoney(amount float64, code string) string {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return fmt.Sprintf("%.2f %s", amount, code)
+	}
+
+	return moneyPrinter.Sprint(currency.NarrowSymbol(unit.Amount(amount)))
+}
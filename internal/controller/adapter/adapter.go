@@ -3,6 +3,8 @@ package adapter
 import (
 	"main/internal/controller/response"
 	"main/internal/model"
+	"main/repository/query"
+	"time"
 )
 
 func BuildAuthTokenResponse(req model.AuthToken) response.AuthTokenResponse {
@@ -48,31 +50,69 @@ func BuildUsersResponse(
 	return result
 }
 
-func BuildGroupDetailsResponse(
-	group model.Group,
-	users model.Users,
-	bills model.Bills,
-) *response.GroupDetails {
+func BuildBillResponse(bill model.Bill, payers map[uint64]model.User) response.Bill {
+	payer := payers[bill.UserID]
+
+	var deletedAt *time.Time
+	if bill.DeletedAt.Valid {
+		deletedAt = &bill.DeletedAt.Time
+	}
+
+	return response.Bill{
+		ID: bill.ID,
+		User: response.User{
+			ID:    payer.ID,
+			Name:  payer.Name,
+			Email: payer.Email,
+		},
+		PaidAmount:  bill.PaidAmount,
+		Description: bill.Description,
+		Category:    bill.Category,
+		Currency:    bill.Currency,
+		Version:     bill.Version,
+		CreatedAt:   bill.CreatedAt,
+		DeletedAt:   deletedAt,
+	}
+}
+
+func BuildBillsResponse(bills model.Bills, users model.Users) response.Bills {
 	idMap := users.MapByID()
-	responseBills := make([]response.Bill, 0, len(bills))
 
+	result := make(response.Bills, 0, len(bills))
 	for _, bill := range bills {
-		payer := idMap[bill.UserID]
-		responseBills = append(responseBills, response.Bill{
-			User: response.User{
-				ID:    payer.ID,
-				Name:  payer.Name,
-				Email: payer.Email,
-			},
-			PaidAmount:  bill.PaidAmount,
-			Description: bill.Description,
+		result = append(result, BuildBillResponse(bill, idMap))
+	}
+
+	return result
+}
+
+func BuildDeletedGroupsResponse(groups model.Groups) []response.DeletedGroup {
+	result := make([]response.DeletedGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, response.DeletedGroup{
+			ID:          group.ID,
+			Name:        group.Name,
+			Description: group.Description,
+			DeletedAt:   group.DeletedAt.Time,
 		})
 	}
 
+	return result
+}
+
+func BuildGroupDetailsResponse(
+	group model.Group,
+	users model.Users,
+	bills model.Bills,
+	page query.Page,
+) *response.GroupDetails {
 	return &response.GroupDetails{
 		ID:          group.ID,
 		Name:        group.Name,
 		Description: group.Description,
-		Bills:       responseBills,
+		Version:     group.Version,
+		Bills:       BuildBillsResponse(bills, users),
+		NextCursor:  page.NextCursor,
+		TotalBills:  page.Total,
 	}
 }
@@ -0,0 +1,36 @@
+package adapter
+
+import (
+	"main/internal/controller/response"
+	"main/internal/model"
+)
+
+// BuildUserSummariesResponse leaves the emails out, for users found by search rather than added as contacts
+func BuildUserSummariesResponse(users model.Users) response.Users {
+	result := make(response.Users, 0, len(users))
+	for _, user := range users {
+		result = append(result, response.User{ID: user.ID, Name: user.Name})
+	}
+
+	return result
+}
+
+func BuildContactInvitesResponse(invites model.ContactInvites, inviters model.Users) []response.ContactInvite {
+	byID := inviters.MapByID()
+
+	result := make([]response.ContactInvite, 0, len(invites))
+	for _, invite := range invites {
+		inviter, ok := byID[invite.InviterID]
+		if !ok {
+			inviter = model.User{ID: invite.InviterID, Name: unknownMember}
+		}
+
+		result = append(result, response.ContactInvite{
+			ID:        invite.ID,
+			Inviter:   response.User{ID: inviter.ID, Name: inviter.Name},
+			CreatedAt: invite.CreatedAt,
+		})
+	}
+
+	return result
+}
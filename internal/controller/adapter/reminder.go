@@ -0,0 +1,29 @@
+package adapter
+
+import (
+	"main/internal/controller/response"
+	"main/internal/model"
+)
+
+// BuildReminderSettingResponse renders the settings of a group, a setting that was never stored has no updated_at
+func BuildReminderSettingResponse(setting model.ReminderSetting) response.ReminderSetting {
+	result := response.ReminderSetting{
+		Enabled:      setting.Enabled,
+		IntervalDays: setting.IntervalDays,
+	}
+	if !setting.UpdatedAt.IsZero() {
+		result.UpdatedAt = &setting.UpdatedAt
+	}
+
+	return result
+}
+
+func BuildPaymentReminderResponse(reminder model.PaymentReminder) response.PaymentReminder {
+	return response.PaymentReminder{
+		UserID:         reminder.UserID,
+		ToPayUserID:    reminder.ToPayUserID,
+		LastRemindedAt: reminder.LastRemindedAt,
+		SnoozedUntil:   reminder.SnoozedUntil,
+		LastNudgedAt:   reminder.LastNudgedAt,
+	}
+}
@@ -0,0 +1,366 @@
+package adapter
+
+import (
+	"cmp"
+	"fmt"
+	"main/internal/controller/response"
+	"main/internal/model"
+	"main/pkg/document"
+	"slices"
+	"strings"
+	"time"
+)
+
+func BuildGroupStatementResponse(statement model.Statement) response.GroupStatement {
+	data := statement.Data
+	names := data.MemberNames()
+
+	currencies := make([]response.GroupStatementCurrency, 0, len(data.Currencies))
+	for _, currency := range data.Currencies {
+		members := make([]response.GroupStatementMember, 0, len(currency.Members))
+		for _, member := range currency.Members {
+			members = append(members, response.GroupStatementMember{
+				User:            buildStatementUser(member.UserID, member.Name),
+				StatementTotals: buildStatementTotals(member),
+				Categories:      buildStatementCategories(member.Categories),
+			})
+		}
+
+		currencies = append(currencies, response.GroupStatementCurrency{
+			Currency:   currency.Currency,
+			TotalSpent: currency.TotalSpent,
+			Members:    members,
+			Categories: buildStatementCategories(currency.Categories),
+		})
+	}
+
+	settlements := make([]response.StatementSettlement, 0, len(data.Settlements))
+	for _, settlement := range data.Settlements {
+		settlements = append(settlements, buildStatementSettlement(settlement, names, nil))
+	}
+
+	return response.GroupStatement{
+		Group:       response.StatementGroup{ID: statement.GroupID, Name: data.GroupName},
+		Month:       statement.Month,
+		From:        data.From,
+		To:          data.To,
+		GeneratedAt: statement.CreatedAt,
+		Currencies:  currencies,
+		Settlements: settlements,
+	}
+}
+
+// BuildUserStatementResponse adds up the lines of user in the statements of their groups for one month, currency by
+// currency
+func BuildUserStatementResponse(user model.User, month time.Time, statements model.Statements) response.UserStatement {
+	from, to := model.StatementPeriod(month)
+	result := response.UserStatement{
+		User:        buildStatementUser(user.ID, user.Name),
+		Month:       from.Format(model.StatementMonthLayout),
+		From:        from,
+		To:          to,
+		Settlements: make([]response.StatementSettlement, 0),
+	}
+
+	currencies := make(map[string]*response.UserStatementCurrency)
+	categories := make(map[string]map[string]*response.StatementCategory)
+	for _, statement := range statements {
+		group := response.StatementGroup{ID: statement.GroupID, Name: statement.Data.GroupName}
+
+		for _, currency := range statement.Data.Currencies {
+			member, ok := currency.Member(user.ID)
+			if !ok {
+				continue
+			}
+
+			sum, ok := currencies[currency.Currency]
+			if !ok {
+				sum = &response.UserStatementCurrency{Currency: currency.Currency, Groups: make([]response.UserStatementGroup, 0)}
+				currencies[currency.Currency] = sum
+				categories[currency.Currency] = make(map[string]*response.StatementCategory)
+			}
+
+			totals := buildStatementTotals(member)
+			sum.Paid += totals.Paid
+			sum.Bills += totals.Bills
+			sum.SettledOut += totals.SettledOut
+			sum.SettledIn += totals.SettledIn
+			sum.OpeningBalance += totals.OpeningBalance
+			sum.ClosingBalance += totals.ClosingBalance
+			sum.Groups = append(sum.Groups, response.UserStatementGroup{Group: group, StatementTotals: totals})
+
+			for _, category := range member.Categories {
+				byName := categories[currency.Currency]
+				if _, ok := byName[category.Category]; !ok {
+					byName[category.Category] = &response.StatementCategory{Category: category.Category}
+				}
+				byName[category.Category].Total += category.Total
+				byName[category.Category].Bills += category.Bills
+			}
+		}
+
+		names := statement.Data.MemberNames()
+		for _, settlement := range statement.Data.Settlements {
+			if settlement.FromUserID == user.ID || settlement.ToUserID == user.ID {
+				result.Settlements = append(result.Settlements, buildStatementSettlement(settlement, names, &group))
+			}
+		}
+	}
+
+	result.Currencies = make([]response.UserStatementCurrency, 0, len(currencies))
+	for code, sum := range currencies {
+		sum.Categories = make([]response.StatementCategory, 0, len(categories[code]))
+		for _, category := range categories[code] {
+			sum.Categories = append(sum.Categories, *category)
+		}
+		slices.SortFunc(sum.Categories, func(a, b response.StatementCategory) int {
+			return cmp.Or(cmp.Compare(b.Total, a.Total), cmp.Compare(a.Category, b.Category))
+		})
+		result.Currencies = append(result.Currencies, *sum)
+	}
+	slices.SortFunc(result.Currencies, func(a, b response.UserStatementCurrency) int {
+		return cmp.Compare(a.Currency, b.Currency)
+	})
+	slices.SortFunc(result.Settlements, func(a, b response.StatementSettlement) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return result
+}
+
+func buildStatementTotals(member model.StatementMember) response.StatementTotals {
+	return response.StatementTotals{
+		Paid:           member.Paid,
+		Bills:          member.Bills,
+		SettledOut:     member.SettledOut,
+		SettledIn:      member.SettledIn,
+		OpeningBalance: member.OpeningBalance,
+		ClosingBalance: member.ClosingBalance,
+	}
+}
+
+func buildStatementCategories(categories []model.StatementCategory) []response.StatementCategory {
+	result := make([]response.StatementCategory, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, response.StatementCategory{
+			Category: category.Category,
+			Total:    category.Total,
+			Bills:    category.Bills,
+		})
+	}
+
+	return result
+}
+
+func buildStatementSettlement(
+	settlement model.StatementSettlement,
+	names map[uint64]string,
+	group *response.StatementGroup,
+) response.StatementSettlement {
+	return response.StatementSettlement{
+		ID:        settlement.ID,
+		Group:     group,
+		From:      buildStatementUser(settlement.FromUserID, names[settlement.FromUserID]),
+		To:        buildStatementUser(settlement.ToUserID, names[settlement.ToUserID]),
+		Amount:    settlement.Amount,
+		Currency:  settlement.Currency,
+		Note:      settlement.Note,
+		CreatedAt: settlement.CreatedAt,
+	}
+}
+
+func buildStatementUser(userID uint64, name string) response.StatementUser {
+	if name == "" {
+		name = unknownMember
+	}
+
+	return response.StatementUser{ID: userID, Name: name}
+}
+
+// uncategorized names the bills that were given no category
+const uncategorized = "Uncategorized"
+
+var (
+	categoryColumns = []document.Column{
+		{Title: "Category"},
+		{Title: "Currency"},
+		{Title: "Bills", Numeric: true},
+		{Title: "Total", Numeric: true},
+	}
+	settlementColumns = []document.Column{{Title: "Date"}, {Title: "From"}, {Title: "To"}, {Title: "Amount", Numeric: true}}
+	totalsColumns     = []document.Column{
+		{Title: "Bills", Numeric: true},
+		{Title: "Paid", Numeric: true},
+		{Title: "Settled out", Numeric: true},
+		{Title: "Settled in", Numeric: true},
+		{Title: "Opening", Numeric: true},
+		{Title: "Closing", Numeric: true},
+	}
+)
+
+// BuildGroupStatementDocument lays out a group statement for the HTML and PDF renderings
+func BuildGroupStatementDocument(statement response.GroupStatement) document.Document {
+	members := document.Table{
+		Title:   "Members",
+		Columns: append([]document.Column{{Title: "Member"}, {Title: "Currency"}}, totalsColumns...),
+		Empty:   "No activity this month.",
+	}
+	categories := categoriesTable()
+	totalSpent := make([]currencyAmount, 0, len(statement.Currencies))
+	memberIDs := make(map[uint64]struct{})
+	for _, currency := range statement.Currencies {
+		totalSpent = append(totalSpent, currencyAmount{currency.Currency, currency.TotalSpent})
+		for _, member := range currency.Members {
+			memberIDs[member.User.ID] = struct{}{}
+			members.Rows = append(members.Rows,
+				append([]string{member.User.Name, currency.Currency}, totalsRow(member.StatementTotals)...))
+		}
+		addCategoryRows(&categories, currency.Currency, currency.Categories)
+	}
+
+	return document.Document{
+		Title:    fmt.Sprintf("%s statement", statement.Group.Name),
+		Subtitle: statementSubtitle(statement.Month, statement.From, statement.To),
+		Summary: []document.Field{
+			{Label: "Total spent", Value: formatAmounts(totalSpent)},
+			{Label: "Members", Value: fmt.Sprint(len(memberIDs))},
+			{Label: "Settlements", Value: fmt.Sprint(len(statement.Settlements))},
+			{Label: "Generated", Value: statement.GeneratedAt.UTC().Format(time.RFC1123)},
+		},
+		Tables: []document.Table{
+			members,
+			categories,
+			settlementsTable(statement.Settlements, false),
+		},
+	}
+}
+
+// BuildUserStatementDocument lays out a user statement for the HTML and PDF renderings
+func BuildUserStatementDocument(statement response.UserStatement) document.Document {
+	groups := document.Table{
+		Title:   "Groups",
+		Columns: append([]document.Column{{Title: "Group"}, {Title: "Currency"}}, totalsColumns...),
+		Empty:   "No groups this month.",
+	}
+	categories := categoriesTable()
+	var paid, settledOut, settledIn, opening, closing []currencyAmount
+	var bills int
+	for _, currency := range statement.Currencies {
+		code := currency.Currency
+		paid = append(paid, currencyAmount{code, currency.Paid})
+		settledOut = append(settledOut, currencyAmount{code, currency.SettledOut})
+		settledIn = append(settledIn, currencyAmount{code, currency.SettledIn})
+		opening = append(opening, currencyAmount{code, currency.OpeningBalance})
+		closing = append(closing, currencyAmount{code, currency.ClosingBalance})
+		bills += currency.Bills
+
+		for _, group := range currency.Groups {
+			groups.Rows = append(groups.Rows, append([]string{group.Group.Name, code}, totalsRow(group.StatementTotals)...))
+		}
+		addCategoryRows(&categories, code, currency.Categories)
+	}
+
+	return document.Document{
+		Title:    fmt.Sprintf("Statement of %s", statement.User.Name),
+		Subtitle: statementSubtitle(statement.Month, statement.From, statement.To),
+		Summary: []document.Field{
+			{Label: "Paid", Value: formatAmounts(paid)},
+			{Label: "Bills", Value: fmt.Sprint(bills)},
+			{Label: "Settled out", Value: formatAmounts(settledOut)},
+			{Label: "Settled in", Value: formatAmounts(settledIn)},
+			{Label: "Opening balance", Value: formatAmounts(opening)},
+			{Label: "Closing balance", Value: formatAmounts(closing)},
+		},
+		Tables: []document.Table{
+			groups,
+			categories,
+			settlementsTable(statement.Settlements, true),
+		},
+	}
+}
+
+func categoriesTable() document.Table {
+	return document.Table{Title: "Categories", Columns: categoryColumns, Empty: "No bills this month."}
+}
+
+func addCategoryRows(table *document.Table, currency string, categories []response.StatementCategory) {
+	for _, category := range categories {
+		name := category.Category
+		if name == "" {
+			name = uncategorized
+		}
+		table.Rows = append(table.Rows, []string{name, currency, fmt.Sprint(category.Bills), formatAmount(category.Total)})
+	}
+}
+
+func settlementsTable(settlements []response.StatementSettlement, withGroup bool) document.Table {
+	table := document.Table{Title: "Settlements", Columns: settlementColumns, Empty: "No settlements this month."}
+	if withGroup {
+		table.Columns = append([]document.Column{{Title: "Group"}}, settlementColumns...)
+	}
+
+	for _, settlement := range settlements {
+		row := []string{
+			settlement.CreatedAt.UTC().Format(time.DateOnly),
+			settlement.From.Name,
+			settlement.To.Name,
+			strings.TrimSpace(formatAmount(settlement.Amount) + " " + settlement.Currency),
+		}
+		if withGroup {
+			group := ""
+			if settlement.Group != nil {
+				group = settlement.Group.Name
+			}
+			row = append([]string{group}, row...)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table
+}
+
+func totalsRow(totals response.StatementTotals) []string {
+	return []string{
+		fmt.Sprint(totals.Bills),
+		formatAmount(totals.Paid),
+		formatAmount(totals.SettledOut),
+		formatAmount(totals.SettledIn),
+		formatAmount(totals.OpeningBalance),
+		formatAmount(totals.ClosingBalance),
+	}
+}
+
+// statementSubtitle names the month and its last day, to is the first instant after it
+func statementSubtitle(month string, from, to time.Time) string {
+	return fmt.Sprintf("%s, %s to %s (UTC). Balances are what a member is owed, negative when they owe.",
+		month, from.Format(time.DateOnly), to.AddDate(0, 0, -1).Format(time.DateOnly))
+}
+
+// currencyAmount is one figure of a statement that is kept per currency
+type currencyAmount struct {
+	currency string
+	amount   float64
+}
+
+// formatAmounts lists a figure in each currency, amounts in different currencies are not added up
+func formatAmounts(amounts []currencyAmount) string {
+	if len(amounts) == 0 {
+		return formatAmount(0)
+	}
+
+	parts := make([]string, 0, len(amounts))
+	for _, amount := range amounts {
+		parts = append(parts, strings.TrimSpace(formatAmount(amount.amount)+" "+amount.currency))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func formatAmount(amount float64) string {
+	// keeps -0.00 from showing up for balances that are settled to the cent
+	if amount > -0.005 && amount < 0.005 {
+		amount = 0
+	}
+
+	return fmt.Sprintf("%.2f", amount)
+}
@@ -0,0 +1,60 @@
+package adapter
+
+import (
+	"main/internal/controller/response"
+	"main/internal/model"
+)
+
+func BuildWebhookResponse(webhook model.Webhook) response.Webhook {
+	events := make([]string, 0, len(webhook.Events))
+	for _, event := range webhook.Events {
+		events = append(events, string(event))
+	}
+
+	return response.Webhook{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    events,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+func BuildWebhooksResponse(webhooks model.Webhooks) []response.Webhook {
+	result := make([]response.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		result = append(result, BuildWebhookResponse(webhook))
+	}
+
+	return result
+}
+
+func BuildWebhookDeliveryResponse(delivery model.WebhookDelivery) response.WebhookDelivery {
+	item := response.WebhookDelivery{
+		ID:          delivery.ID,
+		WebhookID:   delivery.WebhookID,
+		Event:       string(delivery.Event),
+		Status:      string(delivery.Status),
+		Attempts:    delivery.Attempts,
+		StatusCode:  delivery.StatusCode,
+		LastError:   delivery.LastError,
+		DeliveredAt: delivery.DeliveredAt,
+		Payload:     delivery.Payload,
+		CreatedAt:   delivery.CreatedAt,
+	}
+
+	// a finished delivery is not attempted again
+	if delivery.Status == model.DeliveryPending {
+		item.NextAttemptAt = &delivery.NextAttemptAt
+	}
+
+	return item
+}
+
+func BuildWebhookDeliveriesResponse(deliveries model.WebhookDeliveries) []response.WebhookDelivery {
+	result := make([]response.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		result = append(result, BuildWebhookDeliveryResponse(delivery))
+	}
+
+	return result
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"main/constants"
+	"main/internal/controller/adapter"
+	"main/internal/controller/request"
+	"main/internal/controller/response"
+	"main/internal/jwt/private"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/repository/query"
+	"net/http"
+)
+
+func (ctrl *Controller) ListContacts(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	q, parseErr := query.Parse(ctx.Request.URL.Query(), model.User{}.FilterColumns())
+	if parseErr != nil {
+		apperror.Wrap(parseErr, apperror.ValidationFailed, parseErr.Error()).AbortWithError(ctx)
+		return
+	}
+
+	users, page, err := ctrl.contactSvc.ListContacts(ctx, userID, q)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.Page[response.User]{
+		Items:      adapter.BuildUsersResponse(users),
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	})
+}
+
+func (ctrl *Controller) AddContact(ctx *gin.Context) {
+	var req request.AddContactRequest
+	if err := ctx.ShouldBindBodyWithJSON(&req); err != nil {
+		apperror.Validation(err).AbortWithError(ctx)
+		return
+	}
+
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	if err = ctrl.contactSvc.AddContact(ctx, userID, req.Email); err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	// registered or not, the invitee has to accept first
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "Invite sent"})
+}
+
+func (ctrl *Controller) GetContactInvites(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	invites, inviters, err := ctrl.contactSvc.GetContactInvites(ctx, userID)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, adapter.BuildContactInvitesResponse(invites, inviters))
+}
+
+func (ctrl *Controller) AcceptContactInvite(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	inviteID, ok := pathID(ctx, constants.InviteID)
+	if !ok {
+		return
+	}
+
+	if err = ctrl.contactSvc.AcceptContactInvite(ctx, userID, inviteID); err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Contact added"})
+}
+
+func (ctrl *Controller) DeclineContactInvite(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	inviteID, ok := pathID(ctx, constants.InviteID)
+	if !ok {
+		return
+	}
+
+	if err = ctrl.contactSvc.DeclineContactInvite(ctx, userID, inviteID); err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Invite declined"})
+}
+
+func (ctrl *Controller) RemoveContact(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	contactUserID, ok := pathID(ctx, constants.UserID)
+	if !ok {
+		return
+	}
+
+	if err = ctrl.contactSvc.RemoveContact(ctx, userID, contactUserID); err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Contact removed"})
+}
+
+func (ctrl *Controller) SearchUsers(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	users, err := ctrl.contactSvc.SearchUsers(ctx, userID, ctx.Query(constants.SearchTerm))
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, adapter.BuildUserSummariesResponse(users))
+}
@@ -2,15 +2,23 @@ package controller
 
 import (
 	billSplitSvc "main/internal/bill_split/service"
+	contactService "main/internal/contact/service"
 	groupService "main/internal/group/service"
+	reminderService "main/internal/reminder/service"
+	settlementService "main/internal/settlement/service"
+	statementService "main/internal/statement/service"
 	userService "main/internal/user/service"
 	"sync"
 )
 
 type Controller struct {
-	userSvc      userService.Interface
-	groupService groupService.Interface
-	billSplitSvc billSplitSvc.Interface
+	userSvc       userService.Interface
+	groupService  groupService.Interface
+	billSplitSvc  billSplitSvc.Interface
+	contactSvc    contactService.Interface
+	settlementSvc settlementService.Interface
+	reminderSvc   reminderService.Interface
+	statementSvc  statementService.Interface
 }
 
 var (
@@ -22,12 +30,20 @@ func NewController(
 	userSvc userService.Interface,
 	groupService groupService.Interface,
 	billSplitSvc billSplitSvc.Interface,
+	contactSvc contactService.Interface,
+	settlementSvc settlementService.Interface,
+	reminderSvc reminderService.Interface,
+	statementSvc statementService.Interface,
 ) *Controller {
 	syncOnce.Do(func() {
 		ctrl = &Controller{
-			userSvc:      userSvc,
-			groupService: groupService,
-			billSplitSvc: billSplitSvc,
+			userSvc:       userSvc,
+			groupService:  groupService,
+			billSplitSvc:  billSplitSvc,
+			contactSvc:    contactSvc,
+			settlementSvc: settlementSvc,
+			reminderSvc:   reminderSvc,
+			statementSvc:  statementSvc,
 		}
 	})
 
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"main/pkg/apperror"
+	"strconv"
+	"strings"
+)
+
+const (
+	etagHeader    = "ETag"
+	ifMatchHeader = "If-Match"
+)
+
+// etag renders the row version of a bill or group as a strong entity tag
+func etag(version uint64) string {
+	return `"` + strconv.FormatUint(version, 10) + `"`
+}
+
+// ifMatchVersion reads the version an update was based on from If-Match. Updates without the header are refused
+// with 428 so that nobody overwrites changes they have not seen; "*" opts out of the check and yields zero.
+func ifMatchVersion(ctx *gin.Context) (uint64, apperror.Error) {
+	value := strings.TrimSpace(ctx.GetHeader(ifMatchHeader))
+	if value == "" {
+		return 0, apperror.NewCode(apperror.PreconditionRequired, "If-Match header with the ETag of the resource is required")
+	}
+
+	if value == "*" {
+		return 0, apperror.Error{}
+	}
+
+	version, err := strconv.ParseUint(strings.Trim(value, `"`), 10, 64)
+	if err != nil || version == 0 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		// weak tags and lists never match the strong tag of the current version
+		return 0, apperror.NewCode(apperror.VersionConflict, "If-Match does not match the current ETag")
+	}
+
+	return version, apperror.Error{}
+}
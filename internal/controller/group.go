@@ -1,32 +1,41 @@
 package controller
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
-	"log"
 	"main/constants"
 	"main/internal/controller/adapter"
 	"main/internal/controller/request"
+	"main/internal/controller/response"
 	"main/internal/jwt/private"
-	"main/util"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/logger"
+	"main/repository/query"
 	"net/http"
-	"strconv"
 )
 
+// maxImportFileSize caps CSV uploads for bill imports at 5 MB
+const maxImportFileSize = 5 << 20
+
+// maxImportBodySize leaves room for the multipart framing around the CSV file
+const maxImportBodySize = maxImportFileSize + 64<<10
+
 func (ctrl *Controller) CreateGroup(ctx *gin.Context) {
 	var req request.CreateGroupRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		apperror.Validation(err).AbortWithError(ctx)
 		return
 	}
 
 	userID, err := private.GetUserID(ctx)
 	if err.Exists() {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
 	if svcErr := ctrl.groupService.CreateGroup(ctx, userID, req); svcErr.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Error()})
+		svcErr.AbortWithError(ctx)
 		return
 	}
 
@@ -36,45 +45,52 @@ func (ctrl *Controller) CreateGroup(ctx *gin.Context) {
 func (ctrl *Controller) UpdateGroup(ctx *gin.Context) {
 	var req request.UpdateGroupRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		apperror.Validation(err).AbortWithError(ctx)
 		return
 	}
 
 	userID, err := private.GetUserID(ctx)
 	if err.Exists() {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
-	groupID, parseErr := util.ParseUint(ctx.Param(constants.GroupID))
-	if parseErr != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	version, err := ifMatchVersion(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
 		return
 	}
 
-	if svcErr := ctrl.groupService.UpdateGroup(ctx, userID, groupID, req); svcErr.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Error()})
+	if svcErr := ctrl.groupService.UpdateGroup(ctx, userID, groupID, version, req); svcErr.Exists() {
+		svcErr.AbortWithError(ctx)
 		return
 	}
 
+	if version > 0 {
+		ctx.Header(etagHeader, etag(version+1))
+	}
 	ctx.JSON(http.StatusOK, gin.H{"message": "Group updated successfully"})
 }
 
 func (ctrl *Controller) RemoveGroup(ctx *gin.Context) {
 	userID, err := private.GetUserID(ctx)
 	if err.Exists() {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
-	groupID, parseErr := util.ParseUint(ctx.Param(constants.GroupID))
-	if parseErr != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
 		return
 	}
 
 	if svcErr := ctrl.groupService.RemoveGroup(ctx, userID, groupID); svcErr.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Error()})
+		svcErr.AbortWithError(ctx)
 		return
 	}
 
@@ -84,70 +100,110 @@ func (ctrl *Controller) RemoveGroup(ctx *gin.Context) {
 func (ctrl *Controller) GetUserGroups(ctx *gin.Context) {
 	userID, err := private.GetUserID(ctx)
 	if err.Exists() {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
-	// later add filter
-	groups, groupPermissions, err := ctrl.groupService.GetUserGroupsWithPermissions(ctx, userID, make(map[string]any))
+	q, parseErr := query.Parse(ctx.Request.URL.Query(), model.Group{}.FilterColumns())
+	if parseErr != nil {
+		apperror.Wrap(parseErr, apperror.ValidationFailed, parseErr.Error()).AbortWithError(ctx)
+		return
+	}
+
+	groups, groupPermissions, page, err := ctrl.groupService.GetUserGroupsWithPermissions(ctx, userID, q)
 	if err.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, adapter.BuildGroupPermissionsResponse(groups, groupPermissions))
+	ctx.JSON(http.StatusOK, response.Page[response.GroupPermissionResponse]{
+		Items:      adapter.BuildGroupPermissionsResponse(groups, groupPermissions),
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	})
 }
 
 func (ctrl *Controller) GetGroupDetails(ctx *gin.Context) {
 	userID, err := private.GetUserID(ctx)
 	if err.Exists() {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
-	groupID, convErr := strconv.ParseUint(ctx.Param(constants.GroupID), 10, 64)
-	if convErr != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	q, parseErr := query.Parse(ctx.Request.URL.Query(), model.Bill{}.FilterColumns())
+	if parseErr != nil {
+		apperror.Wrap(parseErr, apperror.ValidationFailed, parseErr.Error()).AbortWithError(ctx)
 		return
 	}
 
 	// add all bills, split bills etc
-	group, err := ctrl.groupService.FetchGroupDetailsByUserAccess(ctx, userID, groupID)
+	group, err := ctrl.groupService.FetchGroupDetailsByUserAccess(ctx, userID, groupID, q)
 	if err.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
+	ctx.Header(etagHeader, etag(group.Version))
 	ctx.JSON(http.StatusCreated, group)
 }
 
+func (ctrl *Controller) GetGroupBill(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	billID, ok := pathID(ctx, constants.BillID)
+	if !ok {
+		return
+	}
+
+	bill, err := ctrl.groupService.GetGroupBill(ctx, userID, groupID, billID)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.Header(etagHeader, etag(bill.Version))
+	ctx.JSON(http.StatusOK, bill)
+}
+
 func (ctrl *Controller) CreateGroupBillForUser(ctx *gin.Context) {
 	currentUserID, err := private.GetUserID(ctx)
 	if err.Exists() {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
-	userID, convErr := strconv.ParseUint(ctx.Param(constants.UserID), 10, 64)
-	if convErr != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	userID, ok := pathID(ctx, constants.UserID)
+	if !ok {
 		return
 	}
 
-	groupID, convErr := strconv.ParseUint(ctx.Param(constants.GroupID), 10, 64)
-	if convErr != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
 		return
 	}
 
 	var req request.CreateBillRequest
 	if bindErr := ctx.ShouldBindJSON(&req); bindErr != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		apperror.Validation(bindErr).AbortWithError(ctx)
 		return
 	}
 
 	if err = ctrl.groupService.CreateGroupBill(ctx, currentUserID, userID, groupID, req); err.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
@@ -155,37 +211,29 @@ func (ctrl *Controller) CreateGroupBillForUser(ctx *gin.Context) {
 }
 
 func (ctrl *Controller) AssignUserToGroup(ctx *gin.Context) {
-	logTag := util.LogPrefix(ctx, "AssignUserToGroup")
+	log := logger.With(ctx, "AssignUserToGroup")
 
 	currentUserID, err := private.GetUserID(ctx)
 	if err.Exists() {
-		log.Printf("%s failed to extract current user ID: %v", logTag, err)
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized user"})
+		log.Errorf("failed to extract current user ID: %v", err)
+		err.AbortWithError(ctx)
 		return
 	}
 
-	groupIDStr := ctx.Param(constants.GroupID)
-	userIDStr := ctx.Param(constants.UserID)
-
-	groupID, parseErr := strconv.ParseUint(groupIDStr, 10, 64)
-	if parseErr != nil {
-		log.Printf("%s invalid group ID: %s", logTag, groupIDStr)
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
 		return
 	}
 
-	userID, parseErr := strconv.ParseUint(userIDStr, 10, 64)
-	if parseErr != nil {
-		log.Printf("%s invalid user ID: %s", logTag, userIDStr)
-
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	userID, ok := pathID(ctx, constants.UserID)
+	if !ok {
 		return
 	}
 
 	err = ctrl.groupService.AssignUserToGroup(ctx, currentUserID, userID, groupID)
 	if err.Exists() {
-		log.Printf("%s failed to assign user to group: %v", logTag, err)
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		log.Errorf("failed to assign user to group: %v", err)
+		err.AbortWithError(ctx)
 		return
 	}
 
@@ -195,79 +243,147 @@ func (ctrl *Controller) AssignUserToGroup(ctx *gin.Context) {
 func (ctrl *Controller) UpdateGroupBill(ctx *gin.Context) {
 	userID, err := private.GetUserID(ctx)
 	if err.Exists() {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
-	groupID, convErr := strconv.ParseUint(ctx.Param(constants.GroupID), 10, 64)
-	if convErr != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
 		return
 	}
 
-	billID, convErr := strconv.ParseUint(ctx.Param(constants.BillID), 10, 64)
-	if convErr != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bill ID"})
+	billID, ok := pathID(ctx, constants.BillID)
+	if !ok {
 		return
 	}
 
 	var req request.UpdateBillRequest
 	if bindErr := ctx.ShouldBindJSON(&req); bindErr != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		apperror.Validation(bindErr).AbortWithError(ctx)
 		return
 	}
 
-	if err = ctrl.groupService.UpdateGroupBill(ctx, userID, groupID, billID, req); err.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	version, err := ifMatchVersion(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
 		return
 	}
 
+	if err = ctrl.groupService.UpdateGroupBill(ctx, userID, groupID, billID, version, req); err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	if version > 0 {
+		ctx.Header(etagHeader, etag(version+1))
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{"message": "Bill updated successfully"})
 }
 
 func (ctrl *Controller) DeleteGroupBill(ctx *gin.Context) {
 	userID, err := private.GetUserID(ctx)
 	if err.Exists() {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
-	groupID, convErr := strconv.ParseUint(ctx.Param(constants.GroupID), 10, 64)
-	if convErr != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
 		return
 	}
 
-	billID, convErr := strconv.ParseUint(ctx.Param(constants.BillID), 10, 64)
-	if convErr != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bill ID"})
+	billID, ok := pathID(ctx, constants.BillID)
+	if !ok {
 		return
 	}
 
 	if err = ctrl.groupService.DeleteGroupBill(ctx, userID, groupID, billID); err.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "Bill deleted successfully"})
 }
 
+func (ctrl *Controller) ImportGroupBills(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	// the body is capped before the multipart form is parsed, so an oversized upload is never read in full
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportBodySize)
+
+	fileHeader, fileErr := ctx.FormFile(constants.File)
+	var tooLarge *http.MaxBytesError
+	if errors.As(fileErr, &tooLarge) {
+		apperror.NewCode(apperror.PayloadTooLarge, "CSV file is too large").AbortWithError(ctx)
+		return
+	}
+	if fileErr != nil {
+		apperror.NewValidation(apperror.FieldError{
+			Field:   constants.File,
+			Rule:    "required",
+			Message: "CSV file is required",
+		}).AbortWithError(ctx)
+		return
+	}
+
+	if fileHeader.Size > maxImportFileSize {
+		apperror.NewCode(apperror.PayloadTooLarge, "CSV file is too large").AbortWithError(ctx)
+		return
+	}
+
+	file, openErr := fileHeader.Open()
+	if openErr != nil {
+		apperror.Wrap(openErr, apperror.InvalidCSV, "Unable to read CSV file").AbortWithError(ctx)
+		return
+	}
+	defer file.Close()
+
+	dryRun := ctx.DefaultQuery(constants.DryRun, "true") != "false"
+
+	preview, err := ctrl.groupService.ImportGroupBills(ctx, userID, groupID, file, dryRun)
+	if err.Exists() {
+		// rejected rows are reported along with the error
+		if preview != nil {
+			err = err.With("preview", preview)
+		}
+
+		err.AbortWithError(ctx)
+		return
+	}
+
+	if preview.Imported {
+		ctx.JSON(http.StatusCreated, preview)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preview)
+}
+
 func (ctrl *Controller) CalculateBillSplits(ctx *gin.Context) {
 	userID, err := private.GetUserID(ctx)
 	if err.Exists() {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
-	groupID, convErr := strconv.ParseUint(ctx.Param(constants.GroupID), 10, 64)
-	if convErr != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group_id"})
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
 		return
 	}
 
 	splits, err := ctrl.billSplitSvc.CalculateAndSaveBillSplits(ctx, userID, groupID)
 	if err.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
@@ -277,19 +393,18 @@ func (ctrl *Controller) CalculateBillSplits(ctx *gin.Context) {
 func (ctrl *Controller) RecalculateBillSplits(ctx *gin.Context) {
 	userID, err := private.GetUserID(ctx)
 	if err.Exists() {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
-	groupID, convErr := strconv.ParseUint(ctx.Param(constants.GroupID), 10, 64)
-	if convErr != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group_id"})
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
 		return
 	}
 
 	splits, err := ctrl.billSplitSvc.RecalculateBillSplits(ctx, userID, groupID)
 	if err.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
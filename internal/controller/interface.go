@@ -8,10 +8,17 @@ type Interface interface {
 	LoginUser(ctx *gin.Context)
 	RegisterUser(ctx *gin.Context)
 	UpdateUserProfile(ctx *gin.Context)
-	GetUsers(ctx *gin.Context)
 	SendActivationEmail(ctx *gin.Context)
 	ActivateUser(ctx *gin.Context)
 
+	ListContacts(ctx *gin.Context)
+	AddContact(ctx *gin.Context)
+	GetContactInvites(ctx *gin.Context)
+	AcceptContactInvite(ctx *gin.Context)
+	DeclineContactInvite(ctx *gin.Context)
+	RemoveContact(ctx *gin.Context)
+	SearchUsers(ctx *gin.Context)
+
 	CreateGroup(ctx *gin.Context)
 	UpdateGroup(ctx *gin.Context)
 	RemoveGroup(ctx *gin.Context)
@@ -20,9 +27,36 @@ type Interface interface {
 
 	CreateGroupBillForUser(ctx *gin.Context)
 	AssignUserToGroup(ctx *gin.Context)
+	GetGroupBill(ctx *gin.Context)
 	UpdateGroupBill(ctx *gin.Context)
 	DeleteGroupBill(ctx *gin.Context)
+	ImportGroupBills(ctx *gin.Context)
+
+	GetDeletedGroups(ctx *gin.Context)
+	RestoreGroup(ctx *gin.Context)
+	GetGroupBillTrash(ctx *gin.Context)
+	RestoreGroupBill(ctx *gin.Context)
 
 	CalculateBillSplits(ctx *gin.Context)
 	RecalculateBillSplits(ctx *gin.Context)
+
+	RecordSettlement(ctx *gin.Context)
+	GetGroupSettlements(ctx *gin.Context)
+
+	GetGroupActivity(ctx *gin.Context)
+	StreamGroup(ctx *gin.Context)
+
+	CreateGroupWebhook(ctx *gin.Context)
+	GetGroupWebhooks(ctx *gin.Context)
+	DeleteGroupWebhook(ctx *gin.Context)
+	GetGroupWebhookDeliveries(ctx *gin.Context)
+	RedeliverGroupWebhook(ctx *gin.Context)
+
+	GetReminderSetting(ctx *gin.Context)
+	UpdateReminderSetting(ctx *gin.Context)
+	SnoozeReminders(ctx *gin.Context)
+	NudgeDebtor(ctx *gin.Context)
+
+	GetGroupStatement(ctx *gin.Context)
+	GetUserStatement(ctx *gin.Context)
 }
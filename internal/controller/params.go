@@ -0,0 +1,23 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"main/pkg/apperror"
+	"main/util"
+)
+
+// pathID parses a numeric path parameter. On failure it reports a validation error for the parameter and returns false.
+func pathID(ctx *gin.Context, name string) (uint64, bool) {
+	id, err := util.ParseUint(ctx.Param(name))
+	if err != nil {
+		apperror.NewValidation(apperror.FieldError{
+			Field:   name,
+			Rule:    "uint",
+			Message: "must be a positive integer",
+		}).AbortWithError(ctx)
+
+		return 0, false
+	}
+
+	return id, true
+}
@@ -2,20 +2,40 @@ package controller
 
 import (
 	"github.com/google/wire"
+	activityRepo "main/internal/activity/repository"
+	activitySvc "main/internal/activity/service"
 	authRepo "main/internal/auth/repository"
 	authSvc "main/internal/auth/service"
 	billRepo "main/internal/bill/repository"
 	billSvc "main/internal/bill/service"
+	billShareRepo "main/internal/bill_share/repository"
 	billSplitRepo "main/internal/bill_split/repository"
 	billSplitSvc "main/internal/bill_split/service"
+	contactRepo "main/internal/contact/repository"
+	contactSvc "main/internal/contact/service"
+	contactInviteRepo "main/internal/contact_invite/repository"
 	groupRepo "main/internal/group/repository"
 	groupSvc "main/internal/group/service"
 	groupPermissionRepo "main/internal/group_permission/repository"
 	groupPermissionSvc "main/internal/group_permission/service"
 	otpRepo "main/internal/otp/repository"
 	otpSvc "main/internal/otp/service"
+	outboxRepo "main/internal/outbox/repository"
+	outboxSvc "main/internal/outbox/service"
+	outboxDeliveryRepo "main/internal/outbox_delivery/repository"
+	outboxPositionRepo "main/internal/outbox_position/repository"
+	reminderRepo "main/internal/reminder/repository"
+	reminderSvc "main/internal/reminder/service"
+	reminderSettingRepo "main/internal/reminder_setting/repository"
+	settlementRepo "main/internal/settlement/repository"
+	settlementSvc "main/internal/settlement/service"
+	statementRepo "main/internal/statement/repository"
+	statementSvc "main/internal/statement/service"
 	userRepo "main/internal/user/repository"
 	userSvc "main/internal/user/service"
+	webhookRepo "main/internal/webhook/repository"
+	webhookSvc "main/internal/webhook/service"
+	webhookDeliveryRepo "main/internal/webhook_delivery/repository"
 )
 
 var ProviderSet = wire.NewSet(
@@ -32,8 +52,28 @@ var ProviderSet = wire.NewSet(
 	groupPermissionSvc.NewService,
 	billSvc.NewService,
 	billRepo.NewRepository,
+	billShareRepo.NewRepository,
 	billSplitSvc.NewService,
 	billSplitRepo.NewRepository,
+	contactSvc.NewService,
+	contactRepo.NewRepository,
+	contactInviteRepo.NewRepository,
+	settlementSvc.NewService,
+	settlementRepo.NewRepository,
+	activitySvc.NewService,
+	activityRepo.NewRepository,
+	webhookSvc.NewService,
+	webhookRepo.NewRepository,
+	webhookDeliveryRepo.NewRepository,
+	outboxSvc.NewService,
+	outboxRepo.NewRepository,
+	outboxPositionRepo.NewRepository,
+	outboxDeliveryRepo.NewRepository,
+	reminderSvc.NewService,
+	reminderRepo.NewRepository,
+	reminderSettingRepo.NewRepository,
+	statementSvc.NewService,
+	statementRepo.NewRepository,
 
 	// bind each one of the interfaces
 	wire.Bind(new(Interface), new(*Controller)),
@@ -49,6 +89,26 @@ var ProviderSet = wire.NewSet(
 	wire.Bind(new(groupPermissionSvc.Interface), new(*groupPermissionSvc.Service)),
 	wire.Bind(new(billSvc.Interface), new(*billSvc.Service)),
 	wire.Bind(new(billRepo.Interface), new(*billRepo.Repository)),
+	wire.Bind(new(billShareRepo.Interface), new(*billShareRepo.Repository)),
 	wire.Bind(new(billSplitSvc.Interface), new(*billSplitSvc.Service)),
 	wire.Bind(new(billSplitRepo.Interface), new(*billSplitRepo.Repository)),
+	wire.Bind(new(contactSvc.Interface), new(*contactSvc.Service)),
+	wire.Bind(new(contactRepo.Interface), new(*contactRepo.Repository)),
+	wire.Bind(new(contactInviteRepo.Interface), new(*contactInviteRepo.Repository)),
+	wire.Bind(new(settlementSvc.Interface), new(*settlementSvc.Service)),
+	wire.Bind(new(settlementRepo.Interface), new(*settlementRepo.Repository)),
+	wire.Bind(new(activitySvc.Interface), new(*activitySvc.Service)),
+	wire.Bind(new(activityRepo.Interface), new(*activityRepo.Repository)),
+	wire.Bind(new(webhookSvc.Interface), new(*webhookSvc.Service)),
+	wire.Bind(new(webhookRepo.Interface), new(*webhookRepo.Repository)),
+	wire.Bind(new(webhookDeliveryRepo.Interface), new(*webhookDeliveryRepo.Repository)),
+	wire.Bind(new(outboxSvc.Interface), new(*outboxSvc.Service)),
+	wire.Bind(new(outboxRepo.Interface), new(*outboxRepo.Repository)),
+	wire.Bind(new(outboxPositionRepo.Interface), new(*outboxPositionRepo.Repository)),
+	wire.Bind(new(outboxDeliveryRepo.Interface), new(*outboxDeliveryRepo.Repository)),
+	wire.Bind(new(reminderSvc.Interface), new(*reminderSvc.Service)),
+	wire.Bind(new(reminderRepo.Interface), new(*reminderRepo.Repository)),
+	wire.Bind(new(reminderSettingRepo.Interface), new(*reminderSettingRepo.Repository)),
+	wire.Bind(new(statementSvc.Interface), new(*statementSvc.Service)),
+	wire.Bind(new(statementRepo.Interface), new(*statementRepo.Repository)),
 )
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"main/constants"
+	"main/internal/controller/request"
+	"main/internal/jwt/private"
+	reminderService "main/internal/reminder/service"
+	"main/pkg/apperror"
+	"main/pkg/ratelimit"
+	"net/http"
+	"time"
+)
+
+func (ctrl *Controller) GetReminderSetting(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	setting, err := ctrl.reminderSvc.GetReminderSetting(ctx, userID, groupID)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, setting)
+}
+
+func (ctrl *Controller) UpdateReminderSetting(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	var req request.UpdateReminderSettingRequest
+	if bindErr := ctx.ShouldBindJSON(&req); bindErr != nil {
+		apperror.Validation(bindErr).AbortWithError(ctx)
+		return
+	}
+
+	setting, err := ctrl.reminderSvc.UpdateReminderSetting(ctx, userID, groupID, req)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, setting)
+}
+
+func (ctrl *Controller) SnoozeReminders(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	splitID, ok := pathID(ctx, constants.SplitID)
+	if !ok {
+		return
+	}
+
+	var req request.SnoozeReminderRequest
+	if bindErr := ctx.ShouldBindJSON(&req); bindErr != nil {
+		apperror.Validation(bindErr).AbortWithError(ctx)
+		return
+	}
+
+	reminder, err := ctrl.reminderSvc.SnoozeReminders(ctx, userID, groupID, splitID, req)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reminder)
+}
+
+func (ctrl *Controller) NudgeDebtor(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	splitID, ok := pathID(ctx, constants.SplitID)
+	if !ok {
+		return
+	}
+
+	reminder, err := ctrl.reminderSvc.NudgeDebtor(ctx, userID, groupID, splitID)
+	if err.Exists() {
+		var cooldown *reminderService.NudgeCooldownError
+		if errors.As(err, &cooldown) {
+			ctx.Header(ratelimit.RetryAfterHeader, ratelimit.RetryAfter(time.Until(cooldown.Until)))
+		}
+
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reminder)
+}
@@ -1,21 +1,46 @@
 package request
 
 type CreateGroupRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
 }
 
 type UpdateGroupRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
 }
 
 type CreateBillRequest struct {
-	PaidAmount  float64 `json:"paid_amount"`
-	Description string  `json:"description"`
+	PaidAmount  float64 `json:"paid_amount" binding:"required,money"`
+	Description string  `json:"description" binding:"max=500"`
+	Category    string  `json:"category" binding:"max=50"`
+	Currency    string  `json:"currency" binding:"required,currency_code"`
 }
 
 type UpdateBillRequest struct {
-	PaidAmount  float64 `json:"paid_amount"`
-	Description string  `json:"description"`
+	PaidAmount  float64 `json:"paid_amount" binding:"required,money"`
+	Description string  `json:"description" binding:"max=500"`
+	Category    string  `json:"category" binding:"max=50"`
+	Currency    string  `json:"currency" binding:"required,currency_code"`
+}
+
+type RecordSettlementRequest struct {
+	ToUserID uint64  `json:"to_user_id" binding:"required"`
+	Amount   float64 `json:"amount" binding:"required,money"`
+	Currency string  `json:"currency" binding:"required,currency_code"`
+	Note     string  `json:"note" binding:"max=200"`
+}
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,https_url,max=2048"`
+	Events []string `json:"events" binding:"required,min=1,unique,dive,oneof=bill.created bill.updated bill.deleted split.calculated settlement.recorded member.joined"`
+}
+
+type UpdateReminderSettingRequest struct {
+	Enabled      *bool `json:"enabled" binding:"required"`
+	IntervalDays int   `json:"interval_days" binding:"required,min=1,max=90"`
+}
+
+type SnoozeReminderRequest struct {
+	Days int `json:"days" binding:"required,min=1,max=30"`
 }
@@ -1,28 +1,32 @@
 package request
 
 type ActivateRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	Otp      string `json:"otp"`
+	Email    string `json:"email" binding:"required,email_address"`
+	Password string `json:"password" binding:"required,strong_password"`
+	Otp      string `json:"otp" binding:"required,len=6,numeric"`
 }
 
 type SendOTPRequest struct {
-	Email string `json:"email"`
+	Email string `json:"email" binding:"required,email_address"`
 }
 
 type UpdateRequest struct {
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Name     string `json:"name" binding:"omitempty,max=100"`
+	Email    string `json:"email" binding:"omitempty,email_address"`
+	Password string `json:"password" binding:"omitempty,strong_password"`
 }
 
 type RegisterRequest struct {
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Name     string `json:"name" binding:"required,max=100"`
+	Email    string `json:"email" binding:"required,email_address"`
+	Password string `json:"password" binding:"required,strong_password"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" binding:"required,email_address"`
+	Password string `json:"password" binding:"required,max=72"`
+}
+
+type AddContactRequest struct {
+	Email string `json:"email" binding:"required,email_address"`
 }
@@ -0,0 +1,16 @@
+package response
+
+import "time"
+
+type Activity struct {
+	ID           uint64    `json:"id"`
+	Type         string    `json:"type"`
+	Message      string    `json:"message"`
+	Actor        User      `json:"actor"`
+	Member       *User     `json:"member,omitempty"`
+	BillID       uint64    `json:"bill_id,omitempty"`
+	SettlementID uint64    `json:"settlement_id,omitempty"`
+	Amount       float64   `json:"amount,omitempty"`
+	Currency     string    `json:"currency,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -1,9 +1,17 @@
 package response
 
+import "time"
+
 type Bill struct {
-	User        User    `json:"user"`
-	PaidAmount  float64 `json:"paid_amount"`
-	Description string  `json:"description"`
+	ID          uint64     `json:"id"`
+	User        User       `json:"user"`
+	PaidAmount  float64    `json:"paid_amount"`
+	Description string     `json:"description"`
+	Category    string     `json:"category"`
+	Currency    string     `json:"currency"`
+	Version     uint64     `json:"version"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
 
 type Bills []Bill
@@ -13,3 +21,29 @@ type BillSplitEntry struct {
 	AmountDue float64 `json:"amount_due"` // How much
 	IsPaid    bool    `json:"is_paid"`    // If settled
 }
+
+type BillImportRow struct {
+	Row         int      `json:"row"`
+	Date        string   `json:"date"`
+	Description string   `json:"description"`
+	Category    string   `json:"category"`
+	Cost        float64  `json:"cost"`
+	Currency    string   `json:"currency"`
+	PaidBy      *User    `json:"paid_by,omitempty"`
+	Errors      []string `json:"errors,omitempty"`
+	// what each member owes of the cost, the bill is split by these
+	Shares []BillImportShare `json:"shares,omitempty"`
+}
+
+type BillImportShare struct {
+	User   User    `json:"user"`
+	Amount float64 `json:"amount"`
+}
+
+type BillImportPreview struct {
+	DryRun    bool            `json:"dry_run"`
+	Imported  bool            `json:"imported"`
+	TotalRows int             `json:"total_rows"`
+	ValidRows int             `json:"valid_rows"`
+	Rows      []BillImportRow `json:"rows"`
+}
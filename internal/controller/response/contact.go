@@ -0,0 +1,9 @@
+package response
+
+import "time"
+
+type ContactInvite struct {
+	ID        uint64    `json:"id"`
+	Inviter   User      `json:"inviter"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,5 +1,7 @@
 package response
 
+import "time"
+
 type GroupPermissionResponse struct {
 	ID          uint64   `json:"id"`
 	Name        string   `json:"name"`
@@ -11,5 +13,15 @@ type GroupDetails struct {
 	ID          uint64 `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Version     uint64 `json:"version"`
 	Bills       Bills  `json:"bills"`
+	NextCursor  string `json:"next_cursor,omitempty"`
+	TotalBills  int64  `json:"total_bills"`
+}
+
+type DeletedGroup struct {
+	ID          uint64    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	DeletedAt   time.Time `json:"deleted_at"`
 }
@@ -0,0 +1,17 @@
+package response
+
+import "time"
+
+type ReminderSetting struct {
+	Enabled      bool       `json:"enabled"`
+	IntervalDays int        `json:"interval_days"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+type PaymentReminder struct {
+	UserID         uint64     `json:"user_id"`
+	ToPayUserID    uint64     `json:"to_pay_user_id"`
+	LastRemindedAt *time.Time `json:"last_reminded_at,omitempty"`
+	SnoozedUntil   *time.Time `json:"snoozed_until,omitempty"`
+	LastNudgedAt   *time.Time `json:"last_nudged_at,omitempty"`
+}
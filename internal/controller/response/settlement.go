@@ -0,0 +1,13 @@
+package response
+
+import "time"
+
+type Settlement struct {
+	ID        uint64    `json:"id"`
+	From      User      `json:"from"`
+	To        User      `json:"to"`
+	Amount    float64   `json:"amount"`
+	Currency  string    `json:"currency"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
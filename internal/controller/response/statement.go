@@ -0,0 +1,88 @@
+package response
+
+import "time"
+
+type StatementUser struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+}
+
+type StatementGroup struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+}
+
+type StatementCategory struct {
+	Category string  `json:"category"`
+	Total    float64 `json:"total"`
+	Bills    int     `json:"bills"`
+}
+
+type StatementSettlement struct {
+	ID uint64 `json:"id"`
+	// only set on user statements, which list the settlements of all their groups
+	Group     *StatementGroup `json:"group,omitempty"`
+	From      StatementUser   `json:"from"`
+	To        StatementUser   `json:"to"`
+	Amount    float64         `json:"amount"`
+	Currency  string          `json:"currency"`
+	Note      string          `json:"note,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// StatementTotals are the figures of one member of one group, or of a user across their groups, in one currency.
+// Balances are what is owed to them, negative when they owe.
+type StatementTotals struct {
+	Paid           float64 `json:"paid"`
+	Bills          int     `json:"bills"`
+	SettledOut     float64 `json:"settled_out"`
+	SettledIn      float64 `json:"settled_in"`
+	OpeningBalance float64 `json:"opening_balance"`
+	ClosingBalance float64 `json:"closing_balance"`
+}
+
+type GroupStatementMember struct {
+	User StatementUser `json:"user"`
+	StatementTotals
+	Categories []StatementCategory `json:"categories"`
+}
+
+// GroupStatementCurrency is the part of a group statement in one currency
+type GroupStatementCurrency struct {
+	Currency   string                 `json:"currency"`
+	TotalSpent float64                `json:"total_spent"`
+	Members    []GroupStatementMember `json:"members"`
+	Categories []StatementCategory    `json:"categories"`
+}
+
+type GroupStatement struct {
+	Group       StatementGroup           `json:"group"`
+	Month       string                   `json:"month"`
+	From        time.Time                `json:"from"`
+	To          time.Time                `json:"to"`
+	GeneratedAt time.Time                `json:"generated_at"`
+	Currencies  []GroupStatementCurrency `json:"currencies"`
+	Settlements []StatementSettlement    `json:"settlements"`
+}
+
+type UserStatementGroup struct {
+	Group StatementGroup `json:"group"`
+	StatementTotals
+}
+
+// UserStatementCurrency adds up the lines of a user in one currency across their groups
+type UserStatementCurrency struct {
+	Currency string `json:"currency"`
+	StatementTotals
+	Groups     []UserStatementGroup `json:"groups"`
+	Categories []StatementCategory  `json:"categories"`
+}
+
+type UserStatement struct {
+	User        StatementUser           `json:"user"`
+	Month       string                  `json:"month"`
+	From        time.Time               `json:"from"`
+	To          time.Time               `json:"to"`
+	Currencies  []UserStatementCurrency `json:"currencies"`
+	Settlements []StatementSettlement   `json:"settlements"`
+}
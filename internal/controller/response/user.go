@@ -3,7 +3,7 @@ package response
 type User struct {
 	ID    uint64 `json:"id"`
 	Name  string `json:"name"`
-	Email string `json:"email"`
+	Email string `json:"email,omitempty"`
 }
 
 type Users []User
@@ -0,0 +1,29 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Webhook struct {
+	ID     uint64   `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	// only returned when the webhook is created, deliveries are signed with it
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID            uint64          `json:"id"`
+	WebhookID     uint64          `json:"webhook_id"`
+	Event         string          `json:"event"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt *time.Time      `json:"next_attempt_at,omitempty"`
+	StatusCode    int             `json:"status_code,omitempty"`
+	LastError     string          `json:"last_error,omitempty"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"main/constants"
+	"main/internal/controller/request"
+	"main/internal/controller/response"
+	"main/internal/jwt/private"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/repository/query"
+	"net/http"
+)
+
+func (ctrl *Controller) RecordSettlement(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	var req request.RecordSettlementRequest
+	if bindErr := ctx.ShouldBindJSON(&req); bindErr != nil {
+		apperror.Validation(bindErr).AbortWithError(ctx)
+		return
+	}
+
+	settlement, err := ctrl.settlementSvc.RecordSettlement(ctx, userID, groupID, req)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, settlement)
+}
+
+func (ctrl *Controller) GetGroupSettlements(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	q, parseErr := query.Parse(ctx.Request.URL.Query(), model.Settlement{}.FilterColumns())
+	if parseErr != nil {
+		apperror.Wrap(parseErr, apperror.ValidationFailed, parseErr.Error()).AbortWithError(ctx)
+		return
+	}
+
+	settlements, page, err := ctrl.settlementSvc.GetGroupSettlements(ctx, userID, groupID, q)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.Page[response.Settlement]{
+		Items:      settlements,
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	})
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"main/constants"
+	"main/internal/controller/adapter"
+	"main/internal/jwt/private"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/document"
+	"net/http"
+	"time"
+)
+
+// formats statements are rendered in, picked with ?format=
+const (
+	formatJSON = "json"
+	formatHTML = "html"
+	formatPDF  = "pdf"
+)
+
+func (ctrl *Controller) GetGroupStatement(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	month, ok := pathMonth(ctx)
+	if !ok {
+		return
+	}
+
+	format, ok := statementFormat(ctx)
+	if !ok {
+		return
+	}
+
+	statement, err := ctrl.statementSvc.GetGroupStatement(ctx, userID, groupID, month)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	if format == formatJSON {
+		ctx.JSON(http.StatusOK, statement)
+		return
+	}
+
+	filename := fmt.Sprintf("group-%d-statement-%s", groupID, statement.Month)
+	renderDocument(ctx, format, filename, adapter.BuildGroupStatementDocument(statement))
+}
+
+func (ctrl *Controller) GetUserStatement(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	month, ok := pathMonth(ctx)
+	if !ok {
+		return
+	}
+
+	format, ok := statementFormat(ctx)
+	if !ok {
+		return
+	}
+
+	statement, err := ctrl.statementSvc.GetUserStatement(ctx, userID, month)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	if format == formatJSON {
+		ctx.JSON(http.StatusOK, statement)
+		return
+	}
+
+	filename := fmt.Sprintf("statement-%s", statement.Month)
+	renderDocument(ctx, format, filename, adapter.BuildUserStatementDocument(statement))
+}
+
+// pathMonth parses the yyyy-mm month of a statement from the path
+func pathMonth(ctx *gin.Context) (time.Time, bool) {
+	month, parseErr := time.Parse(model.StatementMonthLayout, ctx.Param(constants.Month))
+	if parseErr != nil {
+		apperror.NewValidation(apperror.FieldError{
+			Field:   constants.Month,
+			Rule:    "yyyy-mm",
+			Message: "must be a month like 2026-01",
+		}).AbortWithError(ctx)
+
+		return time.Time{}, false
+	}
+
+	return month, true
+}
+
+func statementFormat(ctx *gin.Context) (string, bool) {
+	format := ctx.DefaultQuery(constants.Format, formatJSON)
+	switch format {
+	case formatJSON, formatHTML, formatPDF:
+		return format, true
+	}
+
+	apperror.NewValidation(apperror.FieldError{
+		Field:   constants.Format,
+		Rule:    "oneof",
+		Message: "must be one of json, html, pdf",
+	}).AbortWithError(ctx)
+
+	return "", false
+}
+
+// renderDocument writes doc as HTML or as a PDF download. It is rendered to a buffer first, so a failure still
+// answers with an error instead of a truncated document.
+func renderDocument(ctx *gin.Context, format, filename string, doc document.Document) {
+	var buf bytes.Buffer
+	contentType := "text/html; charset=utf-8"
+	render := document.HTML
+	if format == formatPDF {
+		contentType = "application/pdf"
+		render = document.PDF
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".pdf"))
+	}
+
+	if renderErr := render(&buf, doc); renderErr != nil {
+		ctx.Header("Content-Disposition", "")
+		apperror.Wrap(renderErr, apperror.Internal, "Failed to render statement").AbortWithError(ctx)
+		return
+	}
+
+	ctx.Data(http.StatusOK, contentType, buf.Bytes())
+}
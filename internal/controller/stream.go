@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	config "github.com/spf13/viper"
+	"io"
+	"main/constants"
+	"main/internal/jwt/private"
+	"main/internal/model"
+	"main/pkg/metrics"
+	"main/pkg/stream"
+	"net/http"
+	"time"
+)
+
+// StreamGroup pushes the changes of a group to a member as server-sent events, one event per activity, until the
+// client disconnects, the server shuts down, the access token expires or the user is no longer a member, which is
+// checked again on every heartbeat. A client that reconnects reloads the group, events missed while disconnected are
+// not replayed.
+func (ctrl *Controller) StreamGroup(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	if _, err = ctrl.groupService.ValidateUserGroupPermission(ctx, userID, groupID, model.View); err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	subscription := stream.Default().Subscribe(groupID)
+	defer subscription.Close()
+
+	metrics.GroupStreamsOpen.Inc()
+	defer metrics.GroupStreamsOpen.Dec()
+
+	heartbeat := time.NewTicker(config.GetDuration("stream.heartbeat"))
+	defer heartbeat.Stop()
+
+	// without an expiry in the token the stream is only bounded by the membership checks
+	var expired <-chan time.Time
+	if expiresAt, ok := private.GetTokenExpiry(ctx); ok {
+		expiry := time.NewTimer(time.Until(expiresAt))
+		defer expiry.Stop()
+		expired = expiry.C
+	}
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// keeps nginx from buffering the stream
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	// headers go out right away, the first event may take a while
+	ctx.Writer.Flush()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case event, open := <-subscription.Events():
+			if !open {
+				return false
+			}
+
+			ctx.SSEvent(event.Type, event.Data)
+			return true
+		case <-expired:
+			return false
+		case <-heartbeat.C:
+			hasPermission, permErr := ctrl.groupService.ValidateUserGroupPermission(ctx, userID, groupID, model.View)
+			if permErr.Exists() || !hasPermission {
+				return false
+			}
+
+			_, writeErr := io.WriteString(w, ": keep-alive\n\n")
+			return writeErr == nil
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"main/constants"
+	"main/internal/controller/adapter"
+	"main/internal/controller/response"
+	"main/internal/jwt/private"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/repository/query"
+	"net/http"
+)
+
+func (ctrl *Controller) GetDeletedGroups(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	q, parseErr := query.Parse(ctx.Request.URL.Query(), model.Group{}.FilterColumns())
+	if parseErr != nil {
+		apperror.Wrap(parseErr, apperror.ValidationFailed, parseErr.Error()).AbortWithError(ctx)
+		return
+	}
+
+	groups, page, err := ctrl.groupService.GetDeletedGroups(ctx, userID, q)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.Page[response.DeletedGroup]{
+		Items:      adapter.BuildDeletedGroupsResponse(groups),
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	})
+}
+
+func (ctrl *Controller) RestoreGroup(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	if err = ctrl.groupService.RestoreGroup(ctx, userID, groupID); err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Group restored successfully"})
+}
+
+func (ctrl *Controller) GetGroupBillTrash(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	q, parseErr := query.Parse(ctx.Request.URL.Query(), model.Bill{}.FilterColumns())
+	if parseErr != nil {
+		apperror.Wrap(parseErr, apperror.ValidationFailed, parseErr.Error()).AbortWithError(ctx)
+		return
+	}
+
+	bills, page, err := ctrl.groupService.GetGroupBillTrash(ctx, userID, groupID, q)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.Page[response.Bill]{
+		Items:      bills,
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	})
+}
+
+func (ctrl *Controller) RestoreGroupBill(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	billID, ok := pathID(ctx, constants.BillID)
+	if !ok {
+		return
+	}
+
+	if err = ctrl.groupService.RestoreGroupBill(ctx, userID, groupID, billID); err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Bill restored successfully"})
+}
@@ -1,24 +1,34 @@
 package controller
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"main/internal/controller/adapter"
 	"main/internal/controller/request"
 	"main/internal/jwt/private"
 	"main/internal/model"
+	userService "main/internal/user/service"
+	"main/pkg/apperror"
+	"main/pkg/ratelimit"
 	"net/http"
+	"time"
 )
 
 func (ctrl *Controller) LoginUser(ctx *gin.Context) {
 	var req request.LoginRequest
 	if err := ctx.ShouldBindBodyWithJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperror.Validation(err).AbortWithError(ctx)
 		return
 	}
 
 	token, err := ctrl.userSvc.AuthenticateUser(ctx, req.Email, req.Password)
 	if err.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		var locked *userService.AccountLockedError
+		if errors.As(err, &locked) {
+			ctx.Header(ratelimit.RetryAfterHeader, ratelimit.RetryAfter(time.Until(locked.Until)))
+		}
+
+		err.AbortWithError(ctx)
 		return
 	}
 
@@ -28,12 +38,12 @@ func (ctrl *Controller) LoginUser(ctx *gin.Context) {
 func (ctrl *Controller) RegisterUser(ctx *gin.Context) {
 	var req request.RegisterRequest
 	if err := ctx.ShouldBindBodyWithJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperror.Validation(err).AbortWithError(ctx)
 		return
 	}
 
 	if err := ctrl.userSvc.CreateUserAccount(ctx, req); err.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
@@ -43,13 +53,13 @@ func (ctrl *Controller) RegisterUser(ctx *gin.Context) {
 func (ctrl *Controller) UpdateUserProfile(ctx *gin.Context) {
 	var req request.UpdateRequest
 	if err := ctx.ShouldBindBodyWithJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperror.Validation(err).AbortWithError(ctx)
 		return
 	}
 
 	userID, err := private.GetUserID(ctx)
 	if err.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
@@ -61,38 +71,22 @@ func (ctrl *Controller) UpdateUserProfile(ctx *gin.Context) {
 	}
 
 	if err = ctrl.userSvc.UpdateUserProfile(ctx, user); err.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "Profile updated successfully"})
 }
 
-func (ctrl *Controller) GetUsers(ctx *gin.Context) {
-	userID, err := private.GetUserID(ctx)
-	if err.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	users, err := ctrl.userSvc.GetUsers(ctx, userID)
-	if err.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	ctx.JSON(http.StatusOK, adapter.BuildUsersResponse(users))
-}
-
 func (ctrl *Controller) SendActivationEmail(ctx *gin.Context) {
 	var req request.SendOTPRequest
 	if err := ctx.ShouldBindBodyWithJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperror.Validation(err).AbortWithError(ctx)
 		return
 	}
 
 	if err := ctrl.userSvc.SendActivationEmail(ctx, req.Email); err.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
@@ -102,12 +96,12 @@ func (ctrl *Controller) SendActivationEmail(ctx *gin.Context) {
 func (ctrl *Controller) ActivateUser(ctx *gin.Context) {
 	var req request.ActivateRequest
 	if err := ctx.ShouldBindBodyWithJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperror.Validation(err).AbortWithError(ctx)
 		return
 	}
 
 	if err := ctrl.userSvc.ActivateUserAccount(ctx, req.Email, req.Password, req.Otp); err.Exists() {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		err.AbortWithError(ctx)
 		return
 	}
 
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"main/constants"
+	"main/internal/controller/request"
+	"main/internal/controller/response"
+	"main/internal/jwt/private"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/repository/query"
+	"net/http"
+)
+
+func (ctrl *Controller) CreateGroupWebhook(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	var req request.CreateWebhookRequest
+	if bindErr := ctx.ShouldBindJSON(&req); bindErr != nil {
+		apperror.Validation(bindErr).AbortWithError(ctx)
+		return
+	}
+
+	webhook, err := ctrl.groupService.CreateGroupWebhook(ctx, userID, groupID, req)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, webhook)
+}
+
+func (ctrl *Controller) GetGroupWebhooks(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	webhooks, err := ctrl.groupService.GetGroupWebhooks(ctx, userID, groupID)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"webhooks": webhooks})
+}
+
+func (ctrl *Controller) DeleteGroupWebhook(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	webhookID, ok := pathID(ctx, constants.WebhookID)
+	if !ok {
+		return
+	}
+
+	if err = ctrl.groupService.DeleteGroupWebhook(ctx, userID, groupID, webhookID); err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+func (ctrl *Controller) GetGroupWebhookDeliveries(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	webhookID, ok := pathID(ctx, constants.WebhookID)
+	if !ok {
+		return
+	}
+
+	// set before parsing so cursors of the default order are checked against it
+	values := ctx.Request.URL.Query()
+	// the latest deliveries are the ones worth looking at
+	if values.Get(query.SortKey) == "" {
+		values.Set(query.SortKey, "-"+constants.ID)
+	}
+
+	q, parseErr := query.Parse(values, model.WebhookDelivery{}.FilterColumns())
+	if parseErr != nil {
+		apperror.Wrap(parseErr, apperror.ValidationFailed, parseErr.Error()).AbortWithError(ctx)
+		return
+	}
+
+	deliveries, page, err := ctrl.groupService.GetGroupWebhookDeliveries(ctx, userID, groupID, webhookID, q)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.Page[response.WebhookDelivery]{
+		Items:      deliveries,
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	})
+}
+
+func (ctrl *Controller) RedeliverGroupWebhook(ctx *gin.Context) {
+	userID, err := private.GetUserID(ctx)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	groupID, ok := pathID(ctx, constants.GroupID)
+	if !ok {
+		return
+	}
+
+	webhookID, ok := pathID(ctx, constants.WebhookID)
+	if !ok {
+		return
+	}
+
+	deliveryID, ok := pathID(ctx, constants.DeliveryID)
+	if !ok {
+		return
+	}
+
+	delivery, err := ctrl.groupService.RedeliverGroupWebhook(ctx, userID, groupID, webhookID, deliveryID)
+	if err.Exists() {
+		err.AbortWithError(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, delivery)
+}
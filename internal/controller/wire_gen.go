@@ -8,20 +8,40 @@ package controller
 
 import (
 	"context"
+	repository8 "main/internal/activity/repository"
+	service6 "main/internal/activity/service"
 	repository2 "main/internal/auth/repository"
 	"main/internal/auth/service"
 	repository6 "main/internal/bill/repository"
 	service5 "main/internal/bill/service"
-	repository7 "main/internal/bill_split/repository"
-	service7 "main/internal/bill_split/service"
+	repository7 "main/internal/bill_share/repository"
+	repository14 "main/internal/bill_split/repository"
+	service10 "main/internal/bill_split/service"
+	repository16 "main/internal/contact/repository"
+	service11 "main/internal/contact/service"
+	repository17 "main/internal/contact_invite/repository"
 	repository4 "main/internal/group/repository"
-	service6 "main/internal/group/service"
+	service9 "main/internal/group/service"
 	repository5 "main/internal/group_permission/repository"
 	service4 "main/internal/group_permission/service"
 	repository3 "main/internal/otp/repository"
 	service2 "main/internal/otp/service"
+	repository11 "main/internal/outbox/repository"
+	service8 "main/internal/outbox/service"
+	repository13 "main/internal/outbox_delivery/repository"
+	repository12 "main/internal/outbox_position/repository"
+	repository18 "main/internal/reminder/repository"
+	service13 "main/internal/reminder/service"
+	repository19 "main/internal/reminder_setting/repository"
+	repository15 "main/internal/settlement/repository"
+	service12 "main/internal/settlement/service"
+	repository20 "main/internal/statement/repository"
+	service14 "main/internal/statement/service"
 	"main/internal/user/repository"
 	service3 "main/internal/user/service"
+	repository9 "main/internal/webhook/repository"
+	service7 "main/internal/webhook/service"
+	repository10 "main/internal/webhook_delivery/repository"
 	"main/pkg/db/postgres"
 )
 
@@ -29,19 +49,39 @@ import (
 
 func Wire(ctx context.Context, db *postgres.DbCluster) *Controller {
 	repositoryRepository := repository.NewRepository(db)
-	repository8 := repository2.NewRepository(db)
-	serviceService := service.NewService(repository8)
-	repository9 := repository3.NewRepository(db)
-	service8 := service2.NewService(repository9)
-	service9 := service3.NewService(repositoryRepository, serviceService, service8)
-	repository10 := repository4.NewRepository(db)
-	repository11 := repository5.NewRepository(db)
-	service10 := service4.NewService(repository11)
-	repository12 := repository6.NewRepository(db)
-	service11 := service5.NewService(repository12)
-	service12 := service6.NewService(repository10, service10, service11, service9)
-	repository13 := repository7.NewRepository(db)
-	service13 := service7.NewService(repository13, service11, service12)
-	controller := NewController(service9, service12, service13)
+	repository21 := repository2.NewRepository(db)
+	serviceService := service.NewService(repository21)
+	repository22 := repository3.NewRepository(db)
+	service15 := service2.NewService(repository22)
+	service16 := service3.NewService(repositoryRepository, serviceService, service15)
+	repository23 := repository4.NewRepository(db)
+	repository24 := repository5.NewRepository(db)
+	service17 := service4.NewService(repository24)
+	repository25 := repository6.NewRepository(db)
+	repository26 := repository7.NewRepository(db)
+	service18 := service5.NewService(repository25, repository26)
+	repository27 := repository8.NewRepository(db)
+	service19 := service6.NewService(repository27)
+	repository28 := repository9.NewRepository(db)
+	repository29 := repository10.NewRepository(db)
+	service20 := service7.NewService(repository28, repository29)
+	repository30 := repository11.NewRepository(db)
+	repository31 := repository12.NewRepository(db)
+	repository32 := repository13.NewRepository(db)
+	service21 := service8.NewService(repository30, repository31, repository32)
+	service22 := service9.NewService(repository23, service17, service18, service16, service19, service20, service21)
+	repository33 := repository14.NewRepository(db)
+	repository34 := repository15.NewRepository(db)
+	service23 := service10.NewService(repository33, service18, service22, repository34, service21)
+	repository35 := repository16.NewRepository(db)
+	repository36 := repository17.NewRepository(db)
+	service24 := service11.NewService(repository35, repository36, repositoryRepository, service17)
+	service25 := service12.NewService(repository34, repository33, service22, service21, service16)
+	repository37 := repository18.NewRepository(db)
+	repository38 := repository19.NewRepository(db)
+	service26 := service13.NewService(repository37, repository38, repository33, repository23, service22, service16)
+	repository39 := repository20.NewRepository(db)
+	service27 := service14.NewService(repository39, repository23, repository25, repository26, repository34, service22, service17, service16)
+	controller := NewController(service16, service22, service23, service24, service25, service26, service27)
 	return controller
 }
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"main/constants"
+	"main/internal/controller/adapter"
+	"main/internal/controller/response"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/logger"
+	"main/pkg/tracing"
+	"main/repository/query"
+)
+
+// GetGroupActivity returns one page of the feed of a group with the names of the members it mentions
+func (s *Service) GetGroupActivity(
+	ctx context.Context,
+	userID, groupID uint64,
+	q *query.Query,
+) ([]response.Activity, query.Page, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "GroupService.GetGroupActivity")
+	defer span.End()
+
+	log := logger.With(ctx, "GetGroupActivity")
+
+	hasPermission, err := s.ValidateUserGroupPermission(ctx, userID, groupID, model.View)
+	if err.Exists() || !hasPermission {
+		log.Warnf("user %d cannot view the activity of group %d: %v", userID, groupID, err)
+		return nil, query.Page{}, err
+	}
+
+	activities, page, err := s.activitySvc.GetGroupActivity(ctx, groupID, q)
+	if err.Exists() {
+		return nil, page, err
+	}
+
+	users, err := s.userSvc.FetchFilteredUsers(ctx, map[string]any{
+		constants.ID: activities.ExtractUniqueUserIDs(),
+	})
+	if err.Exists() {
+		log.Errorf("failed to fetch members named in the activity of group %d: %v", groupID, err)
+		return nil, page, apperror.NewCode(apperror.Internal, "Failed to fetch users")
+	}
+
+	return adapter.BuildActivitiesResponse(activities, users), page, apperror.Error{}
+}
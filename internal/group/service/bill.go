@@ -2,13 +2,14 @@ package service
 
 import (
 	"context"
-	"log"
 	"main/constants"
+	"main/internal/controller/adapter"
 	"main/internal/controller/request"
+	"main/internal/controller/response"
 	"main/internal/model"
 	"main/pkg/apperror"
-	"main/util"
-	"net/http"
+	"main/pkg/logger"
+	"main/pkg/tracing"
 )
 
 func (s *Service) CreateGroupBill(
@@ -16,20 +17,23 @@ func (s *Service) CreateGroupBill(
 	currentUserID, userID, groupID uint64,
 	req request.CreateBillRequest,
 ) apperror.Error {
-	logTag := util.LogPrefix(ctx, "CreateGroupBill")
+	ctx, span := tracing.Start(ctx, "GroupService.CreateGroupBill")
+	defer span.End()
+
+	log := logger.With(ctx, "CreateGroupBill")
 	hasPermission, err := s.ValidateUserGroupPermission(ctx, currentUserID, groupID, model.Create)
 	if err.Exists() {
-		log.Printf("%s failed to validate permission for user %d on group %d: %v", logTag, userID, groupID, err)
+		log.Errorf("failed to validate permission for user %d on group %d: %v", userID, groupID, err)
 		return err
 	}
 	if !hasPermission {
-		return apperror.NewWithMessage("Permission denied", http.StatusForbidden)
+		return apperror.NewCode(apperror.PermissionDenied, "Permission denied")
 	}
 
 	if currentUserID != userID && s.userSvc.IsUserValid(ctx, userID) {
-		log.Printf("%s: invalid user ID %d", logTag, userID)
+		log.Warnf("invalid user ID %d", userID)
 
-		return apperror.NewWithMessage("Please provide a valid user", http.StatusBadRequest)
+		return apperror.NewCode(apperror.BadRequest, "Please provide a valid user")
 	}
 
 	bill := model.Bill{
@@ -37,70 +41,154 @@ func (s *Service) CreateGroupBill(
 		GroupID:     groupID,
 		PaidAmount:  req.PaidAmount,
 		Description: req.Description,
+		Category:    req.Category,
+		Currency:    req.Currency,
+	}
+	return s.groupRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		if err := s.billSvc.CreateBill(txCtx, &bill); err.Exists() {
+			log.Errorf("failed to create bill for user %d in group %d: %v", userID, groupID, err)
+			return err
+		}
+
+		return s.outboxSvc.Publish(txCtx, model.OutboxEvent{
+			GroupID: groupID,
+			ActorID: currentUserID,
+			Type:    model.BillCreated,
+			Data: model.ActivityData{
+				BillID:      bill.ID,
+				UserID:      bill.UserID,
+				Description: bill.Description,
+				Amount:      bill.PaidAmount,
+				Currency:    bill.Currency,
+			},
+		})
+	})
+}
+
+func (s *Service) GetGroupBill(
+	ctx context.Context,
+	userID, groupID, billID uint64,
+) (response.Bill, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "GroupService.GetGroupBill")
+	defer span.End()
+
+	log := logger.With(ctx, "GetGroupBill")
+
+	hasPermission, err := s.ValidateUserGroupPermission(ctx, userID, groupID, model.View)
+	if err.Exists() {
+		log.Errorf("permission validation failed for user %d: %v", userID, err)
+		return response.Bill{}, err
+	}
+	if !hasPermission {
+		return response.Bill{}, apperror.NewCode(apperror.PermissionDenied, "Permission denied")
 	}
-	err = s.billSvc.CreateBill(ctx, bill)
+
+	bill, err := s.billSvc.GetBill(ctx, groupID, billID)
+	if err.Exists() {
+		return response.Bill{}, err
+	}
+
+	users, err := s.userSvc.FetchFilteredUsers(ctx, map[string]any{constants.ID: bill.UserID})
 	if err.Exists() {
-		log.Printf("%s failed to create bill for user %d in group %d: %v", logTag, userID, groupID, err)
-		return apperror.NewWithMessage("Failed to create bill", http.StatusBadRequest)
+		log.Errorf("failed to fetch payer of bill %d: %v", billID, err)
+		return response.Bill{}, apperror.NewCode(apperror.Internal, "Failed to fetch users")
 	}
 
-	return apperror.Error{}
+	return adapter.BuildBillResponse(bill, users.MapByID()), apperror.Error{}
 }
 
 func (s *Service) UpdateGroupBill(
 	ctx context.Context,
-	userID, groupID, billID uint64,
+	userID, groupID, billID, version uint64,
 	req request.UpdateBillRequest,
 ) apperror.Error {
-	logTag := util.LogPrefix(ctx, "UpdateGroupBill")
+	ctx, span := tracing.Start(ctx, "GroupService.UpdateGroupBill")
+	defer span.End()
+
+	log := logger.With(ctx, "UpdateGroupBill")
 
 	hasPermission, err := s.ValidateUserGroupPermission(ctx, userID, groupID, model.Edit)
 	if err.Exists() {
-		log.Printf("%s permission validation failed for user %d: %v", logTag, userID, err)
+		log.Errorf("permission validation failed for user %d: %v", userID, err)
 		return err
 	}
 	if !hasPermission {
-		log.Printf("%s user %d lacks permission to update bills in group %d", logTag, userID, groupID)
-		return apperror.NewWithMessage("Permission denied", http.StatusForbidden)
-	}
-
-	bill := model.Bill{
-		PaidAmount:  req.PaidAmount,
-		Description: req.Description,
-	}
-	err = s.billSvc.UpdateBill(ctx, billID, bill)
-	if err.Exists() {
-		log.Printf("%s failed to update bill for user %d: %v", logTag, userID, err)
-
-		return apperror.NewWithMessage("Failed to update bill", http.StatusBadRequest)
+		log.Warnf("user %d lacks permission to update bills in group %d", userID, groupID)
+		return apperror.NewCode(apperror.PermissionDenied, "Permission denied")
 	}
 
-	return apperror.Error{}
+	return s.groupRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		// PUT replaces the whole bill, the version guards against overwriting changes the client has not seen
+		err := s.billSvc.UpdateBill(txCtx, groupID, billID, version, map[string]any{
+			constants.PaidAmount:  req.PaidAmount,
+			constants.Description: req.Description,
+			constants.Category:    req.Category,
+			constants.Currency:    req.Currency,
+		})
+		if err.Exists() {
+			log.Errorf("failed to update bill for user %d: %v", userID, err)
+
+			return err
+		}
+
+		return s.outboxSvc.Publish(txCtx, model.OutboxEvent{
+			GroupID: groupID,
+			ActorID: userID,
+			Type:    model.BillUpdated,
+			Data: model.ActivityData{
+				BillID:      billID,
+				Description: req.Description,
+				Amount:      req.PaidAmount,
+				Currency:    req.Currency,
+			},
+		})
+	})
 }
 
 func (s *Service) DeleteGroupBill(
 	ctx context.Context,
 	userID, groupID, billID uint64,
 ) apperror.Error {
-	logTag := util.LogPrefix(ctx, "DeleteGroupBill")
+	ctx, span := tracing.Start(ctx, "GroupService.DeleteGroupBill")
+	defer span.End()
+
+	log := logger.With(ctx, "DeleteGroupBill")
 
 	hasPermission, err := s.ValidateUserGroupPermission(ctx, userID, groupID, model.Delete)
 	if err.Exists() {
-		log.Printf("%s permission validation failed for user %d: %v", logTag, userID, err)
+		log.Errorf("permission validation failed for user %d: %v", userID, err)
 		return err
 	}
 	if !hasPermission {
-		log.Printf("%s user %d lacks permission to delete bills in group %d", logTag, userID, groupID)
+		log.Warnf("user %d lacks permission to delete bills in group %d", userID, groupID)
 
-		return apperror.NewWithMessage("Permission denied", http.StatusForbidden)
+		return apperror.NewCode(apperror.PermissionDenied, "Permission denied")
 	}
 
-	err = s.billSvc.DeleteBill(ctx, billID)
+	bill, err := s.billSvc.GetBill(ctx, groupID, billID)
 	if err.Exists() {
-		log.Printf("%s failed to delete bill %d for user %d: %v", logTag, billID, userID, err)
+		log.Warnf("failed to find bill %d in group %d: %v", billID, groupID, err)
 		return err
 	}
-	return apperror.Error{}
+
+	return s.groupRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		if err := s.billSvc.DeleteBill(txCtx, billID); err.Exists() {
+			log.Errorf("failed to delete bill %d for user %d: %v", billID, userID, err)
+			return err
+		}
+
+		return s.outboxSvc.Publish(txCtx, model.OutboxEvent{
+			GroupID: groupID,
+			ActorID: userID,
+			Type:    model.BillDeleted,
+			Data: model.ActivityData{
+				BillID:      bill.ID,
+				Description: bill.Description,
+				Amount:      bill.PaidAmount,
+				Currency:    bill.Currency,
+			},
+		})
+	})
 }
 
 func (s *Service) ValidateUserGroupPermission(
@@ -109,21 +197,21 @@ func (s *Service) ValidateUserGroupPermission(
 	groupID uint64,
 	permissionType model.PermissionType,
 ) (bool, apperror.Error) {
-	logTag := util.LogPrefix(ctx, "ValidateUserGroupPermission")
+	ctx, span := tracing.Start(ctx, "GroupService.ValidateUserGroupPermission")
+	defer span.End()
 
-	group, err := s.groupRepo.Get(ctx, map[string]any{
-		constants.ID: groupID,
-	})
-	if err.Exists() || group.ID == 0 {
-		log.Printf("%s group not found with ID %d: %v", logTag, groupID, err)
-		return false, apperror.NewWithMessage("Group not found or unauthorized access", http.StatusForbidden)
+	log := logger.With(ctx, "ValidateUserGroupPermission")
+
+	if _, err := s.getGroup(ctx, groupID); err.Exists() {
+		log.Warnf("group not found with ID %d: %v", groupID, err)
+		return false, err
 	}
 
 	hasPermission, err := s.groupPermissionSvc.HasUserPermissionInGroup(ctx, userID, groupID, permissionType)
 	if err.Exists() || !hasPermission {
-		log.Printf("%s user %d does not have '%s' permission for group %d: %v", logTag, userID, permissionType, groupID, err)
+		log.Warnf("user %d does not have '%s' permission for group %d: %v", userID, permissionType, groupID, err)
 
-		return false, apperror.NewWithMessage("Permission denied", http.StatusForbidden)
+		return false, apperror.NewCode(apperror.PermissionDenied, "Permission denied")
 	}
 
 	return true, apperror.Error{}
@@ -2,22 +2,26 @@ package service
 
 import (
 	"context"
-	"log"
+	"errors"
 	"main/constants"
 	"main/internal/controller/adapter"
 	"main/internal/controller/request"
 	"main/internal/controller/response"
 	"main/internal/model"
 	"main/pkg/apperror"
-	"main/util"
-	"net/http"
+	"main/pkg/logger"
+	"main/pkg/tracing"
+	baseRepository "main/repository"
+	"main/repository/query"
 	"strconv"
-	"sync"
 	"time"
 )
 
 func (s *Service) CreateGroup(ctx context.Context, userID uint64, req request.CreateGroupRequest) apperror.Error {
-	logTag := util.LogPrefix(ctx, "CreateGroup")
+	ctx, span := tracing.Start(ctx, "GroupService.CreateGroup")
+	defer span.End()
+
+	log := logger.With(ctx, "CreateGroup")
 
 	group := model.Group{
 		OwnerID:     userID,
@@ -26,190 +30,219 @@ func (s *Service) CreateGroup(ctx context.Context, userID uint64, req request.Cr
 		CreatedBy:   strconv.FormatUint(userID, 10),
 		UpdatedBy:   strconv.FormatUint(userID, 10),
 	}
-	err := s.groupRepo.Create(ctx, &group)
-	if err.Exists() {
-		log.Printf("%s failed to create group: %v", logTag, err)
 
-		return apperror.NewWithMessage("Failed to create group", http.StatusBadRequest)
-	}
+	// the group, its owner permissions and its event are stored together or not at all
+	return s.groupRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		err := s.groupRepo.Create(txCtx, &group)
+		if err.Exists() {
+			log.Errorf("failed to create group: %v", err)
+
+			return apperror.NewCode(apperror.Internal, "Failed to create group")
+		}
+
+		err = s.groupPermissionSvc.AssignGroupPermissionsToUser(
+			txCtx,
+			userID,
+			group.ID,
+			[]model.PermissionType{model.View, model.Create, model.Edit, model.Delete},
+		)
+		if err.Exists() {
+			return err
+		}
 
-	return s.groupPermissionSvc.AssignGroupPermissionsToUser(
-		ctx,
-		userID,
-		group.ID,
-		[]model.PermissionType{model.View, model.Create, model.Edit, model.Delete},
-	)
+		return s.outboxSvc.Publish(txCtx, model.OutboxEvent{
+			GroupID: group.ID,
+			ActorID: userID,
+			Type:    model.GroupCreated,
+			Data:    model.ActivityData{Name: group.Name},
+		})
+	})
 }
 
-func (s *Service) UpdateGroup(ctx context.Context, userID, groupID uint64, req request.UpdateGroupRequest) apperror.Error {
-	logTag := util.LogPrefix(ctx, "UpdateGroup")
+// UpdateGroup overwrites the group only if it is still at version, see repository.UpdateWithVersion
+func (s *Service) UpdateGroup(
+	ctx context.Context,
+	userID, groupID, version uint64,
+	req request.UpdateGroupRequest,
+) apperror.Error {
+	ctx, span := tracing.Start(ctx, "GroupService.UpdateGroup")
+	defer span.End()
 
-	group, err := s.groupRepo.Get(ctx, map[string]any{constants.ID: groupID})
-	if err.Exists() {
-		return apperror.NewWithMessage("Permission denied", http.StatusForbidden)
-	}
+	log := logger.With(ctx, "UpdateGroup")
 
-	if group.ID == 0 {
-		return apperror.NewWithMessage("Permission denied", http.StatusForbidden)
+	if _, err := s.getGroup(ctx, groupID); err.Exists() {
+		log.Warnf("failed to find group %d: %v", groupID, err)
+		return err
 	}
 
 	hasPermission, err := s.groupPermissionSvc.HasUserPermissionInGroup(ctx, userID, groupID, model.Edit)
 	if err.Exists() || !hasPermission {
-		log.Printf("%s user %d does not have edit permission for group %d. Error: %v", logTag, userID, groupID, err)
+		log.Errorf("user %d does not have edit permission for group %d. Error: %v", userID, groupID, err)
 
-		return apperror.NewWithMessage("Permission denied", http.StatusForbidden)
+		return apperror.NewCode(apperror.PermissionDenied, "Permission denied")
 	}
 
 	update := map[string]any{
 		constants.Name:        req.Name,
 		constants.Description: req.Description,
 	}
-	err = s.groupRepo.Update(ctx, map[string]any{constants.ID: groupID}, update)
-	if err.Exists() {
-		log.Printf("%s failed to update group %d: %v", logTag, groupID, err)
 
-		return apperror.NewWithMessage("Failed to update group", http.StatusBadRequest)
-	}
+	return s.groupRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		err := s.groupRepo.UpdateWithVersion(txCtx, map[string]any{constants.ID: groupID}, version, update)
+		if err.Exists() {
+			if errors.Is(err, baseRepository.ErrVersionConflict) {
+				log.Warnf("group %d is no longer at version %d", groupID, version)
+
+				return apperror.NewCode(apperror.VersionConflict, "Group was changed by someone else, fetch it again and retry")
+			}
+
+			log.Errorf("failed to update group %d: %v", groupID, err)
 
-	return apperror.Error{}
+			return apperror.NewCode(apperror.Internal, "Failed to update group")
+		}
+
+		return s.outboxSvc.Publish(txCtx, model.OutboxEvent{
+			GroupID: groupID,
+			ActorID: userID,
+			Type:    model.GroupUpdated,
+			Data:    model.ActivityData{Name: req.Name},
+		})
+	})
 }
 
 func (s *Service) RemoveGroup(ctx context.Context, userID, groupID uint64) apperror.Error {
-	logTag := util.LogPrefix(ctx, "RemoveGroup")
+	ctx, span := tracing.Start(ctx, "GroupService.RemoveGroup")
+	defer span.End()
 
-	group, err := s.groupRepo.Get(ctx, map[string]any{constants.ID: groupID})
-	if err.Exists() || group.ID == 0 {
-		log.Printf("%s failed to find group %d: %v", logTag, groupID, err)
-		return apperror.NewWithMessage("Permission denied", http.StatusForbidden)
+	log := logger.With(ctx, "RemoveGroup")
+
+	group, err := s.getGroup(ctx, groupID)
+	if err.Exists() {
+		log.Warnf("failed to find group %d: %v", groupID, err)
+		return err
 	}
 
 	hasPermission, err := s.groupPermissionSvc.HasUserPermissionInGroup(ctx, userID, groupID, model.Delete)
 	if err.Exists() || !hasPermission {
-		log.Printf("%s user %d does not have delete permission for group %d. Error: %v", logTag, userID, groupID, err)
-		return apperror.NewWithMessage("Permission denied", http.StatusForbidden)
+		log.Errorf("user %d does not have delete permission for group %d. Error: %v", userID, groupID, err)
+		return apperror.NewCode(apperror.PermissionDenied, "Permission denied")
 	}
 
-	var (
-		wg      sync.WaitGroup
-		errChan = make(chan apperror.Error, 3)
-	)
-
-	// 1. Soft-delete the group
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		updateErr := s.groupRepo.Update(ctx, map[string]any{
+	// soft-delete the group and its permissions atomically so neither outlives the other
+	return s.groupRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		updateErr := s.groupRepo.Update(txCtx, map[string]any{
 			constants.ID: groupID,
 		}, map[string]any{
 			constants.DeletedAt: time.Now(),
 		})
 		if updateErr.Exists() {
-			log.Printf("%s failed to mark group %d as deleted: %v", logTag, groupID, updateErr)
-			errChan <- apperror.NewWithMessage("Failed to delete group", http.StatusBadRequest)
+			log.Errorf("failed to mark group %d as deleted: %v", groupID, updateErr)
+			return apperror.NewCode(apperror.Internal, "Failed to delete group")
 		}
-	}()
 
-	// 2. Mark all group permissions as deleted
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		updateErr := s.groupPermissionSvc.DeleteGroupPermissions(ctx, groupID)
+		updateErr = s.groupPermissionSvc.DeleteGroupPermissions(txCtx, groupID)
 		if updateErr.Exists() {
-			log.Printf("%s failed to mark group permissions for group %d as deleted: %v", logTag, groupID, updateErr)
-			errChan <- apperror.NewWithMessage("Failed to update group permissions", http.StatusBadRequest)
-		}
-	}()
-
-	// Wait for all goroutines to complete
-	wg.Wait()
-	close(errChan)
-
-	// Return first error if any occurred
-	for e := range errChan {
-		if e.Exists() {
-			return e
+			log.Errorf("failed to mark group permissions for group %d as deleted: %v", groupID, updateErr)
+			return apperror.NewCode(apperror.Internal, "Failed to update group permissions")
 		}
-	}
 
-	return apperror.Error{}
+		return s.outboxSvc.Publish(txCtx, model.OutboxEvent{
+			GroupID: groupID,
+			ActorID: userID,
+			Type:    model.GroupDeleted,
+			Data:    model.ActivityData{Name: group.Name},
+		})
+	})
 }
 
 func (s *Service) GetUserGroupsWithPermissions(
 	ctx context.Context,
 	userID uint64,
-	filters map[string]any,
-) (model.Groups, model.GroupUserPermissions, apperror.Error) {
-	logTag := util.LogPrefix(ctx, "FetchUserAccessibleGroups")
+	q *query.Query,
+) (model.Groups, model.GroupUserPermissions, query.Page, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "GroupService.GetUserGroupsWithPermissions")
+	defer span.End()
+
+	log := logger.With(ctx, "FetchUserAccessibleGroups")
 
 	groupPermissions, err := s.groupPermissionSvc.FetchUserGroup(ctx, userID)
 	if err.Exists() {
-		log.Printf("%s failed to fetch group permissions for user %d: %v", logTag, userID, err)
+		log.Errorf("failed to fetch group permissions for user %d: %v", userID, err)
 
-		return nil, nil, apperror.NewWithMessage("Failed to fetch user group permissions", http.StatusBadRequest)
+		return nil, nil, query.Page{}, apperror.NewCode(apperror.Internal, "Failed to fetch user group permissions")
 	}
 
-	filters[constants.ID] = groupPermissions.GetUniqueGroupIDs()
-	groups, err := s.groupRepo.GetAll(ctx, filters)
+	groups, page, err := s.groupRepo.GetAllWithPagination(ctx, map[string]any{
+		constants.ID: groupPermissions.GetUniqueGroupIDs(),
+	}, q)
 	if err.Exists() {
-		log.Printf("%s failed to fetch groups for user %d: %v", logTag, userID, err)
+		log.Errorf("failed to fetch groups for user %d: %v", userID, err)
 
-		return nil, nil, apperror.NewWithMessage("Unable to fetch user groups", http.StatusBadRequest)
+		return nil, nil, page, apperror.NewCode(apperror.Internal, "Unable to fetch user groups")
 	}
 
-	return groups, groupPermissions, apperror.Error{}
+	return groups, groupPermissions, page, apperror.Error{}
 }
 
-func (s *Service) FetchGroupDetailsByUserAccess(ctx context.Context, userID, groupID uint64) (*response.GroupDetails, apperror.Error) {
-	logTag := util.LogPrefix(ctx, "FetchGroupDetailsByUserAccess")
+func (s *Service) FetchGroupDetailsByUserAccess(
+	ctx context.Context,
+	userID, groupID uint64,
+	q *query.Query,
+) (*response.GroupDetails, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "GroupService.FetchGroupDetailsByUserAccess")
+	defer span.End()
+
+	log := logger.With(ctx, "FetchGroupDetailsByUserAccess")
 
 	hasPermission, err := s.ValidateUserGroupPermission(ctx, userID, groupID, model.View)
 	if err.Exists() || !hasPermission {
-		log.Printf("%s: user %d does not have view permission for group %d. Error: %v", logTag, userID, groupID, err)
-		return nil, apperror.NewWithMessage("Permission denied", http.StatusForbidden)
+		log.Errorf("user %d does not have view permission for group %d. Error: %v", userID, groupID, err)
+		return nil, apperror.NewCode(apperror.PermissionDenied, "Permission denied")
 	}
 
-	group, err := s.groupRepo.Get(ctx, map[string]any{constants.ID: groupID})
+	group, err := s.getGroup(ctx, groupID)
 	if err.Exists() {
-		log.Printf("%s: failed to retrieve group %d: %v", logTag, groupID, err)
-		return nil, apperror.NewWithMessage("Failed to retrieve group", http.StatusBadRequest)
+		log.Errorf("failed to retrieve group %d: %v", groupID, err)
+		return nil, err
 	}
 
-	bills, err := s.billSvc.GetBills(ctx, map[string]any{constants.GroupID: groupID})
+	bills, page, err := s.billSvc.GetGroupBills(ctx, groupID, q)
 	if err.Exists() {
-		log.Printf("%s: failed to fetch bills for group %d: %v", logTag, groupID, err)
-		return nil, apperror.NewWithMessage("Failed to fetch bills", http.StatusBadRequest)
+		log.Errorf("failed to fetch bills for group %d: %v", groupID, err)
+		return nil, apperror.NewCode(apperror.Internal, "Failed to fetch bills")
 	}
 
 	users, err := s.userSvc.FetchFilteredUsers(ctx, map[string]any{
 		constants.ID: bills.ExtractUniqueUserIDs(),
 	})
 	if err.Exists() {
-		log.Printf("%s: failed to fetch users for group %d: %v", logTag, groupID, err)
-		return nil, apperror.NewWithMessage("Failed to fetch users", http.StatusBadRequest)
+		log.Errorf("failed to fetch users for group %d: %v", groupID, err)
+		return nil, apperror.NewCode(apperror.Internal, "Failed to fetch users")
 	}
 
-	return adapter.BuildGroupDetailsResponse(group, users, bills), apperror.Error{}
+	return adapter.BuildGroupDetailsResponse(group, users, bills, page), apperror.Error{}
 }
 
 func (s *Service) AssignUserToGroup(
 	ctx context.Context,
 	currentUserID, userID, groupID uint64,
 ) apperror.Error {
-	logTag := util.LogPrefix(ctx, "AssignUserToGroup")
+	ctx, span := tracing.Start(ctx, "GroupService.AssignUserToGroup")
+	defer span.End()
 
-	group, err := s.groupRepo.Get(ctx, map[string]any{
-		constants.ID: groupID,
-	})
+	log := logger.With(ctx, "AssignUserToGroup")
+
+	group, err := s.getGroup(ctx, groupID)
 	if err.Exists() {
-		log.Printf("%s failed to retrieve group %d: %v", logTag, groupID, err)
-		return apperror.NewWithMessage("Failed to retrieve group", http.StatusBadRequest)
+		log.Warnf("failed to retrieve group %d: %v", groupID, err)
+		return err
 	}
 
 	// we can later change we can allow to all users who has create or edit access
 	if group.OwnerID != currentUserID {
-		log.Printf("%s user %d is not the owner of group %d", logTag, currentUserID, groupID)
+		log.Warnf("user %d is not the owner of group %d", currentUserID, groupID)
 
-		return apperror.NewWithMessage("Unauthorized access to assign user", http.StatusForbidden)
+		return apperror.NewCode(apperror.PermissionDenied, "Unauthorized access to assign user")
 	}
 
 	filter := map[string]any{
@@ -218,22 +251,43 @@ func (s *Service) AssignUserToGroup(
 	}
 	permissions, err := s.groupPermissionSvc.GetGroupUserPermissionsByFilter(ctx, filter)
 	if err.Exists() {
-		log.Printf("%s failed to fetch existing permissions for user %d in group %d: %v", logTag, userID, groupID, err)
+		log.Errorf("failed to fetch existing permissions for user %d in group %d: %v", userID, groupID, err)
 
-		return apperror.NewWithMessage("Unable to verify existing permissions", http.StatusBadRequest)
+		return apperror.NewCode(apperror.Internal, "Unable to verify existing permissions")
 	}
 
 	if len(permissions) > 0 {
-		log.Printf("%s user %d is already assigned to group %d", logTag, userID, groupID)
-		return apperror.NewWithMessage("User already assigned to group", http.StatusBadRequest)
+		log.Warnf("user %d is already assigned to group %d", userID, groupID)
+		return apperror.NewCode(apperror.AlreadyGroupMember, "User already assigned to group")
+	}
+
+	return s.groupRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		// currently hardcore later we can provide support for all permissions
+		err := s.groupPermissionSvc.AssignGroupPermissionsToUser(txCtx, userID, groupID, model.PermissionTypes{model.View})
+		if err.Exists() {
+			log.Errorf("failed to assign user %d to group %d: %v", userID, groupID, err)
+			return err
+		}
+
+		return s.outboxSvc.Publish(txCtx, model.OutboxEvent{
+			GroupID: groupID,
+			ActorID: currentUserID,
+			Type:    model.MemberJoined,
+			Data:    model.ActivityData{UserID: userID},
+		})
+	})
+}
+
+// getGroup loads a group, reporting a missing one as GROUP_NOT_FOUND
+func (s *Service) getGroup(ctx context.Context, groupID uint64) (model.Group, apperror.Error) {
+	group, err := s.groupRepo.Get(ctx, map[string]any{constants.ID: groupID})
+	if errors.Is(err, apperror.NotFound) {
+		return model.Group{}, apperror.Wrap(err, apperror.GroupNotFound, "Group not found")
 	}
 
-	// currently hardcore later we can provide support for all permissions
-	err = s.groupPermissionSvc.AssignGroupPermissionsToUser(ctx, userID, groupID, model.PermissionTypes{model.View})
 	if err.Exists() {
-		log.Printf("%s failed to assign user %d to group %d: %v", logTag, userID, groupID, err)
-		return apperror.NewWithMessage("Failed to assign user to group", http.StatusBadRequest)
+		return model.Group{}, apperror.Wrap(err, apperror.Internal, "Failed to retrieve group")
 	}
 
-	return apperror.Error{}
+	return group, apperror.Error{}
 }
@@ -0,0 +1,338 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"main/constants"
+	"main/internal/controller/response"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/logger"
+	"main/pkg/tracing"
+	"main/pkg/validation"
+	"main/util"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	importDateLayout    = "2006-01-02"
+	importTotalBalance  = "total balance"
+	importAmountEpsilon = 0.01
+)
+
+// fixed leading columns of a Splitwise-style export, every column after these is a member email
+var importHeader = []string{"date", "description", "category", "cost", "currency"}
+
+func (s *Service) ImportGroupBills(
+	ctx context.Context,
+	userID, groupID uint64,
+	file io.Reader,
+	dryRun bool,
+) (*response.BillImportPreview, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "GroupService.ImportGroupBills")
+	defer span.End()
+
+	log := logger.With(ctx, "ImportGroupBills")
+
+	hasPermission, err := s.ValidateUserGroupPermission(ctx, userID, groupID, model.Create)
+	if err.Exists() {
+		log.Errorf("failed to validate permission for user %d on group %d: %v", userID, groupID, err)
+		return nil, err
+	}
+	if !hasPermission {
+		return nil, apperror.NewCode(apperror.PermissionDenied, "Permission denied")
+	}
+
+	records, readErr := csv.NewReader(file).ReadAll()
+	if readErr != nil {
+		log.Errorf("failed to read csv for group %d: %v", groupID, readErr)
+		return nil, apperror.NewCode(apperror.InvalidCSV, "Invalid CSV file")
+	}
+
+	if len(records) < 2 {
+		return nil, apperror.NewCode(apperror.InvalidCSV, "CSV file has no rows to import")
+	}
+
+	members, err := s.fetchGroupMembers(ctx, groupID)
+	if err.Exists() {
+		log.Errorf("failed to fetch members of group %d: %v", groupID, err)
+		return nil, err
+	}
+
+	memberColumns, err := mapImportMemberColumns(records[0], members.MapByEmail())
+	if err.Exists() {
+		log.Warnf("invalid csv header for group %d: %v", groupID, err)
+		return nil, err
+	}
+
+	preview := &response.BillImportPreview{
+		DryRun: dryRun,
+		Rows:   make([]response.BillImportRow, 0, len(records)-1),
+	}
+	bills := make(model.Bills, 0, len(records)-1)
+
+	for i, record := range records[1:] {
+		if isImportRowSkippable(record) {
+			continue
+		}
+
+		row, bill := parseImportRow(i+2, record, memberColumns)
+
+		preview.TotalRows++
+		preview.Rows = append(preview.Rows, row)
+		if len(row.Errors) > 0 {
+			continue
+		}
+
+		preview.ValidRows++
+		bill.GroupID = groupID
+		bills = append(bills, bill)
+	}
+
+	if dryRun {
+		return preview, apperror.Error{}
+	}
+
+	if preview.ValidRows != preview.TotalRows {
+		log.Warnf("%d of %d rows are invalid for group %d", preview.TotalRows-preview.ValidRows, preview.TotalRows, groupID)
+		return preview, apperror.NewCode(apperror.InvalidImportRows, "CSV contains invalid rows")
+	}
+
+	err = s.groupRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		if err := s.billSvc.CreateBills(txCtx, bills); err.Exists() {
+			log.Errorf("failed to import %d bills into group %d: %v", len(bills), groupID, err)
+			return err
+		}
+
+		return s.outboxSvc.Publish(txCtx, model.OutboxEvent{
+			GroupID: groupID,
+			ActorID: userID,
+			Type:    model.BillsImported,
+			Data:    model.ActivityData{Count: len(bills)},
+		})
+	})
+	if err.Exists() {
+		return nil, err
+	}
+
+	preview.Imported = true
+	return preview, apperror.Error{}
+}
+
+func (s *Service) fetchGroupMembers(ctx context.Context, groupID uint64) (model.Users, apperror.Error) {
+	permissions, err := s.groupPermissionSvc.GetGroupUserPermissionsByFilter(ctx, map[string]any{
+		constants.GroupID:  groupID,
+		constants.IsActive: true,
+	})
+	if err.Exists() {
+		return nil, apperror.NewCode(apperror.Internal, "Failed to fetch group members")
+	}
+
+	return s.userSvc.FetchFilteredUsers(ctx, map[string]any{
+		constants.ID: permissions.GetUniqueUserIDs(),
+	})
+}
+
+func mapImportMemberColumns(header []string, emailToUser map[string]model.User) (map[int]model.User, apperror.Error) {
+	if len(header) <= len(importHeader) {
+		return nil, apperror.NewCode(apperror.InvalidCSV, "CSV header must contain at least one member column")
+	}
+
+	for i, column := range importHeader {
+		if !strings.EqualFold(util.TrimSpace(header[i]), column) {
+			return nil, apperror.NewCode(apperror.InvalidCSV, fmt.Sprintf("CSV column %d must be %q", i+1, column))
+		}
+	}
+
+	memberColumns := make(map[int]model.User)
+	unknown := make([]string, 0)
+	for i := len(importHeader); i < len(header); i++ {
+		email := strings.ToLower(util.TrimSpace(header[i]))
+		user, ok := emailToUser[email]
+		if !ok {
+			unknown = append(unknown, email)
+			continue
+		}
+
+		memberColumns[i] = user
+	}
+
+	if len(unknown) > 0 {
+		return nil, apperror.NewCode(apperror.InvalidCSV, "CSV members are not part of the group: "+strings.Join(unknown, ", "))
+	}
+
+	return memberColumns, apperror.Error{}
+}
+
+func isImportRowSkippable(record []string) bool {
+	if len(record) == 0 {
+		return true
+	}
+
+	empty := true
+	for _, field := range record {
+		if util.TrimSpace(field) != "" {
+			empty = false
+			break
+		}
+	}
+	if empty {
+		return true
+	}
+
+	// exports end with a summary row that carries no date
+	return util.TrimSpace(record[0]) == "" && len(record) > 1 &&
+		strings.EqualFold(util.TrimSpace(record[1]), importTotalBalance)
+}
+
+func parseImportRow(rowNumber int, record []string, memberColumns map[int]model.User) (response.BillImportRow, model.Bill) {
+	row := response.BillImportRow{Row: rowNumber, Errors: make([]string, 0)}
+	bill := model.Bill{}
+
+	if len(record) < len(importHeader)+len(memberColumns) {
+		row.Errors = append(row.Errors, "row has fewer columns than the header")
+		return row, bill
+	}
+
+	row.Date = util.TrimSpace(record[0])
+	row.Description = util.TrimSpace(record[1])
+	row.Category = util.TrimSpace(record[2])
+	row.Currency = strings.ToUpper(util.TrimSpace(record[4]))
+
+	date, dateErr := time.Parse(importDateLayout, row.Date)
+	if dateErr != nil {
+		row.Errors = append(row.Errors, "date must be in YYYY-MM-DD format")
+	}
+
+	cost, costErr := strconv.ParseFloat(util.TrimSpace(record[3]), 64)
+	if costErr != nil {
+		row.Errors = append(row.Errors, "cost must be a number")
+	}
+	row.Cost = cost
+	row.Errors = append(row.Errors, validateImportRow(row, costErr == nil)...)
+
+	var (
+		payers  = make([]model.User, 0, 1)
+		amounts = make(map[uint64]float64, len(memberColumns))
+		members = make(map[uint64]model.User, len(memberColumns))
+		balance float64
+	)
+	for column, user := range memberColumns {
+		members[user.ID] = user
+		amount, err := parseImportAmount(record[column])
+		if err != nil {
+			row.Errors = append(row.Errors, fmt.Sprintf("amount for %s must be a number", user.Email))
+			continue
+		}
+
+		amounts[user.ID] = amount
+		balance += amount
+		if amount > importAmountEpsilon {
+			payers = append(payers, user)
+		}
+	}
+
+	// a bill has a single payer, rows paid by several members would have to be entered as one bill per payer
+	switch {
+	case len(payers) == 0:
+		row.Errors = append(row.Errors, "no member paid for this expense")
+	case len(payers) > 1:
+		row.Errors = append(row.Errors, "expenses paid by multiple members are not supported, "+
+			"enter them as one bill per payer")
+	default:
+		row.PaidBy = &response.User{ID: payers[0].ID, Name: payers[0].Name, Email: payers[0].Email}
+		if amounts[payers[0].ID] > cost+importAmountEpsilon {
+			row.Errors = append(row.Errors, fmt.Sprintf("%s cannot be owed more than the cost", payers[0].Email))
+		}
+	}
+
+	if math.Abs(balance) > importAmountEpsilon {
+		row.Errors = append(row.Errors, "member amounts must add up to zero")
+	}
+
+	if len(row.Errors) > 0 {
+		return row, bill
+	}
+
+	bill = model.Bill{
+		UserID:      payers[0].ID,
+		PaidAmount:  cost,
+		Description: row.Description,
+		Category:    row.Category,
+		Currency:    row.Currency,
+		CreatedAt:   date,
+		Shares:      importShares(cost, payers[0].ID, amounts),
+	}
+
+	row.Shares = make([]response.BillImportShare, 0, len(bill.Shares))
+	for _, share := range bill.Shares {
+		user := members[share.UserID]
+		row.Shares = append(row.Shares, response.BillImportShare{
+			User:   response.User{ID: user.ID, Name: user.Name, Email: user.Email},
+			Amount: share.Amount,
+		})
+	}
+
+	return row, bill
+}
+
+// importShares turns the member columns into what each member owes of cost. A member column holds what the member
+// is owed for the row, so the payer owes the cost less their amount and everybody else owes their amount negated.
+func importShares(cost float64, payerID uint64, amounts map[uint64]float64) model.BillShares {
+	shares := make(model.BillShares, 0, len(amounts))
+	for userID, amount := range amounts {
+		share := -amount
+		if userID == payerID {
+			share = cost - amount
+		}
+
+		share = math.Round(share*100) / 100
+		if share > 0 {
+			shares = append(shares, model.BillShare{UserID: userID, Amount: share})
+		}
+	}
+	slices.SortFunc(shares, func(a, b model.BillShare) int {
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+
+	return shares
+}
+
+func validateImportRow(row response.BillImportRow, hasCost bool) []string {
+	errs := make([]string, 0)
+	if row.Description == "" {
+		errs = append(errs, "description is required")
+	}
+
+	if hasCost && !validation.IsMoney(row.Cost) {
+		errs = append(errs, "cost must be a positive amount with at most 2 decimals")
+	}
+
+	if !validation.IsCurrencyCode(row.Currency) {
+		errs = append(errs, "currency must be an upper case ISO 4217 currency code")
+	}
+
+	return errs
+}
+
+func parseImportAmount(value string) (float64, error) {
+	value = util.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, errors.New("invalid amount")
+	}
+
+	return amount, nil
+}
@@ -2,24 +2,36 @@ package service
 
 import (
 	"context"
+	"io"
 	"main/internal/controller/request"
 	"main/internal/controller/response"
 	"main/internal/model"
 	"main/pkg/apperror"
+	"main/repository/query"
+	"time"
 )
 
 type Interface interface {
 	CreateGroup(ctx context.Context, userID uint64, req request.CreateGroupRequest) apperror.Error
-	UpdateGroup(ctx context.Context, userID, groupID uint64, req request.UpdateGroupRequest) apperror.Error
+	UpdateGroup(
+		ctx context.Context,
+		userID, groupID, version uint64,
+		req request.UpdateGroupRequest,
+	) apperror.Error
+
 	RemoveGroup(ctx context.Context, userID, groupID uint64) apperror.Error
 
 	GetUserGroupsWithPermissions(
 		ctx context.Context,
 		userID uint64,
-		filters map[string]any,
-	) (model.Groups, model.GroupUserPermissions, apperror.Error)
+		q *query.Query,
+	) (model.Groups, model.GroupUserPermissions, query.Page, apperror.Error)
 
-	FetchGroupDetailsByUserAccess(ctx context.Context, userID, groupID uint64) (*response.GroupDetails, apperror.Error)
+	FetchGroupDetailsByUserAccess(
+		ctx context.Context,
+		userID, groupID uint64,
+		q *query.Query,
+	) (*response.GroupDetails, apperror.Error)
 
 	AssignUserToGroup(
 		ctx context.Context,
@@ -32,9 +44,14 @@ type Interface interface {
 		req request.CreateBillRequest,
 	) apperror.Error
 
-	UpdateGroupBill(
+	GetGroupBill(
 		ctx context.Context,
 		userID, groupID, billID uint64,
+	) (response.Bill, apperror.Error)
+
+	UpdateGroupBill(
+		ctx context.Context,
+		userID, groupID, billID, version uint64,
 		req request.UpdateBillRequest,
 	) apperror.Error
 
@@ -43,6 +60,58 @@ type Interface interface {
 		userID, groupID, billID uint64,
 	) apperror.Error
 
+	ImportGroupBills(
+		ctx context.Context,
+		userID, groupID uint64,
+		file io.Reader,
+		dryRun bool,
+	) (*response.BillImportPreview, apperror.Error)
+
+	GetDeletedGroups(
+		ctx context.Context,
+		userID uint64,
+		q *query.Query,
+	) (model.Groups, query.Page, apperror.Error)
+
+	RestoreGroup(ctx context.Context, userID, groupID uint64) apperror.Error
+
+	GetGroupBillTrash(
+		ctx context.Context,
+		userID, groupID uint64,
+		q *query.Query,
+	) (response.Bills, query.Page, apperror.Error)
+
+	RestoreGroupBill(ctx context.Context, userID, groupID, billID uint64) apperror.Error
+
+	PurgeTrash(ctx context.Context, before time.Time) apperror.Error
+
+	GetGroupActivity(
+		ctx context.Context,
+		userID, groupID uint64,
+		q *query.Query,
+	) ([]response.Activity, query.Page, apperror.Error)
+
+	CreateGroupWebhook(
+		ctx context.Context,
+		userID, groupID uint64,
+		req request.CreateWebhookRequest,
+	) (response.Webhook, apperror.Error)
+
+	GetGroupWebhooks(ctx context.Context, userID, groupID uint64) ([]response.Webhook, apperror.Error)
+
+	DeleteGroupWebhook(ctx context.Context, userID, groupID, webhookID uint64) apperror.Error
+
+	GetGroupWebhookDeliveries(
+		ctx context.Context,
+		userID, groupID, webhookID uint64,
+		q *query.Query,
+	) ([]response.WebhookDelivery, query.Page, apperror.Error)
+
+	RedeliverGroupWebhook(
+		ctx context.Context,
+		userID, groupID, webhookID, deliveryID uint64,
+	) (response.WebhookDelivery, apperror.Error)
+
 	ValidateUserGroupPermission(
 		ctx context.Context,
 		userID,
@@ -1,10 +1,13 @@
 package service
 
 import (
+	activitySvc "main/internal/activity/service"
 	billSvc "main/internal/bill/service"
 	groupRepo "main/internal/group/repository"
 	groupPermissionSvc "main/internal/group_permission/service"
+	outboxSvc "main/internal/outbox/service"
 	userSvc "main/internal/user/service"
+	webhookSvc "main/internal/webhook/service"
 	"sync"
 )
 
@@ -13,6 +16,9 @@ type Service struct {
 	groupPermissionSvc groupPermissionSvc.Interface
 	billSvc            billSvc.Interface
 	userSvc            userSvc.Interface
+	activitySvc        activitySvc.Interface
+	webhookSvc         webhookSvc.Interface
+	outboxSvc          outboxSvc.Interface
 }
 
 var (
@@ -25,9 +31,20 @@ func NewService(
 	groupPermissionSvc groupPermissionSvc.Interface,
 	billSvc billSvc.Interface,
 	userSvc userSvc.Interface,
+	activitySvc activitySvc.Interface,
+	webhookSvc webhookSvc.Interface,
+	outboxSvc outboxSvc.Interface,
 ) *Service {
 	syncOnce.Do(func() {
-		svc = &Service{groupRepo: groupRepo, groupPermissionSvc: groupPermissionSvc, billSvc: billSvc, userSvc: userSvc}
+		svc = &Service{
+			groupRepo:          groupRepo,
+			groupPermissionSvc: groupPermissionSvc,
+			billSvc:            billSvc,
+			userSvc:            userSvc,
+			activitySvc:        activitySvc,
+			webhookSvc:         webhookSvc,
+			outboxSvc:          outboxSvc,
+		}
 	})
 
 	return svc
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"main/constants"
+	"main/internal/controller/adapter"
+	"main/internal/controller/response"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/logger"
+	"main/pkg/tracing"
+	baseRepository "main/repository"
+	"main/repository/query"
+	"time"
+)
+
+// GetDeletedGroups lists the trash of a user, the groups they own that were deleted and not purged yet
+func (s *Service) GetDeletedGroups(
+	ctx context.Context,
+	userID uint64,
+	q *query.Query,
+) (model.Groups, query.Page, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "GroupService.GetDeletedGroups")
+	defer span.End()
+
+	log := logger.With(ctx, "GetDeletedGroups")
+
+	groups, page, err := s.groupRepo.GetAllWithPagination(ctx, map[string]any{
+		constants.OwnerID: userID,
+	}, q, baseRepository.OnlyDeleted)
+	if err.Exists() {
+		log.Errorf("failed to fetch deleted groups of user %d: %v", userID, err)
+
+		return nil, page, apperror.NewCode(apperror.Internal, "Failed to fetch deleted groups")
+	}
+
+	return groups, page, apperror.Error{}
+}
+
+// RestoreGroup brings a deleted group back together with the permissions it had. Members lose their permissions
+// with the group, so only the owner can restore it.
+func (s *Service) RestoreGroup(ctx context.Context, userID, groupID uint64) apperror.Error {
+	ctx, span := tracing.Start(ctx, "GroupService.RestoreGroup")
+	defer span.End()
+
+	log := logger.With(ctx, "RestoreGroup")
+
+	filter := map[string]any{constants.ID: groupID}
+	group, err := s.groupRepo.Get(ctx, filter, baseRepository.OnlyDeleted)
+	if errors.Is(err, apperror.NotFound) {
+		log.Warnf("group %d is not in the trash", groupID)
+		return apperror.NewCode(apperror.GroupNotFound, "Group not found in trash")
+	}
+	if err.Exists() {
+		log.Errorf("failed to retrieve deleted group %d: %v", groupID, err)
+		return apperror.NewCode(apperror.Internal, "Failed to retrieve group")
+	}
+
+	if group.OwnerID != userID {
+		log.Warnf("user %d is not the owner of deleted group %d", userID, groupID)
+		return apperror.NewCode(apperror.PermissionDenied, "Only the owner can restore a group")
+	}
+
+	return s.groupRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		if restoreErr := s.groupRepo.Restore(txCtx, filter); restoreErr.Exists() {
+			log.Errorf("failed to restore group %d: %v", groupID, restoreErr)
+			return apperror.NewCode(apperror.Internal, "Failed to restore group")
+		}
+
+		if err := s.groupPermissionSvc.RestoreGroupPermissions(txCtx, groupID, group.DeletedAt.Time); err.Exists() {
+			return err
+		}
+
+		return s.outboxSvc.Publish(txCtx, model.OutboxEvent{
+			GroupID: groupID,
+			ActorID: userID,
+			Type:    model.GroupRestored,
+			Data:    model.ActivityData{Name: group.Name},
+		})
+	})
+}
+
+// GetGroupBillTrash lists the bills deleted from a group that were not purged yet
+func (s *Service) GetGroupBillTrash(
+	ctx context.Context,
+	userID, groupID uint64,
+	q *query.Query,
+) (response.Bills, query.Page, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "GroupService.GetGroupBillTrash")
+	defer span.End()
+
+	log := logger.With(ctx, "GetGroupBillTrash")
+
+	hasPermission, err := s.ValidateUserGroupPermission(ctx, userID, groupID, model.View)
+	if err.Exists() || !hasPermission {
+		log.Warnf("user %d cannot view the trash of group %d: %v", userID, groupID, err)
+		return nil, query.Page{}, err
+	}
+
+	bills, page, err := s.billSvc.GetDeletedGroupBills(ctx, groupID, q)
+	if err.Exists() {
+		log.Errorf("failed to fetch deleted bills of group %d: %v", groupID, err)
+		return nil, page, err
+	}
+
+	users, err := s.userSvc.FetchFilteredUsers(ctx, map[string]any{
+		constants.ID: bills.ExtractUniqueUserIDs(),
+	})
+	if err.Exists() {
+		log.Errorf("failed to fetch payers of deleted bills in group %d: %v", groupID, err)
+		return nil, page, apperror.NewCode(apperror.Internal, "Failed to fetch users")
+	}
+
+	return adapter.BuildBillsResponse(bills, users), page, apperror.Error{}
+}
+
+// RestoreGroupBill takes a bill back out of the trash, it needs the same permission as deleting it
+func (s *Service) RestoreGroupBill(ctx context.Context, userID, groupID, billID uint64) apperror.Error {
+	ctx, span := tracing.Start(ctx, "GroupService.RestoreGroupBill")
+	defer span.End()
+
+	log := logger.With(ctx, "RestoreGroupBill")
+
+	hasPermission, err := s.ValidateUserGroupPermission(ctx, userID, groupID, model.Delete)
+	if err.Exists() || !hasPermission {
+		log.Warnf("user %d cannot restore bills in group %d: %v", userID, groupID, err)
+		return err
+	}
+
+	return s.groupRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		if err := s.billSvc.RestoreBill(txCtx, groupID, billID); err.Exists() {
+			log.Errorf("failed to restore bill %d of group %d: %v", billID, groupID, err)
+			return err
+		}
+
+		return s.outboxSvc.Publish(txCtx, model.OutboxEvent{
+			GroupID: groupID,
+			ActorID: userID,
+			Type:    model.BillRestored,
+			Data:    model.ActivityData{BillID: billID},
+		})
+	})
+}
+
+// PurgeTrash permanently deletes the groups and bills deleted before the given time. The rows that belong to a
+// purged group, its bills, splits and permissions, go with it.
+func (s *Service) PurgeTrash(ctx context.Context, before time.Time) apperror.Error {
+	ctx, span := tracing.Start(ctx, "GroupService.PurgeTrash")
+	defer span.End()
+
+	log := logger.With(ctx, "PurgeTrash")
+
+	purged, err := s.groupRepo.Purge(ctx, map[string]any{}, baseRepository.DeletedBefore(before))
+	if err.Exists() {
+		log.Errorf("failed to purge groups deleted before %s: %v", before, err)
+		return apperror.NewCode(apperror.Internal, "Failed to purge deleted groups")
+	}
+
+	if purged > 0 {
+		log.Infof("purged %d groups deleted before %s", purged, before)
+	}
+
+	return s.billSvc.PurgeDeletedBills(ctx, before)
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"main/internal/controller/adapter"
+	"main/internal/controller/request"
+	"main/internal/controller/response"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/logger"
+	"main/pkg/tracing"
+	"main/repository/query"
+)
+
+// CreateGroupWebhook registers a webhook for the events of a group. The response is the only one carrying the
+// signing secret.
+func (s *Service) CreateGroupWebhook(
+	ctx context.Context,
+	userID, groupID uint64,
+	req request.CreateWebhookRequest,
+) (response.Webhook, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "GroupService.CreateGroupWebhook")
+	defer span.End()
+
+	if err := s.validateGroupOwner(ctx, userID, groupID); err.Exists() {
+		return response.Webhook{}, err
+	}
+
+	events := make([]model.ActivityType, 0, len(req.Events))
+	for _, event := range req.Events {
+		events = append(events, model.ActivityType(event))
+	}
+
+	webhook := model.Webhook{
+		GroupID:   groupID,
+		URL:       req.URL,
+		Events:    events,
+		CreatedBy: userID,
+	}
+	if err := s.webhookSvc.CreateWebhook(ctx, &webhook); err.Exists() {
+		return response.Webhook{}, err
+	}
+
+	result := adapter.BuildWebhookResponse(webhook)
+	result.Secret = webhook.Secret
+
+	return result, apperror.Error{}
+}
+
+func (s *Service) GetGroupWebhooks(ctx context.Context, userID, groupID uint64) ([]response.Webhook, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "GroupService.GetGroupWebhooks")
+	defer span.End()
+
+	if err := s.validateGroupOwner(ctx, userID, groupID); err.Exists() {
+		return nil, err
+	}
+
+	webhooks, err := s.webhookSvc.GetGroupWebhooks(ctx, groupID)
+	if err.Exists() {
+		return nil, err
+	}
+
+	return adapter.BuildWebhooksResponse(webhooks), apperror.Error{}
+}
+
+func (s *Service) DeleteGroupWebhook(ctx context.Context, userID, groupID, webhookID uint64) apperror.Error {
+	ctx, span := tracing.Start(ctx, "GroupService.DeleteGroupWebhook")
+	defer span.End()
+
+	if err := s.validateGroupOwner(ctx, userID, groupID); err.Exists() {
+		return err
+	}
+
+	return s.webhookSvc.DeleteWebhook(ctx, groupID, webhookID)
+}
+
+// GetGroupWebhookDeliveries returns one page of the delivery log of a webhook of the group
+func (s *Service) GetGroupWebhookDeliveries(
+	ctx context.Context,
+	userID, groupID, webhookID uint64,
+	q *query.Query,
+) ([]response.WebhookDelivery, query.Page, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "GroupService.GetGroupWebhookDeliveries")
+	defer span.End()
+
+	if err := s.validateGroupOwner(ctx, userID, groupID); err.Exists() {
+		return nil, query.Page{}, err
+	}
+
+	if _, err := s.webhookSvc.GetWebhook(ctx, groupID, webhookID); err.Exists() {
+		return nil, query.Page{}, err
+	}
+
+	deliveries, page, err := s.webhookSvc.GetDeliveries(ctx, webhookID, q)
+	if err.Exists() {
+		return nil, page, err
+	}
+
+	return adapter.BuildWebhookDeliveriesResponse(deliveries), page, apperror.Error{}
+}
+
+// RedeliverGroupWebhook sends an earlier delivery of a webhook of the group again
+func (s *Service) RedeliverGroupWebhook(
+	ctx context.Context,
+	userID, groupID, webhookID, deliveryID uint64,
+) (response.WebhookDelivery, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "GroupService.RedeliverGroupWebhook")
+	defer span.End()
+
+	if err := s.validateGroupOwner(ctx, userID, groupID); err.Exists() {
+		return response.WebhookDelivery{}, err
+	}
+
+	if _, err := s.webhookSvc.GetWebhook(ctx, groupID, webhookID); err.Exists() {
+		return response.WebhookDelivery{}, err
+	}
+
+	delivery, err := s.webhookSvc.Redeliver(ctx, webhookID, deliveryID)
+	if err.Exists() {
+		return response.WebhookDelivery{}, err
+	}
+
+	return adapter.BuildWebhookDeliveryResponse(delivery), apperror.Error{}
+}
+
+// validateGroupOwner lets only the owner of a group manage its webhooks, they carry a secret and see every event
+func (s *Service) validateGroupOwner(ctx context.Context, userID, groupID uint64) apperror.Error {
+	log := logger.With(ctx, "validateGroupOwner")
+
+	group, err := s.getGroup(ctx, groupID)
+	if err.Exists() {
+		log.Warnf("failed to retrieve group %d: %v", groupID, err)
+		return err
+	}
+
+	if group.OwnerID != userID {
+		log.Warnf("user %d is not the owner of group %d", userID, groupID)
+		return apperror.NewCode(apperror.PermissionDenied, "Only the owner can manage the webhooks of a group")
+	}
+
+	return apperror.Error{}
+}
@@ -8,17 +8,27 @@ package service
 
 import (
 	"context"
-	repository5 "main/internal/auth/repository"
+	repository8 "main/internal/activity/repository"
+	service6 "main/internal/activity/service"
+	repository6 "main/internal/auth/repository"
 	service3 "main/internal/auth/service"
 	repository3 "main/internal/bill/repository"
 	service2 "main/internal/bill/service"
+	repository4 "main/internal/bill_share/repository"
 	"main/internal/group/repository"
 	repository2 "main/internal/group_permission/repository"
 	"main/internal/group_permission/service"
-	repository6 "main/internal/otp/repository"
+	repository7 "main/internal/otp/repository"
 	service4 "main/internal/otp/service"
-	repository4 "main/internal/user/repository"
+	repository11 "main/internal/outbox/repository"
+	service8 "main/internal/outbox/service"
+	repository13 "main/internal/outbox_delivery/repository"
+	repository12 "main/internal/outbox_position/repository"
+	repository5 "main/internal/user/repository"
 	service5 "main/internal/user/service"
+	repository9 "main/internal/webhook/repository"
+	service7 "main/internal/webhook/service"
+	repository10 "main/internal/webhook_delivery/repository"
 	"main/pkg/db/postgres"
 )
 
@@ -26,16 +36,26 @@ import (
 
 func Wire(ctx context.Context, db *postgres.DbCluster) *Service {
 	repositoryRepository := repository.NewRepository(db)
-	repository7 := repository2.NewRepository(db)
-	serviceService := service.NewService(repository7)
-	repository8 := repository3.NewRepository(db)
-	service6 := service2.NewService(repository8)
-	repository9 := repository4.NewRepository(db)
-	repository10 := repository5.NewRepository(db)
-	service7 := service3.NewService(repository10)
-	repository11 := repository6.NewRepository(db)
-	service8 := service4.NewService(repository11)
-	service9 := service5.NewService(repository9, service7, service8)
-	service10 := NewService(repositoryRepository, serviceService, service6, service9)
-	return service10
+	repository14 := repository2.NewRepository(db)
+	serviceService := service.NewService(repository14)
+	repository15 := repository3.NewRepository(db)
+	repository16 := repository4.NewRepository(db)
+	service9 := service2.NewService(repository15, repository16)
+	repository17 := repository5.NewRepository(db)
+	repository18 := repository6.NewRepository(db)
+	service10 := service3.NewService(repository18)
+	repository19 := repository7.NewRepository(db)
+	service11 := service4.NewService(repository19)
+	service12 := service5.NewService(repository17, service10, service11)
+	repository20 := repository8.NewRepository(db)
+	service13 := service6.NewService(repository20)
+	repository21 := repository9.NewRepository(db)
+	repository22 := repository10.NewRepository(db)
+	service14 := service7.NewService(repository21, repository22)
+	repository23 := repository11.NewRepository(db)
+	repository24 := repository12.NewRepository(db)
+	repository25 := repository13.NewRepository(db)
+	service15 := service8.NewService(repository23, repository24, repository25)
+	service16 := NewService(repositoryRepository, serviceService, service9, service12, service13, service14, service15)
+	return service16
 }
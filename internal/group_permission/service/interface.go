@@ -4,6 +4,7 @@ import (
 	"context"
 	"main/internal/model"
 	"main/pkg/apperror"
+	"time"
 )
 
 type Interface interface {
@@ -34,4 +35,10 @@ type Interface interface {
 		ctx context.Context,
 		groupID uint64,
 	) apperror.Error
+
+	RestoreGroupPermissions(
+		ctx context.Context,
+		groupID uint64,
+		deletedSince time.Time,
+	) apperror.Error
 }
@@ -2,13 +2,14 @@ package service
 
 import (
 	"context"
-	"log"
+	"errors"
+	"gorm.io/gorm"
 	"main/constants"
 	"main/internal/group_permission/repository"
 	"main/internal/model"
 	"main/pkg/apperror"
-	"main/util"
-	"net/http"
+	"main/pkg/logger"
+	"main/pkg/tracing"
 	"sync"
 	"time"
 )
@@ -35,7 +36,10 @@ func (s *Service) AssignGroupPermissionsToUser(
 	userID, groupID uint64,
 	permissions []model.PermissionType,
 ) apperror.Error {
-	logTag := util.LogPrefix(ctx, "AssignGroupPermissionsToUser")
+	ctx, span := tracing.Start(ctx, "GroupPermissionService.AssignGroupPermissionsToUser")
+	defer span.End()
+
+	log := logger.With(ctx, "AssignGroupPermissionsToUser")
 
 	groupUserPermission := make(model.GroupUserPermissions, 0)
 	for _, permission := range permissions {
@@ -49,10 +53,18 @@ func (s *Service) AssignGroupPermissionsToUser(
 
 	err := s.UpdateMany(ctx, groupUserPermission)
 	if err.Exists() {
-		log.Printf("%s failed to assign permissions %v to user %d in group %d: %v",
-			logTag, permissions, userID, groupID, err)
-
-		return apperror.NewWithMessage("Failed to assign permissions", http.StatusBadRequest)
+		log.Errorf("failed to assign permissions %v to user %d in group %d: %v", permissions, userID, groupID, err)
+
+		switch {
+		case errors.Is(err, gorm.ErrDuplicatedKey):
+			return apperror.NewCode(apperror.AlreadyGroupMember, "User already has these permissions in the group")
+		case errors.Is(err, gorm.ErrForeignKeyViolated):
+			return apperror.NewCode(apperror.InvalidReference, "Group or user does not exist")
+		case errors.Is(err, gorm.ErrCheckConstraintViolated):
+			return apperror.NewCode(apperror.UnprocessableEntity, "Invalid permission type")
+		}
+
+		return apperror.NewCode(apperror.Internal, "Failed to assign permissions")
 	}
 
 	return apperror.Error{}
@@ -62,6 +74,9 @@ func (s *Service) GetGroupUserPermissionsByFilter(
 	ctx context.Context,
 	filter map[string]interface{},
 ) (model.GroupUserPermissions, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "GroupPermissionService.GetGroupUserPermissionsByFilter")
+	defer span.End()
+
 	return s.GetAll(ctx, filter)
 }
 
@@ -69,7 +84,10 @@ func (s *Service) FetchUserGroup(
 	ctx context.Context,
 	userID uint64,
 ) (model.GroupUserPermissions, apperror.Error) {
-	logTag := util.LogPrefix(ctx, "FetchUserGroupPermissions")
+	ctx, span := tracing.Start(ctx, "GroupPermissionService.FetchUserGroup")
+	defer span.End()
+
+	log := logger.With(ctx, "FetchUserGroupPermissions")
 
 	filters := map[string]any{
 		constants.UserID:   userID,
@@ -77,9 +95,9 @@ func (s *Service) FetchUserGroup(
 	}
 	records, err := s.GetAll(ctx, filters)
 	if err.Exists() {
-		log.Printf("%s failed to fetch permissions for userID: %d. Error: %v", logTag, userID, err)
+		log.Errorf("failed to fetch permissions for userID: %d. Error: %v", userID, err)
 
-		return nil, apperror.NewWithMessage("Failed to fetch group permissions", http.StatusBadRequest)
+		return nil, apperror.NewCode(apperror.Internal, "Failed to fetch group permissions")
 	}
 
 	return records, apperror.Error{}
@@ -91,7 +109,10 @@ func (s *Service) HasUserPermissionInGroup(
 	groupID uint64,
 	permission model.PermissionType,
 ) (bool, apperror.Error) {
-	logTag := util.LogPrefix(ctx, "HasUserPermissionInGroup")
+	ctx, span := tracing.Start(ctx, "GroupPermissionService.HasUserPermissionInGroup")
+	defer span.End()
+
+	log := logger.With(ctx, "HasUserPermissionInGroup")
 
 	filters := map[string]any{
 		constants.UserID:         userID,
@@ -102,15 +123,13 @@ func (s *Service) HasUserPermissionInGroup(
 
 	record, err := s.Get(ctx, filters)
 	if err.Exists() {
-		log.Printf("%s failed to check permission [%s] for user %d in group %d: %v",
-			logTag, permission, userID, groupID, err)
+		log.Errorf("failed to check permission [%s] for user %d in group %d: %v", permission, userID, groupID, err)
 
-		return false, apperror.NewWithMessage("Failed to check user permission", http.StatusBadRequest)
+		return false, apperror.NewCode(apperror.Internal, "Failed to check user permission")
 	}
 
 	if record.ID == 0 {
-		log.Printf("%s no permission [%s] found for user %d in group %d",
-			logTag, permission, userID, groupID)
+		log.Warnf("no permission [%s] found for user %d in group %d", permission, userID, groupID)
 
 		return false, apperror.Error{}
 	}
@@ -122,7 +141,10 @@ func (s *Service) DeleteGroupPermissions(
 	ctx context.Context,
 	groupID uint64,
 ) apperror.Error {
-	logTag := util.LogPrefix(ctx, "DeleteGroupPermissions")
+	ctx, span := tracing.Start(ctx, "GroupPermissionService.DeleteGroupPermissions")
+	defer span.End()
+
+	log := logger.With(ctx, "DeleteGroupPermissions")
 
 	err := s.Update(ctx, map[string]interface{}{
 		constants.GroupID: groupID,
@@ -130,9 +152,35 @@ func (s *Service) DeleteGroupPermissions(
 		constants.DeletedAt: time.Now(),
 	})
 	if err.Exists() {
-		log.Printf("%s failed to soft-delete permissions for group %d: %v", logTag, groupID, err)
+		log.Errorf("failed to soft-delete permissions for group %d: %v", groupID, err)
+
+		return apperror.NewCode(apperror.Internal, "Failed to remove permissions from group")
+	}
+
+	return apperror.Error{}
+}
+
+// RestoreGroupPermissions brings back the permissions that were removed together with the group, those deleted
+// at or after deletedSince
+func (s *Service) RestoreGroupPermissions(
+	ctx context.Context,
+	groupID uint64,
+	deletedSince time.Time,
+) apperror.Error {
+	ctx, span := tracing.Start(ctx, "GroupPermissionService.RestoreGroupPermissions")
+	defer span.End()
+
+	log := logger.With(ctx, "RestoreGroupPermissions")
+
+	err := s.Restore(ctx, map[string]interface{}{
+		constants.GroupID: groupID,
+	}, func(db *gorm.DB) *gorm.DB {
+		return db.Where(constants.DeletedAt+" >= ?", deletedSince)
+	})
+	if err.Exists() && !errors.Is(err, apperror.NotFound) {
+		log.Errorf("failed to restore permissions for group %d: %v", groupID, err)
 
-		return apperror.NewWithMessage("Failed to remove permissions from group", http.StatusBadRequest)
+		return apperror.NewCode(apperror.Internal, "Failed to restore group permissions")
 	}
 
 	return apperror.Error{}
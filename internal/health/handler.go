@@ -0,0 +1,65 @@
+package health
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"main/pkg/db/postgres"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	statusOK       = "ok"
+	statusDraining = "draining"
+	statusDegraded = "unavailable"
+)
+
+type Handler struct {
+	db          *postgres.DbCluster
+	pingTimeout time.Duration
+	draining    atomic.Bool
+}
+
+func NewHandler(db *postgres.DbCluster, pingTimeout time.Duration) *Handler {
+	return &Handler{db: db, pingTimeout: pingTimeout}
+}
+
+// Liveness only reports that the process is serving requests, a database outage must not get it restarted
+func (h *Handler) Liveness(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": statusOK})
+}
+
+// Readiness pings every database node and fails when the master is down or the server is shutting down.
+// A slave being down is only reported, reads are routed to the remaining replicas or the master meanwhile.
+func (h *Handler) Readiness(ctx *gin.Context) {
+	if h.draining.Load() {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": statusDraining})
+		return
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, h.pingTimeout)
+	defer cancel()
+
+	nodes := h.db.Ping(pingCtx)
+
+	status, code := statusOK, http.StatusOK
+	for _, node := range nodes {
+		if node.Node == postgres.MasterNode && !node.Healthy {
+			status, code = statusDegraded, http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	ctx.JSON(code, gin.H{"status": status, "nodes": nodes})
+}
+
+// Drain makes readiness fail so load balancers stop routing here before the listener closes
+func (h *Handler) Drain() {
+	h.draining.Store(true)
+}
+
+// ClusterState shows which replicas serve reads, their lag and why ejected ones were taken out
+func (h *Handler) ClusterState(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, h.db.State())
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"main/internal/model"
+	"main/pkg/apperror"
+)
+
+type Interface interface {
+	Reserve(ctx context.Context, record model.IdempotencyKey) (model.IdempotencyKey, bool, apperror.Error)
+
+	Complete(
+		ctx context.Context,
+		id uint64,
+		statusCode int,
+		contentType string,
+		headers model.ResponseHeaders,
+		body []byte,
+	) apperror.Error
+
+	Release(ctx context.Context, id uint64) apperror.Error
+
+	PurgeExpired(ctx context.Context) apperror.Error
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	config "github.com/spf13/viper"
+	"gorm.io/gorm"
+	"main/constants"
+	"main/internal/idempotency/repository"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/db/postgres"
+	"main/pkg/logger"
+	"main/pkg/tracing"
+	"main/repository/query"
+	"sync"
+	"time"
+)
+
+type Service struct {
+	repository.Interface
+}
+
+var (
+	syncOnce sync.Once
+	svc      *Service
+)
+
+func NewService(r repository.Interface) *Service {
+	syncOnce.Do(func() {
+		svc = &Service{r}
+	})
+
+	return svc
+}
+
+// Reserve claims record.Key for the user before the request runs. It returns false when the key is new and the
+// request may proceed, or true with the stored response when a completed request with the same payload already
+// used it. A different payload under the same key is rejected with 422, a first request still running with 409.
+func (s *Service) Reserve(ctx context.Context, record model.IdempotencyKey) (model.IdempotencyKey, bool, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Reserve")
+	defer span.End()
+
+	log := logger.With(ctx, "Reserve")
+
+	// a retry may arrive before the replicas caught up with the first attempt
+	ctx = postgres.WithConsistency(ctx, postgres.NewConsistency(true))
+
+	existing, found, err := s.findActive(ctx, record.UserID, record.Key)
+	if err.Exists() {
+		log.Errorf("failed to look up idempotency key for user %d: %v", record.UserID, err)
+		return model.IdempotencyKey{}, false, apperror.NewCode(apperror.Internal, "Failed to check idempotency key")
+	}
+	if found {
+		return evaluate(existing, record.RequestHash)
+	}
+
+	now := time.Now()
+	record.ExpiresAt = now.Add(config.GetDuration("idempotency.ttl"))
+
+	err = s.Create(ctx, &record)
+	if err.Exists() {
+		if !errors.Is(err, gorm.ErrDuplicatedKey) {
+			log.Errorf("failed to store idempotency key for user %d: %v", record.UserID, err)
+			return model.IdempotencyKey{}, false, apperror.NewCode(apperror.Internal, "Failed to store idempotency key")
+		}
+
+		// a concurrent request with the same key won the insert
+		existing, found, err = s.findActive(ctx, record.UserID, record.Key)
+		if err.Exists() || !found {
+			log.Warnf("idempotency key of user %d vanished after a duplicate insert: %v", record.UserID, err)
+			return model.IdempotencyKey{}, false, apperror.NewCode(apperror.IdempotencyKeyInProgress, "A request with this Idempotency-Key is already in progress")
+		}
+
+		return evaluate(existing, record.RequestHash)
+	}
+
+	return record, false, apperror.Error{}
+}
+
+// Complete stores the response of a reserved request so that retries replay it
+func (s *Service) Complete(
+	ctx context.Context,
+	id uint64,
+	statusCode int,
+	contentType string,
+	headers model.ResponseHeaders,
+	body []byte,
+) apperror.Error {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Complete")
+	defer span.End()
+
+	log := logger.With(ctx, "Complete")
+
+	err := s.Update(ctx, map[string]any{
+		constants.ID: id,
+	}, map[string]any{
+		constants.StatusCode:      statusCode,
+		constants.ContentType:     contentType,
+		constants.ResponseHeaders: headers,
+		constants.ResponseBody:    body,
+		constants.CompletedAt:     time.Now(),
+	})
+	if err.Exists() {
+		log.Errorf("failed to store response for idempotency key %d: %v", id, err)
+		return apperror.NewCode(apperror.Internal, "Failed to store idempotent response")
+	}
+
+	return apperror.Error{}
+}
+
+// Release drops a reservation whose response is not worth replaying, so the client can retry with the same key
+func (s *Service) Release(ctx context.Context, id uint64) apperror.Error {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Release")
+	defer span.End()
+
+	log := logger.With(ctx, "Release")
+
+	err := s.Delete(ctx, map[string]any{constants.ID: id})
+	if err.Exists() {
+		log.Errorf("failed to release idempotency key %d: %v", id, err)
+		return apperror.NewCode(apperror.Internal, "Failed to release idempotency key")
+	}
+
+	return apperror.Error{}
+}
+
+// PurgeExpired deletes the keys whose TTL has passed
+func (s *Service) PurgeExpired(ctx context.Context) apperror.Error {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.PurgeExpired")
+	defer span.End()
+
+	log := logger.With(ctx, "PurgeExpired")
+
+	err := s.Delete(ctx, map[string]any{}, func(db *gorm.DB) *gorm.DB {
+		return db.Where(constants.ExpiresAt+" < ?", time.Now())
+	})
+	if err.Exists() {
+		log.Errorf("failed to purge expired idempotency keys: %v", err)
+		return apperror.NewCode(apperror.Internal, "Failed to purge idempotency keys")
+	}
+
+	return apperror.Error{}
+}
+
+// findActive returns the live key of the user. Expired keys and reservations abandoned by a request that never
+// finished, for instance because the instance died, are deleted so that the key can be reserved again.
+func (s *Service) findActive(ctx context.Context, userID uint64, key string) (model.IdempotencyKey, bool, apperror.Error) {
+	records, err := s.Find(ctx, query.New().
+		Eq(constants.UserID, userID).
+		Eq(constants.Key, key).
+		Limit(1),
+	)
+	if err.Exists() || len(records) == 0 {
+		return model.IdempotencyKey{}, false, err
+	}
+
+	record := records[0]
+	now := time.Now()
+	abandoned := !record.IsCompleted() && record.CreatedAt.Add(config.GetDuration("idempotency.lockTimeout")).Before(now)
+	if !record.ExpiresAt.Before(now) && !abandoned {
+		return record, true, apperror.Error{}
+	}
+
+	err = s.Delete(ctx, map[string]any{constants.ID: record.ID})
+	return model.IdempotencyKey{}, false, err
+}
+
+func evaluate(existing model.IdempotencyKey, requestHash string) (model.IdempotencyKey, bool, apperror.Error) {
+	switch {
+	case existing.RequestHash != requestHash:
+		return model.IdempotencyKey{}, false, apperror.NewCode(
+			apperror.IdempotencyKeyReused, "Idempotency-Key was already used with a different request")
+	case !existing.IsCompleted():
+		return model.IdempotencyKey{}, false, apperror.NewCode(
+			apperror.IdempotencyKeyInProgress, "A request with this Idempotency-Key is already in progress")
+	default:
+		return existing, true, apperror.Error{}
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"main/constants"
 	"main/pkg/apperror"
-	"net/http"
+	"time"
 )
 
 type Claims struct {
@@ -20,8 +20,14 @@ type UserDetails struct {
 func GetUserID(ctx *gin.Context) (uint64, apperror.Error) {
 	userDetails, ok := ctx.Value(constants.PrivateUserDetails).(*UserDetails)
 	if !ok {
-		return 0, apperror.NewWithMessage("user details missing in context", http.StatusUnauthorized)
+		return 0, apperror.NewCode(apperror.Unauthenticated, "user details missing in context")
 	}
 
 	return userDetails.UserID, apperror.Error{}
 }
+
+// GetTokenExpiry is when the access token of the request expires, long-lived responses must not outlast it
+func GetTokenExpiry(ctx *gin.Context) (time.Time, bool) {
+	expiresAt, ok := ctx.Value(constants.TokenExpiresAt).(time.Time)
+	return expiresAt, ok
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"main/constants"
+	"main/repository/query"
+	"time"
+)
+
+type ActivityType string
+
+const (
+	GroupCreated       ActivityType = "group.created"
+	GroupUpdated       ActivityType = "group.updated"
+	GroupDeleted       ActivityType = "group.deleted"
+	GroupRestored      ActivityType = "group.restored"
+	MemberJoined       ActivityType = "member.joined"
+	BillCreated        ActivityType = "bill.created"
+	BillUpdated        ActivityType = "bill.updated"
+	BillDeleted        ActivityType = "bill.deleted"
+	BillRestored       ActivityType = "bill.restored"
+	BillsImported      ActivityType = "bills.imported"
+	SplitCalculated    ActivityType = "split.calculated"
+	SettlementRecorded ActivityType = "settlement.recorded"
+)
+
+// Activity is one entry of the feed of a group: who did what, with what it was about kept in Data so the entry
+// still reads right after the bill or group it mentions changed
+type Activity struct {
+	ID        uint64       `json:"id" gorm:"primaryKey"`
+	GroupID   uint64       `json:"group_id" gorm:"not null;index:idx_activities_group_created_at,priority:1"`
+	ActorID   uint64       `json:"actor_id" gorm:"not null"`
+	Type      ActivityType `json:"type" gorm:"not null"`
+	Data      ActivityData `json:"data" gorm:"serializer:json;type:jsonb;not null"`
+	CreatedAt time.Time    `json:"created_at" gorm:"index:idx_activities_group_created_at,priority:2"`
+}
+
+// ActivityData holds what an activity was about, only the fields that apply to its type are set
+type ActivityData struct {
+	BillID       uint64  `json:"bill_id,omitempty"`
+	SettlementID uint64  `json:"settlement_id,omitempty"`
+	UserID       uint64  `json:"user_id,omitempty"`
+	Name         string  `json:"name,omitempty"`
+	Description  string  `json:"description,omitempty"`
+	Amount       float64 `json:"amount,omitempty"`
+	Currency     string  `json:"currency,omitempty"`
+	Count        int     `json:"count,omitempty"`
+}
+
+type Activities []Activity
+
+func (Activity) FilterColumns() query.Columns {
+	return query.Columns{
+		constants.ID:        {Name: constants.ID, Kind: query.Int, Sortable: true},
+		constants.ActorID:   {Name: constants.ActorID, Kind: query.Int},
+		constants.Type:      {Name: constants.Type, Kind: query.String},
+		constants.CreatedAt: {Name: constants.CreatedAt, Kind: query.Time, Sortable: true},
+	}
+}
+
+// ExtractUniqueUserIDs returns the actors and the members the activities mention
+func (a Activities) ExtractUniqueUserIDs() []uint64 {
+	userIDs := make([]uint64, 0)
+	seen := make(map[uint64]struct{})
+
+	for _, activity := range a {
+		for _, userID := range []uint64{activity.ActorID, activity.Data.UserID} {
+			if _, ok := seen[userID]; userID != 0 && !ok {
+				userIDs = append(userIDs, userID)
+				seen[userID] = struct{}{}
+			}
+		}
+	}
+
+	return userIDs
+}
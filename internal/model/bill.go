@@ -2,22 +2,41 @@ package model
 
 import (
 	"gorm.io/gorm"
+	"main/constants"
+	"main/repository/query"
 	"time"
 )
 
 type Bill struct {
-	ID          uint64         `json:"id"`
-	UserID      uint64         `json:"user_id"`
-	GroupID     uint64         `json:"group_id"`
-	PaidAmount  float64        `json:"paid_amount"`
-	Description string         `json:"description"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"deleted_at"`
+	ID          uint64  `json:"id" gorm:"primaryKey"`
+	UserID      uint64  `json:"user_id" gorm:"not null"`
+	GroupID     uint64  `json:"group_id" gorm:"not null;index:idx_bills_group_created_at,priority:1"`
+	PaidAmount  float64 `json:"paid_amount" gorm:"not null;check:chk_bills_paid_amount,paid_amount > 0"`
+	Description string  `json:"description"`
+	Category    string  `json:"category"`
+	Currency    string  `json:"currency"`
+	Version     uint64  `json:"version" gorm:"not null;default:1"`
+	// Shares are stored alongside by CreateBills, empty for bills shared equally
+	Shares    BillShares     `json:"shares,omitempty" gorm:"-"`
+	CreatedAt time.Time      `json:"created_at" gorm:"index:idx_bills_group_created_at,priority:2"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
 
 type Bills []Bill
 
+func (Bill) FilterColumns() query.Columns {
+	return query.Columns{
+		constants.ID:          {Name: constants.ID, Kind: query.Int, Sortable: true},
+		"payer_id":            {Name: constants.UserID, Kind: query.Int},
+		constants.PaidAmount:  {Name: constants.PaidAmount, Kind: query.Float, Sortable: true},
+		constants.Category:    {Name: constants.Category, Kind: query.String},
+		constants.Currency:    {Name: constants.Currency, Kind: query.String},
+		constants.Description: {Name: constants.Description, Kind: query.String, Operators: []query.Operator{query.Like}},
+		constants.CreatedAt:   {Name: constants.CreatedAt, Kind: query.Time, Sortable: true},
+	}
+}
+
 func (b Bills) ExtractUniqueUserIDs() []uint64 {
 	uniqueUserIDs := make([]uint64, 0)
 	uniqueUserIDMap := make(map[uint64]struct{})
@@ -31,3 +50,23 @@ func (b Bills) ExtractUniqueUserIDs() []uint64 {
 
 	return uniqueUserIDs
 }
+
+// IDs lists the IDs of the bills
+func (b Bills) IDs() []uint64 {
+	ids := make([]uint64, 0, len(b))
+	for _, bill := range b {
+		ids = append(ids, bill.ID)
+	}
+
+	return ids
+}
+
+// WithShares attaches the shares to the bills they belong to
+func (b Bills) WithShares(shares BillShares) Bills {
+	byBill := shares.MapByBillID()
+	for i := range b {
+		b[i].Shares = byBill[b[i].ID]
+	}
+
+	return b
+}
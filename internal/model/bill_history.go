@@ -1,6 +1,6 @@
 package model
 
 type BillHistory struct {
-	ID     uint64
-	BillID uint64
+	ID     uint64 `gorm:"primaryKey"`
+	BillID uint64 `gorm:"not null;index"`
 }
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// BillShare is what one member owes of a bill. Bills that carry shares are split by them, bills without any are
+// shared equally.
+type BillShare struct {
+	ID        uint64    `json:"id" gorm:"primaryKey"`
+	BillID    uint64    `json:"bill_id" gorm:"not null;uniqueIndex:uq_bill_shares_bill_user,priority:1"`
+	UserID    uint64    `json:"user_id" gorm:"not null;uniqueIndex:uq_bill_shares_bill_user,priority:2;index"`
+	Amount    float64   `json:"amount" gorm:"not null;check:chk_bill_shares_amount,amount > 0"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type BillShares []BillShare
+
+// MapByBillID groups the shares by the bill they belong to
+func (s BillShares) MapByBillID() map[uint64]BillShares {
+	byBill := make(map[uint64]BillShares)
+	for _, share := range s {
+		byBill[share.BillID] = append(byBill[share.BillID], share)
+	}
+
+	return byBill
+}
+
+// ExtractUniqueUserIDs lists the members holding any of the shares
+func (s BillShares) ExtractUniqueUserIDs() []uint64 {
+	uniqueUserIDs := make([]uint64, 0)
+	uniqueUserIDMap := make(map[uint64]struct{})
+
+	for _, share := range s {
+		if _, exists := uniqueUserIDMap[share.UserID]; !exists {
+			uniqueUserIDs = append(uniqueUserIDs, share.UserID)
+			uniqueUserIDMap[share.UserID] = struct{}{}
+		}
+	}
+
+	return uniqueUserIDs
+}
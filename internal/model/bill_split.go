@@ -2,19 +2,97 @@ package model
 
 import (
 	"gorm.io/gorm"
+	"main/constants"
+	"main/repository/query"
 	"time"
 )
 
 type BillSplit struct {
-	ID          uint64         `json:"id"`
-	GroupID     uint64         `json:"group_id"`
-	ToPayUserID uint64         `json:"to_pay_user_id"`
-	UserID      uint64         `json:"user_id"`
-	AmountDue   float64        `json:"amount_due"`
-	IsPaid      bool           `json:"is_paid"`
+	ID          uint64         `json:"id" gorm:"primaryKey"`
+	GroupID     uint64         `json:"group_id" gorm:"not null;index"`
+	ToPayUserID uint64         `json:"to_pay_user_id" gorm:"not null;index"`
+	UserID      uint64         `json:"user_id" gorm:"not null;index"`
+	AmountDue   float64        `json:"amount_due" gorm:"not null;check:chk_bill_splits_amount_due,amount_due >= 0"`
+	Currency    string         `json:"currency" gorm:"not null"`
+	IsPaid      bool           `json:"is_paid" gorm:"not null"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"deleted_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
 
 type BillSplits []*BillSplit
+
+func (BillSplit) FilterColumns() query.Columns {
+	return query.Columns{
+		constants.ID:          {Name: constants.ID, Kind: query.Int, Sortable: true},
+		constants.UserID:      {Name: constants.UserID, Kind: query.Int},
+		constants.ToPayUserID: {Name: constants.ToPayUserID, Kind: query.Int},
+		constants.AmountDue:   {Name: constants.AmountDue, Kind: query.Float, Sortable: true},
+		constants.IsPaid:      {Name: constants.IsPaid, Kind: query.Bool},
+		constants.Currency:    {Name: constants.Currency, Kind: query.String},
+		constants.CreatedAt:   {Name: constants.CreatedAt, Kind: query.Time, Sortable: true},
+	}
+}
+
+// CurrencyBalances is GroupBalances for each currency on its own, amounts in different currencies never offset
+// each other
+func CurrencyBalances(bills Bills, settlements Settlements) map[string]map[uint64]float64 {
+	billsByCurrency := make(map[string]Bills)
+	for _, bill := range bills {
+		billsByCurrency[bill.Currency] = append(billsByCurrency[bill.Currency], bill)
+	}
+
+	settlementsByCurrency := make(map[string]Settlements)
+	for _, settlement := range settlements {
+		settlementsByCurrency[settlement.Currency] = append(settlementsByCurrency[settlement.Currency], settlement)
+	}
+
+	balances := make(map[string]map[uint64]float64, len(billsByCurrency))
+	for currency, currencyBills := range billsByCurrency {
+		balances[currency] = GroupBalances(currencyBills, settlementsByCurrency[currency])
+	}
+	for currency, currencySettlements := range settlementsByCurrency {
+		if _, ok := balances[currency]; !ok {
+			balances[currency] = GroupBalances(nil, currencySettlements)
+		}
+	}
+
+	return balances
+}
+
+// GroupBalances is what each member of a group is owed after bills and settlements of a single currency, negative
+// when they owe. A bill
+// with shares is owed by its share holders, the other bills are shared equally among the members who paid any of
+// them. Money already paid back moves the debtor up and the creditor down.
+func GroupBalances(bills Bills, settlements Settlements) map[uint64]float64 {
+	balances := make(map[uint64]float64)
+	memberSpend := make(map[uint64]float64)
+	var total float64
+	for _, bill := range bills {
+		if len(bill.Shares) > 0 {
+			balances[bill.UserID] += bill.PaidAmount
+			for _, share := range bill.Shares {
+				balances[share.UserID] -= share.Amount
+			}
+
+			continue
+		}
+
+		memberSpend[bill.UserID] += bill.PaidAmount
+		total += bill.PaidAmount
+	}
+
+	if len(memberSpend) > 0 {
+		perHead := total / float64(len(memberSpend))
+		for uid, paid := range memberSpend {
+			balances[uid] += paid - perHead
+		}
+	}
+
+	for _, settlement := range settlements {
+		balances[settlement.FromUserID] += settlement.Amount
+		balances[settlement.ToUserID] -= settlement.Amount
+	}
+
+	return balances
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// Contact is one direction of a friendship, adding a contact stores a row for each side
+type Contact struct {
+	ID            uint64         `json:"id" gorm:"primaryKey"`
+	UserID        uint64         `json:"user_id" gorm:"not null;uniqueIndex:idx_contacts_user_contact,priority:1,where:deleted_at IS NULL"`
+	ContactUserID uint64         `json:"contact_user_id" gorm:"not null;uniqueIndex:idx_contacts_user_contact,priority:2,where:deleted_at IS NULL"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+}
+
+type Contacts []Contact
+
+func (c Contacts) GetContactUserIDs() []uint64 {
+	contactUserIDs := make([]uint64, 0, len(c))
+	for _, contact := range c {
+		contactUserIDs = append(contactUserIDs, contact.ContactUserID)
+	}
+
+	return contactUserIDs
+}
+
+// ContactInvite asks the owner of an email to become a contact of the inviter. It turns into contacts once they
+// accept it, an email that is not registered yet can accept after signing up.
+type ContactInvite struct {
+	ID         uint64         `json:"id" gorm:"primaryKey"`
+	InviterID  uint64         `json:"inviter_id" gorm:"not null;uniqueIndex:idx_contact_invites_inviter_email,priority:1,where:deleted_at IS NULL"`
+	Email      string         `json:"email" gorm:"not null;index;uniqueIndex:idx_contact_invites_inviter_email,priority:2,where:deleted_at IS NULL"`
+	AcceptedAt *time.Time     `json:"accepted_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+}
+
+type ContactInvites []ContactInvite
+
+func (c ContactInvites) GetInviterIDs() []uint64 {
+	inviterIDs := make([]uint64, 0, len(c))
+	for _, invite := range c {
+		inviterIDs = append(inviterIDs, invite.InviterID)
+	}
+
+	return inviterIDs
+}
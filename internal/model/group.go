@@ -2,19 +2,31 @@ package model
 
 import (
 	"gorm.io/gorm"
+	"main/constants"
+	"main/repository/query"
 	"time"
 )
 
 type Group struct {
-	ID          uint64         `json:"id"`
-	OwnerID     uint64         `json:"owner_id"`
-	Name        string         `json:"name"`
+	ID          uint64         `json:"id" gorm:"primaryKey"`
+	OwnerID     uint64         `json:"owner_id" gorm:"not null;index"`
+	Name        string         `json:"name" gorm:"not null"`
 	Description string         `json:"description"`
 	CreatedBy   string         `json:"created_by"`
 	UpdatedBy   string         `json:"updated_by"`
+	Version     uint64         `json:"version" gorm:"not null;default:1"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"deleted_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
 
 type Groups []Group
+
+func (Group) FilterColumns() query.Columns {
+	return query.Columns{
+		constants.ID:        {Name: constants.ID, Kind: query.Int, Sortable: true},
+		constants.Name:      {Name: constants.Name, Kind: query.String, Sortable: true},
+		constants.OwnerID:   {Name: constants.OwnerID, Kind: query.Int},
+		constants.CreatedAt: {Name: constants.CreatedAt, Kind: query.Time, Sortable: true},
+	}
+}
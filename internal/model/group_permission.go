@@ -35,14 +35,14 @@ func (p PermissionTypes) ToStringSlice() []string {
 type PermissionTypes []PermissionType
 
 type GroupUserPermission struct {
-	ID             uint64         `json:"id"`
-	GroupID        uint64         `json:"group_id"`
-	UserID         uint64         `json:"user_id"`
-	PermissionType PermissionType `json:"permission_type"`
-	IsActive       bool           `json:"is_active"`
+	ID             uint64         `json:"id" gorm:"primaryKey"`
+	GroupID        uint64         `json:"group_id" gorm:"not null;uniqueIndex:idx_group_user_permissions_group_user_type,priority:1,where:deleted_at IS NULL"`
+	UserID         uint64         `json:"user_id" gorm:"not null;index;uniqueIndex:idx_group_user_permissions_group_user_type,priority:2,where:deleted_at IS NULL"`
+	PermissionType PermissionType `json:"permission_type" gorm:"not null;uniqueIndex:idx_group_user_permissions_group_user_type,priority:3,where:deleted_at IS NULL"`
+	IsActive       bool           `json:"is_active" gorm:"not null"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
-	DeletedAt      gorm.DeletedAt `json:"deleted_at"`
+	DeletedAt      gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
 
 type GroupUserPermissions []GroupUserPermission
@@ -65,6 +65,24 @@ func (g GroupUserPermissions) GetUniqueGroupIDs() []uint64 {
 	return uniqueGroupIDs
 }
 
+func (g GroupUserPermissions) GetUniqueUserIDs() []uint64 {
+	uniqueUserIDs := make([]uint64, 0)
+	uniqueUserID := make(map[uint64]struct{})
+	if g == nil || len(g) == 0 {
+		return uniqueUserIDs
+	}
+
+	for _, permission := range g {
+		if _, ok := uniqueUserID[permission.UserID]; !ok {
+			uniqueUserIDs = append(uniqueUserIDs, permission.UserID)
+		}
+
+		uniqueUserID[permission.UserID] = struct{}{}
+	}
+
+	return uniqueUserIDs
+}
+
 func (g GroupUserPermissions) MapGroupIDToPermissions() map[uint64]PermissionTypes {
 	groupIDMapPermissionTypes := make(map[uint64]PermissionTypes)
 	if g == nil || len(g) == 0 {
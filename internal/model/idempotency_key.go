@@ -0,0 +1,59 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// IdempotencyKey remembers the outcome of a mutating request so that a retry carrying the same key
+// gets the stored response instead of running the request again. Rows are deleted outright once expired.
+type IdempotencyKey struct {
+	ID          uint64 `gorm:"primaryKey"`
+	UserID      uint64 `gorm:"not null;uniqueIndex:idx_idempotency_keys_user_key,priority:1"`
+	Key         string `gorm:"not null;uniqueIndex:idx_idempotency_keys_user_key,priority:2"`
+	Method      string `gorm:"not null"`
+	Path        string `gorm:"not null"`
+	RequestHash string `gorm:"not null"`
+	StatusCode  int
+	ContentType string
+	// headers of the response worth replaying besides its content type, keyed by canonical name
+	ResponseHeaders ResponseHeaders `gorm:"type:jsonb;not null;default:'{}'"`
+	ResponseBody    []byte
+	CompletedAt     *time.Time
+	ExpiresAt       time.Time `gorm:"not null;index"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// IsCompleted reports whether the response of the first request has been stored
+func (k IdempotencyKey) IsCompleted() bool {
+	return k.CompletedAt != nil
+}
+
+// ResponseHeaders is stored as a JSON object, it is also written with map updates which bypass gorm serializers
+type ResponseHeaders map[string]string
+
+func (h ResponseHeaders) Value() (driver.Value, error) {
+	if h == nil {
+		return "{}", nil
+	}
+
+	raw, err := json.Marshal(h)
+	return string(raw), err
+}
+
+func (h *ResponseHeaders) Scan(value any) error {
+	switch raw := value.(type) {
+	case nil:
+		*h = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(raw, h)
+	case string:
+		return json.Unmarshal([]byte(raw), h)
+	default:
+		return errors.New("unsupported response headers value")
+	}
+}
@@ -13,13 +13,13 @@ const (
 )
 
 type OTP struct {
-	ID        uint64
-	UserID    uint64
-	Code      string
-	Purpose   Purpose
+	ID        uint64  `gorm:"primaryKey"`
+	UserID    uint64  `gorm:"not null;index:idx_otps_user_purpose_used,priority:1"`
+	Code      string  `gorm:"not null"`
+	Purpose   Purpose `gorm:"not null;index:idx_otps_user_purpose_used,priority:2"`
 	ExpiresAt time.Time
-	Used      bool
+	Used      bool `gorm:"not null;index:idx_otps_user_purpose_used,priority:3"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// OutboxEvent is a domain event of a group. It is written in the same transaction as the change it describes, so
+// it exists if and only if the change was committed, and the relay hands it to the subscribers from there.
+type OutboxEvent struct {
+	ID        uint64       `json:"id" gorm:"primaryKey"`
+	GroupID   uint64       `json:"group_id" gorm:"not null"`
+	ActorID   uint64       `json:"actor_id" gorm:"not null"`
+	Type      ActivityType `json:"type" gorm:"not null"`
+	Data      ActivityData `json:"data" gorm:"serializer:json;type:jsonb;not null"`
+	CreatedAt time.Time    `json:"created_at" gorm:"index"`
+}
+
+// OutboxPosition is the highest event id the relay handed to a subscriber, its row is locked while relaying to it
+type OutboxPosition struct {
+	Subscriber string `gorm:"primaryKey"`
+	EventID    uint64 `gorm:"not null"`
+	UpdatedAt  time.Time
+}
+
+// OutboxDelivery records that the relay handed an event to a subscriber. Events are picked by the missing
+// delivery rather than by id, an event committed after later ids were relayed is still received.
+type OutboxDelivery struct {
+	Subscriber string `gorm:"primaryKey"`
+	EventID    uint64 `gorm:"primaryKey"`
+	CreatedAt  time.Time
+}
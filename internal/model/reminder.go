@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// ReminderSetting is how often the debtors of a group are reminded of what they owe. Groups without one are not
+// reminded.
+type ReminderSetting struct {
+	GroupID      uint64    `json:"group_id" gorm:"primaryKey"`
+	Enabled      bool      `json:"enabled" gorm:"not null"`
+	IntervalDays int       `json:"interval_days" gorm:"not null;check:chk_reminder_settings_interval_days,interval_days > 0"`
+	UpdatedBy    uint64    `json:"updated_by" gorm:"not null"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Interval is the wait between two reminders of the same debt
+func (r ReminderSetting) Interval() time.Duration {
+	return time.Duration(r.IntervalDays) * 24 * time.Hour
+}
+
+// PaymentReminder is the reminder state between a debtor, UserID, and a creditor, ToPayUserID, of a group. It is
+// kept per pair rather than per split, so recalculating the splits keeps snoozes and cooldowns.
+type PaymentReminder struct {
+	ID             uint64     `json:"id" gorm:"primaryKey"`
+	GroupID        uint64     `json:"group_id" gorm:"not null;uniqueIndex:idx_payment_reminders_pair"`
+	UserID         uint64     `json:"user_id" gorm:"not null;uniqueIndex:idx_payment_reminders_pair"`
+	ToPayUserID    uint64     `json:"to_pay_user_id" gorm:"not null;uniqueIndex:idx_payment_reminders_pair"`
+	LastRemindedAt *time.Time `json:"last_reminded_at"`
+	SnoozedUntil   *time.Time `json:"snoozed_until"`
+	LastNudgedAt   *time.Time `json:"last_nudged_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type PaymentReminders []PaymentReminder
+
+// Due tells whether the debtor of split should be reminded at now. A reminder is due interval after the split was
+// created or the debtor last heard about it, by reminder or by nudge, unless they snoozed past now.
+func (r PaymentReminder) Due(split BillSplit, interval time.Duration, now time.Time) bool {
+	if r.SnoozedUntil != nil && now.Before(*r.SnoozedUntil) {
+		return false
+	}
+
+	last := split.CreatedAt
+	for _, at := range []*time.Time{r.LastRemindedAt, r.LastNudgedAt} {
+		if at != nil && at.After(last) {
+			last = *at
+		}
+	}
+
+	return !now.Before(last.Add(interval))
+}
+
+// NudgeAllowedAt is when the creditor may nudge again, the zero time if they never did
+func (r PaymentReminder) NudgeAllowedAt(cooldown time.Duration) time.Time {
+	if r.LastNudgedAt == nil {
+		return time.Time{}
+	}
+
+	return r.LastNudgedAt.Add(cooldown)
+}
+
+// MapByPair indexes reminders by debtor and creditor
+func (r PaymentReminders) MapByPair() map[[2]uint64]PaymentReminder {
+	pairs := make(map[[2]uint64]PaymentReminder, len(r))
+	for _, reminder := range r {
+		pairs[[2]uint64{reminder.UserID, reminder.ToPayUserID}] = reminder
+	}
+
+	return pairs
+}
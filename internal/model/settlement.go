@@ -0,0 +1,48 @@
+package model
+
+import (
+	"main/constants"
+	"main/repository/query"
+	"time"
+)
+
+// Settlement is a payment a debtor made to a creditor of the group outside the app, it pays down their split
+type Settlement struct {
+	ID         uint64    `json:"id" gorm:"primaryKey"`
+	GroupID    uint64    `json:"group_id" gorm:"not null;index:idx_settlements_group_created_at,priority:1"`
+	FromUserID uint64    `json:"from_user_id" gorm:"not null;index"`
+	ToUserID   uint64    `json:"to_user_id" gorm:"not null;index"`
+	Amount     float64   `json:"amount" gorm:"not null;check:chk_settlements_amount,amount > 0"`
+	Currency   string    `json:"currency" gorm:"not null"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index:idx_settlements_group_created_at,priority:2"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type Settlements []Settlement
+
+func (Settlement) FilterColumns() query.Columns {
+	return query.Columns{
+		constants.ID:         {Name: constants.ID, Kind: query.Int, Sortable: true},
+		constants.FromUserID: {Name: constants.FromUserID, Kind: query.Int},
+		constants.ToUserID:   {Name: constants.ToUserID, Kind: query.Int},
+		constants.Amount:     {Name: constants.Amount, Kind: query.Float, Sortable: true},
+		constants.CreatedAt:  {Name: constants.CreatedAt, Kind: query.Time, Sortable: true},
+	}
+}
+
+func (s Settlements) ExtractUniqueUserIDs() []uint64 {
+	userIDs := make([]uint64, 0)
+	seen := make(map[uint64]struct{})
+
+	for _, settlement := range s {
+		for _, userID := range []uint64{settlement.FromUserID, settlement.ToUserID} {
+			if _, ok := seen[userID]; !ok {
+				userIDs = append(userIDs, userID)
+				seen[userID] = struct{}{}
+			}
+		}
+	}
+
+	return userIDs
+}
@@ -0,0 +1,96 @@
+package model
+
+import "time"
+
+// StatementMonthLayout is how the month of a statement is written, e.g. 2026-09
+const StatementMonthLayout = "2006-01"
+
+// Statement is the monthly statement of a group. It is generated once the month is over and kept as it was, later
+// changes to the bills of the month do not rewrite it.
+type Statement struct {
+	ID        uint64        `json:"id" gorm:"primaryKey"`
+	GroupID   uint64        `json:"group_id" gorm:"not null;uniqueIndex:idx_statements_group_month"`
+	Month     string        `json:"month" gorm:"not null;uniqueIndex:idx_statements_group_month"`
+	Data      StatementData `json:"data" gorm:"serializer:json;type:jsonb;not null"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type Statements []Statement
+
+// StatementData is what a statement says. Names are taken when it is generated, so it reads the same later on.
+type StatementData struct {
+	GroupName string    `json:"group_name"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	// Currencies has the figures of each currency the group used, amounts in different currencies are never added up
+	Currencies  []StatementCurrency   `json:"currencies"`
+	Settlements []StatementSettlement `json:"settlements"`
+}
+
+// StatementCurrency is the part of a statement in one currency
+type StatementCurrency struct {
+	Currency   string              `json:"currency"`
+	TotalSpent float64             `json:"total_spent"`
+	Members    []StatementMember   `json:"members"`
+	Categories []StatementCategory `json:"categories"`
+}
+
+// StatementMember is one member of the group over the month in one currency. Balances are what the member is owed,
+// negative when they owe, at the start and at the end of the month.
+type StatementMember struct {
+	UserID         uint64              `json:"user_id"`
+	Name           string              `json:"name"`
+	Paid           float64             `json:"paid"`
+	Bills          int                 `json:"bills"`
+	SettledOut     float64             `json:"settled_out"`
+	SettledIn      float64             `json:"settled_in"`
+	OpeningBalance float64             `json:"opening_balance"`
+	ClosingBalance float64             `json:"closing_balance"`
+	Categories     []StatementCategory `json:"categories"`
+}
+
+type StatementCategory struct {
+	Category string  `json:"category"`
+	Total    float64 `json:"total"`
+	Bills    int     `json:"bills"`
+}
+
+type StatementSettlement struct {
+	ID         uint64    `json:"id"`
+	FromUserID uint64    `json:"from_user_id"`
+	ToUserID   uint64    `json:"to_user_id"`
+	Amount     float64   `json:"amount"`
+	Currency   string    `json:"currency"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// StatementPeriod is the month starting at from, in UTC
+func StatementPeriod(month time.Time) (from, to time.Time) {
+	from = time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	return from, from.AddDate(0, 1, 0)
+}
+
+// Member returns the line of a user in the currency, false when they had no bills, settlements or balance in it
+func (c StatementCurrency) Member(userID uint64) (StatementMember, bool) {
+	for _, member := range c.Members {
+		if member.UserID == userID {
+			return member, true
+		}
+	}
+
+	return StatementMember{}, false
+}
+
+// MemberNames maps the members of the statement to their names as they were when it was generated
+func (d StatementData) MemberNames() map[uint64]string {
+	names := make(map[uint64]string)
+	for _, currency := range d.Currencies {
+		for _, member := range currency.Members {
+			names[member.UserID] = member.Name
+		}
+	}
+
+	return names
+}
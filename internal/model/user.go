@@ -2,25 +2,43 @@ package model
 
 import (
 	"gorm.io/gorm"
+	"main/constants"
+	"main/repository/query"
 	"main/util"
+	"strings"
 	"time"
 )
 
 type User struct {
-	ID        uint64         `json:"id"`
-	Name      string         `json:"name"`
-	Email     string         `json:"email"`
-	Password  string         `json:"password"`
-	IsActive  bool           `json:"is_active"`
-	CreatedBy string         `json:"created_by"`
-	UpdatedBy string         `json:"updated_by"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"deleted_at"`
+	ID                  uint64         `json:"id" gorm:"primaryKey"`
+	Name                string         `json:"name"`
+	Email               string         `json:"email" gorm:"not null;uniqueIndex:idx_users_email,where:deleted_at IS NULL"`
+	Password            string         `json:"password" gorm:"not null"`
+	IsActive            bool           `json:"is_active" gorm:"not null"`
+	FailedLoginAttempts int            `json:"-" gorm:"not null"`
+	LockedUntil         *time.Time     `json:"-"`
+	CreatedBy           string         `json:"created_by"`
+	UpdatedBy           string         `json:"updated_by"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
 
 type Users []User
 
+func (u User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && u.LockedUntil.After(now)
+}
+
+// FilterColumns leaves email out on purpose, clients must not be able to probe for addresses
+func (User) FilterColumns() query.Columns {
+	return query.Columns{
+		constants.ID:        {Name: constants.ID, Kind: query.Int, Sortable: true},
+		constants.Name:      {Name: constants.Name, Kind: query.String, Sortable: true},
+		constants.CreatedAt: {Name: constants.CreatedAt, Kind: query.Time, Sortable: true},
+	}
+}
+
 func (u Users) MapByID() map[uint64]User {
 	idMapUser := make(map[uint64]User)
 	u = util.DeduplicateSlice(u)
@@ -34,3 +52,16 @@ func (u Users) MapByID() map[uint64]User {
 
 	return idMapUser
 }
+
+func (u Users) MapByEmail() map[string]User {
+	emailMapUser := make(map[string]User)
+	if u == nil || len(u) == 0 {
+		return emailMapUser
+	}
+
+	for _, user := range u {
+		emailMapUser[strings.ToLower(user.Email)] = user
+	}
+
+	return emailMapUser
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"encoding/json"
+	"main/constants"
+	"main/repository/query"
+	"slices"
+	"time"
+)
+
+// WebhookEvents are the activity types a webhook can subscribe to
+var WebhookEvents = []ActivityType{
+	BillCreated,
+	BillUpdated,
+	BillDeleted,
+	SplitCalculated,
+	SettlementRecorded,
+	MemberJoined,
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Webhook is a URL the owner of a group registered to receive the events of the group it subscribed to
+type Webhook struct {
+	ID        uint64         `json:"id" gorm:"primaryKey"`
+	GroupID   uint64         `json:"group_id" gorm:"not null;index"`
+	URL       string         `json:"url" gorm:"not null"`
+	Secret    string         `json:"-" gorm:"not null"`
+	Events    []ActivityType `json:"events" gorm:"serializer:json;type:jsonb;not null"`
+	CreatedBy uint64         `json:"created_by" gorm:"not null"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+type Webhooks []Webhook
+
+// Subscribes reports whether the webhook wants events of the given type
+func (w Webhook) Subscribes(event ActivityType) bool {
+	return slices.Contains(w.Events, event)
+}
+
+func (Webhook) FilterColumns() query.Columns {
+	return query.Columns{
+		constants.ID:        {Name: constants.ID, Kind: query.Int, Sortable: true},
+		constants.CreatedAt: {Name: constants.CreatedAt, Kind: query.Time, Sortable: true},
+	}
+}
+
+// WebhookPayload is the body of a delivery. ID is the id of the outbox event, a receiver seeing it twice, after a
+// retry or a redelivery, got the same event again.
+type WebhookPayload struct {
+	ID         uint64       `json:"id"`
+	Type       ActivityType `json:"type"`
+	GroupID    uint64       `json:"group_id"`
+	ActorID    uint64       `json:"actor_id"`
+	Data       ActivityData `json:"data"`
+	OccurredAt time.Time    `json:"occurred_at"`
+}
+
+// WebhookDelivery is one event on its way to one webhook. The pending rows are the outbox the dispatcher drains,
+// the finished ones are the delivery log.
+type WebhookDelivery struct {
+	ID            uint64          `json:"id" gorm:"primaryKey"`
+	WebhookID     uint64          `json:"webhook_id" gorm:"not null;index"`
+	Event         ActivityType    `json:"event" gorm:"not null"`
+	Payload       json.RawMessage `json:"payload" gorm:"type:jsonb;not null"`
+	Status        DeliveryStatus  `json:"status" gorm:"not null;index:idx_webhook_deliveries_status_next_attempt_at,priority:1"`
+	Attempts      int             `json:"attempts" gorm:"not null"`
+	NextAttemptAt time.Time       `json:"next_attempt_at" gorm:"not null;index:idx_webhook_deliveries_status_next_attempt_at,priority:2"`
+	StatusCode    int             `json:"status_code"`
+	ResponseBody  string          `json:"response_body"`
+	LastError     string          `json:"last_error"`
+	DeliveredAt   *time.Time      `json:"delivered_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+type WebhookDeliveries []WebhookDelivery
+
+func (WebhookDelivery) FilterColumns() query.Columns {
+	return query.Columns{
+		constants.ID:        {Name: constants.ID, Kind: query.Int, Sortable: true},
+		constants.Event:     {Name: constants.Event, Kind: query.String},
+		constants.Status:    {Name: constants.Status, Kind: query.String},
+		constants.CreatedAt: {Name: constants.CreatedAt, Kind: query.Time, Sortable: true},
+	}
+}
@@ -2,14 +2,15 @@ package service
 
 import (
 	"context"
-	"gorm.io/gorm"
-	"log"
 	"main/constants"
 	"main/internal/model"
 	"main/internal/otp/repository"
 	"main/pkg/apperror"
+	"main/pkg/logger"
+	"main/pkg/metrics"
+	"main/pkg/tracing"
+	"main/repository/query"
 	"main/util"
-	"net/http"
 	"sync"
 	"time"
 )
@@ -32,12 +33,15 @@ func NewService(r repository.Interface) *Service {
 }
 
 func (s *Service) GenerateOTP(ctx context.Context, userID uint64, purpose model.Purpose) (string, apperror.Error) {
-	logTag := util.LogPrefix(ctx, "GenerateOTP")
+	ctx, span := tracing.Start(ctx, "OtpService.GenerateOTP")
+	defer span.End()
+
+	log := logger.With(ctx, "GenerateOTP")
 
 	code, err := util.GenerateRandomNumericCode(6)
 	if err != nil {
-		log.Println(logTag, "Failed to generate OTP code:", err)
-		return "", apperror.NewWithMessage("Failed to generate OTP", http.StatusBadRequest)
+		log.Errorf("failed to generate OTP code: %v", err)
+		return "", apperror.NewCode(apperror.Internal, "Failed to generate OTP")
 	}
 
 	otp := model.OTP{
@@ -49,11 +53,12 @@ func (s *Service) GenerateOTP(ctx context.Context, userID uint64, purpose model.
 
 	createErr := s.Create(ctx, &otp)
 	if createErr.Exists() {
-		log.Println(logTag, "Failed to store OTP in DB:", createErr)
+		log.Errorf("failed to store OTP in DB: %v", createErr)
 
-		return "", apperror.NewWithMessage("Failed to create OTP", http.StatusBadRequest)
+		return "", apperror.NewCode(apperror.Internal, "Failed to create OTP")
 	}
 
+	metrics.OTPsIssued.WithLabelValues(string(purpose)).Inc()
 	return code, apperror.Error{}
 }
 
@@ -63,55 +68,55 @@ func (s *Service) ValidateOTP(
 	purpose model.Purpose,
 	otp string,
 ) (bool, apperror.Error) {
-	logTag := util.LogPrefix(ctx, "ValidateOTP")
-
-	scopes := []func(db *gorm.DB) *gorm.DB{
-		func(db *gorm.DB) *gorm.DB {
-			return db.Order("created_at DESC")
-		},
-		func(db *gorm.DB) *gorm.DB {
-			return db.Limit(1)
-		},
-		func(db *gorm.DB) *gorm.DB {
-			return db.Where("used = ?", false)
-		},
-	}
+	ctx, span := tracing.Start(ctx, "OtpService.ValidateOTP")
+	defer span.End()
 
-	otps, err := s.GetAll(ctx, map[string]any{
-		constants.UserID:  userID,
-		constants.Purpose: purpose,
-	}, scopes...)
+	log := logger.With(ctx, "ValidateOTP")
+
+	otps, err := s.Find(ctx, query.New().
+		Eq(constants.UserID, userID).
+		Eq(constants.Purpose, purpose).
+		Eq(constants.Used, false).
+		OrderBy(constants.CreatedAt, true).
+		Limit(1),
+	)
 
 	if err.Exists() {
-		log.Println(logTag, "Failed to fetch OTPs:", err)
+		log.Errorf("failed to fetch OTPs: %v", err)
 
-		return false, apperror.NewWithMessage("Unable to validate OTP", http.StatusBadRequest)
+		return false, apperror.NewCode(apperror.Internal, "Unable to validate OTP")
 	}
 
 	if len(otps) == 0 {
-		log.Println(logTag, "No valid OTP found for user:", userID)
+		log.Warnf("no valid OTP found for user: %v", userID)
+		metrics.OTPValidationFailures.WithLabelValues(string(purpose), metrics.OTPNotFound).Inc()
 
-		return false, apperror.NewWithMessage("Invalid or expired OTP", http.StatusBadRequest)
+		return false, apperror.NewCode(apperror.InvalidOTP, "Invalid or expired OTP")
 	}
 
 	latestOTP := otps[0]
 	if latestOTP.ExpiresAt.Before(time.Now()) {
-		log.Printf("%s OTP expired for user ID: %d", logTag, userID)
+		log.Warnf("OTP expired for user ID: %d", userID)
+		metrics.OTPValidationFailures.WithLabelValues(string(purpose), metrics.OTPExpired).Inc()
 
-		return false, apperror.NewWithMessage("OTP has expired", http.StatusBadRequest)
+		return false, apperror.NewCode(apperror.OTPExpired, "OTP has expired")
 	}
 
 	if latestOTP.Code != otp {
-		log.Printf("%s OTP code mismatch for user ID: %d", logTag, userID)
+		log.Warnf("OTP code mismatch for user ID: %d", userID)
+		metrics.OTPValidationFailures.WithLabelValues(string(purpose), metrics.OTPMismatch).Inc()
 
-		return false, apperror.NewWithMessage("Invalid OTP", http.StatusBadRequest)
+		return false, apperror.NewCode(apperror.InvalidOTP, "Invalid OTP")
 	}
 
 	return true, apperror.Error{}
 }
 
 func (s *Service) MarkOTPUsed(ctx context.Context, userID uint64, otpCode string) apperror.Error {
-	logTag := util.LogPrefix(ctx, "MarkOTPUsed")
+	ctx, span := tracing.Start(ctx, "OtpService.MarkOTPUsed")
+	defer span.End()
+
+	log := logger.With(ctx, "MarkOTPUsed")
 
 	err := s.Update(ctx, map[string]any{
 		constants.UserID: userID,
@@ -121,9 +126,9 @@ func (s *Service) MarkOTPUsed(ctx context.Context, userID uint64, otpCode string
 	})
 
 	if err.Exists() {
-		log.Printf("%s: failed to mark OTP as used for user %d and code %s: %v", logTag, userID, otpCode, err)
+		log.Errorf("failed to mark OTP as used for user %d: %v", userID, err)
 
-		return apperror.NewWithMessage("Unable to mark OTP as used", http.StatusBadRequest)
+		return apperror.NewCode(apperror.Internal, "Unable to mark OTP as used")
 	}
 
 	return apperror.Error{}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"time"
+)
+
+// Handler receives the events relayed to a subscriber. It runs in the transaction that advances the position of the
+// subscriber: its database writes commit together with the position, anything else may see an event more than once.
+type Handler func(ctx context.Context, event model.OutboxEvent) apperror.Error
+
+type Interface interface {
+	Publish(ctx context.Context, event model.OutboxEvent) apperror.Error
+	Subscribe(name string, handle Handler)
+	Relay(ctx context.Context)
+	PurgeRelayed(ctx context.Context, before time.Time) apperror.Error
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	config "github.com/spf13/viper"
+	"gorm.io/gorm"
+	"main/constants"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/db/postgres"
+	"main/pkg/logger"
+	"main/pkg/tracing"
+	baseRepository "main/repository"
+	"slices"
+	"time"
+)
+
+// Publish writes an event to the outbox. Callers pass the context of the transaction making the change, the event
+// is then committed or rolled back with it.
+func (s *Service) Publish(ctx context.Context, event model.OutboxEvent) apperror.Error {
+	ctx, span := tracing.Start(ctx, "OutboxService.Publish")
+	defer span.End()
+
+	log := logger.With(ctx, "Publish")
+
+	if err := s.outboxRepo.Create(ctx, &event); err.Exists() {
+		log.Errorf("failed to write %s event of group %d to the outbox: %v", event.Type, event.GroupID, err)
+		return apperror.NewCode(apperror.Internal, "Failed to record event")
+	}
+
+	return apperror.Error{}
+}
+
+// Subscribe registers a handler under a name. The name keys the deliveries, renaming a subscriber makes it start
+// over from the oldest event still in the outbox.
+func (s *Service) Subscribe(name string, handle Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subscribers = append(s.subscribers, &subscriber{name: name, handle: handle})
+}
+
+// Relay hands each subscriber the committed events it has not received yet, in id order, and marks them as
+// delivered. Subscribers progress independently, one failing is retried on the next run without holding the others
+// back. Relay runs from a single worker per process.
+func (s *Service) Relay(ctx context.Context) {
+	ctx, span := tracing.Start(ctx, "OutboxService.Relay")
+	defer span.End()
+
+	for _, sub := range s.subscriptions() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.relayTo(ctx, sub)
+	}
+}
+
+// relayTo relays one batch to a subscriber. The position row is locked for the batch so that one instance at a
+// time relays to a subscriber, the others pass over it.
+func (s *Service) relayTo(ctx context.Context, sub *subscriber) {
+	log := logger.With(ctx, "relayTo")
+
+	if err := s.ensurePosition(ctx, sub); err.Exists() {
+		log.Errorf("failed to set up the relay position of %s: %v", sub.name, err)
+		return
+	}
+
+	err := s.outboxPositionRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		position, err := s.outboxPositionRepo.Get(txCtx, map[string]any{
+			constants.Subscriber: sub.name,
+		}, baseRepository.ForUpdateSkipLocked)
+		if errors.Is(err, apperror.NotFound) {
+			// another instance holds the position
+			return apperror.Error{}
+		}
+		if err.Exists() {
+			return err
+		}
+
+		events, err := s.outboxRepo.GetAll(txCtx, map[string]any{}, undelivered(sub.name, config.GetInt("outbox.batchSize")))
+		if err.Exists() {
+			return err
+		}
+		if len(events) == 0 {
+			return apperror.Error{}
+		}
+
+		deliveries := make([]*model.OutboxDelivery, 0, len(events))
+		last := position.EventID
+		for _, event := range events {
+			if err = sub.handle(txCtx, event); err.Exists() {
+				log.Errorf("subscriber %s failed on event %d, retrying the batch: %v", sub.name, event.ID, err)
+				return err
+			}
+
+			deliveries = append(deliveries, &model.OutboxDelivery{Subscriber: sub.name, EventID: event.ID})
+			last = max(last, event.ID)
+		}
+
+		if err = s.outboxDeliveryRepo.CreateMany(txCtx, deliveries); err.Exists() {
+			return err
+		}
+
+		return s.outboxPositionRepo.Update(txCtx, map[string]any{
+			constants.Subscriber: sub.name,
+		}, map[string]any{constants.EventID: last})
+	})
+	if err.Exists() {
+		log.Warnf("failed to relay events to %s: %v", sub.name, err)
+	}
+}
+
+// ensurePosition creates the position row of a subscriber seen for the first time, there is nothing to lock before
+func (s *Service) ensurePosition(ctx context.Context, sub *subscriber) apperror.Error {
+	if sub.ready {
+		return apperror.Error{}
+	}
+
+	ctx = postgres.WithConsistency(ctx, postgres.NewConsistency(true))
+	filter := map[string]any{constants.Subscriber: sub.name}
+
+	_, err := s.outboxPositionRepo.Get(ctx, filter)
+	if errors.Is(err, apperror.NotFound) {
+		err = s.outboxPositionRepo.Create(ctx, &model.OutboxPosition{Subscriber: sub.name})
+		// created by another instance in the meantime
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			err = apperror.Error{}
+		}
+	}
+	if err.Exists() {
+		return err
+	}
+
+	sub.ready = true
+
+	return apperror.Error{}
+}
+
+// PurgeRelayed deletes the events created before the given time that every subscriber already received
+func (s *Service) PurgeRelayed(ctx context.Context, before time.Time) apperror.Error {
+	ctx, span := tracing.Start(ctx, "OutboxService.PurgeRelayed")
+	defer span.End()
+
+	log := logger.With(ctx, "PurgeRelayed")
+
+	names := make([]string, 0)
+	for _, sub := range s.subscriptions() {
+		names = append(names, sub.name)
+	}
+	if len(names) == 0 {
+		return apperror.Error{}
+	}
+
+	purged, err := s.outboxRepo.Purge(ctx, map[string]any{}, deliveredTo(names), func(db *gorm.DB) *gorm.DB {
+		return db.Where(constants.CreatedAt+" < ?", before)
+	})
+	if err.Exists() {
+		log.Errorf("failed to purge relayed events: %v", err)
+		return apperror.NewCode(apperror.Internal, "Failed to purge relayed events")
+	}
+
+	if purged > 0 {
+		log.Infof("purged %d relayed events created before %s", purged, before)
+	}
+
+	return apperror.Error{}
+}
+
+func (s *Service) subscriptions() []*subscriber {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.subscribers)
+}
+
+// undelivered keeps the first limit events, in id order, that the subscriber has not received. Ids are taken when
+// an event is inserted but become visible when its transaction commits, so an event can show up below ids
+// already relayed, it is picked up on the run after it commits.
+func undelivered(name string, limit int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			"NOT EXISTS (SELECT 1 FROM outbox_deliveries d WHERE d.event_id = outbox_events.id AND d.subscriber = ?)",
+			name,
+		).Order(constants.ID).Limit(limit)
+	}
+}
+
+// deliveredTo keeps the events every one of the subscribers received
+func deliveredTo(names []string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			"(SELECT COUNT(*) FROM outbox_deliveries d WHERE d.event_id = outbox_events.id AND d.subscriber IN ?) = ?",
+			names, len(names),
+		)
+	}
+}
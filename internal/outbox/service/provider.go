@@ -0,0 +1,21 @@
+package service
+
+import (
+	"github.com/google/wire"
+	outboxRepo "main/internal/outbox/repository"
+	outboxDeliveryRepo "main/internal/outbox_delivery/repository"
+	outboxPositionRepo "main/internal/outbox_position/repository"
+)
+
+var ProviderSet = wire.NewSet(
+	NewService,
+	outboxRepo.NewRepository,
+	outboxPositionRepo.NewRepository,
+	outboxDeliveryRepo.NewRepository,
+
+	// bind each one of the interfaces
+	wire.Bind(new(Interface), new(*Service)),
+	wire.Bind(new(outboxRepo.Interface), new(*outboxRepo.Repository)),
+	wire.Bind(new(outboxPositionRepo.Interface), new(*outboxPositionRepo.Repository)),
+	wire.Bind(new(outboxDeliveryRepo.Interface), new(*outboxDeliveryRepo.Repository)),
+)
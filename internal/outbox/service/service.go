@@ -0,0 +1,45 @@
+package service
+
+import (
+	outboxRepo "main/internal/outbox/repository"
+	outboxDeliveryRepo "main/internal/outbox_delivery/repository"
+	outboxPositionRepo "main/internal/outbox_position/repository"
+	"sync"
+)
+
+type subscriber struct {
+	name   string
+	handle Handler
+	// whether the position row of the subscriber is known to exist
+	ready bool
+}
+
+type Service struct {
+	outboxRepo         outboxRepo.Interface
+	outboxPositionRepo outboxPositionRepo.Interface
+	outboxDeliveryRepo outboxDeliveryRepo.Interface
+
+	mu          sync.Mutex
+	subscribers []*subscriber
+}
+
+var (
+	syncOnce sync.Once
+	svc      *Service
+)
+
+func NewService(
+	outboxRepo outboxRepo.Interface,
+	outboxPositionRepo outboxPositionRepo.Interface,
+	outboxDeliveryRepo outboxDeliveryRepo.Interface,
+) *Service {
+	syncOnce.Do(func() {
+		svc = &Service{
+			outboxRepo:         outboxRepo,
+			outboxPositionRepo: outboxPositionRepo,
+			outboxDeliveryRepo: outboxDeliveryRepo,
+		}
+	})
+
+	return svc
+}
@@ -0,0 +1,25 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run -mod=mod github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package service
+
+import (
+	"context"
+	"main/internal/outbox/repository"
+	repository3 "main/internal/outbox_delivery/repository"
+	repository2 "main/internal/outbox_position/repository"
+	"main/pkg/db/postgres"
+)
+
+// Injectors from wire.go:
+
+func Wire(ctx context.Context, db *postgres.DbCluster) *Service {
+	repositoryRepository := repository.NewRepository(db)
+	repository4 := repository2.NewRepository(db)
+	repository5 := repository3.NewRepository(db)
+	service := NewService(repositoryRepository, repository4, repository5)
+	return service
+}
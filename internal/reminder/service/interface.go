@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"main/internal/controller/request"
+	"main/internal/controller/response"
+	"main/pkg/apperror"
+)
+
+type Interface interface {
+	GetReminderSetting(ctx context.Context, userID, groupID uint64) (response.ReminderSetting, apperror.Error)
+
+	UpdateReminderSetting(
+		ctx context.Context,
+		userID, groupID uint64,
+		req request.UpdateReminderSettingRequest,
+	) (response.ReminderSetting, apperror.Error)
+
+	SnoozeReminders(
+		ctx context.Context,
+		userID, groupID, splitID uint64,
+		req request.SnoozeReminderRequest,
+	) (response.PaymentReminder, apperror.Error)
+
+	NudgeDebtor(ctx context.Context, userID, groupID, splitID uint64) (response.PaymentReminder, apperror.Error)
+
+	SendDueReminders(ctx context.Context)
+}
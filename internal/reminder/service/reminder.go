@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"errors"
+	config "github.com/spf13/viper"
+	"gorm.io/gorm"
+	"main/constants"
+	"main/internal/controller/adapter"
+	"main/internal/controller/request"
+	"main/internal/controller/response"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/db/postgres"
+	"main/pkg/logger"
+	"main/pkg/metrics"
+	"main/pkg/tracing"
+	baseRepository "main/repository"
+	"time"
+)
+
+// NudgeCooldownError is returned by NudgeDebtor while the creditor has to wait before nudging the debtor again
+type NudgeCooldownError struct {
+	Until time.Time
+}
+
+func (e *NudgeCooldownError) Error() string {
+	return "Debtor was nudged recently"
+}
+
+// GetReminderSetting returns the reminder settings of a group, groups that never set them are not reminded
+func (s *Service) GetReminderSetting(ctx context.Context, userID, groupID uint64) (response.ReminderSetting, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "ReminderService.GetReminderSetting")
+	defer span.End()
+
+	log := logger.With(ctx, "GetReminderSetting")
+
+	hasPermission, err := s.groupSvc.ValidateUserGroupPermission(ctx, userID, groupID, model.View)
+	if err.Exists() || !hasPermission {
+		log.Warnf("user %d cannot view the reminder settings of group %d: %v", userID, groupID, err)
+		return response.ReminderSetting{}, err
+	}
+
+	setting, err := s.reminderSettingRepo.Get(ctx, map[string]any{constants.GroupID: groupID})
+	if errors.Is(err, apperror.NotFound) {
+		setting = model.ReminderSetting{
+			GroupID:      groupID,
+			IntervalDays: config.GetInt("reminder.defaultIntervalDays"),
+		}
+	} else if err.Exists() {
+		log.Errorf("failed to fetch reminder settings of group %d: %v", groupID, err)
+		return response.ReminderSetting{}, apperror.NewCode(apperror.Internal, "Failed to fetch reminder settings")
+	}
+
+	return adapter.BuildReminderSettingResponse(setting), apperror.Error{}
+}
+
+// UpdateReminderSetting turns the reminders of a group on or off and sets how often they repeat, it needs the same
+// permission as editing the group
+func (s *Service) UpdateReminderSetting(
+	ctx context.Context,
+	userID, groupID uint64,
+	req request.UpdateReminderSettingRequest,
+) (response.ReminderSetting, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "ReminderService.UpdateReminderSetting")
+	defer span.End()
+
+	log := logger.With(ctx, "UpdateReminderSetting")
+
+	hasPermission, err := s.groupSvc.ValidateUserGroupPermission(ctx, userID, groupID, model.Edit)
+	if err.Exists() || !hasPermission {
+		log.Warnf("user %d cannot change the reminder settings of group %d: %v", userID, groupID, err)
+		return response.ReminderSetting{}, err
+	}
+
+	setting := model.ReminderSetting{
+		GroupID:      groupID,
+		Enabled:      *req.Enabled,
+		IntervalDays: req.IntervalDays,
+		UpdatedBy:    userID,
+	}
+
+	err = s.reminderSettingRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		filter := map[string]any{constants.GroupID: groupID}
+
+		stored, err := s.reminderSettingRepo.Get(txCtx, filter, baseRepository.ForUpdate)
+		if errors.Is(err, apperror.NotFound) {
+			return s.reminderSettingRepo.Create(txCtx, &setting)
+		}
+		if err.Exists() {
+			return err
+		}
+
+		setting.CreatedAt = stored.CreatedAt
+		setting.UpdatedAt = time.Now()
+
+		return s.reminderSettingRepo.Update(txCtx, filter, map[string]any{
+			constants.Enabled:      setting.Enabled,
+			constants.IntervalDays: setting.IntervalDays,
+			constants.UpdatedBy:    setting.UpdatedBy,
+		})
+	})
+	if err.Exists() {
+		log.Errorf("failed to store reminder settings of group %d: %v", groupID, err)
+		return response.ReminderSetting{}, apperror.NewCode(apperror.Internal, "Failed to store reminder settings")
+	}
+
+	return adapter.BuildReminderSettingResponse(setting), apperror.Error{}
+}
+
+// SnoozeReminders holds back the scheduled reminders about a split for a number of days. Only the member who owes
+// can snooze, a snooze does not stop the creditor from nudging.
+func (s *Service) SnoozeReminders(
+	ctx context.Context,
+	userID, groupID, splitID uint64,
+	req request.SnoozeReminderRequest,
+) (response.PaymentReminder, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "ReminderService.SnoozeReminders")
+	defer span.End()
+
+	log := logger.With(ctx, "SnoozeReminders")
+
+	split, err := s.getOutstandingSplit(ctx, userID, groupID, splitID)
+	if err.Exists() {
+		return response.PaymentReminder{}, err
+	}
+
+	if split.UserID != userID {
+		log.Warnf("user %d does not owe split %d", userID, splitID)
+		return response.PaymentReminder{}, apperror.NewCode(apperror.PermissionDenied, "Only the member who owes can snooze reminders")
+	}
+
+	reminder, err := s.getOrCreateReminder(ctx, split)
+	if err.Exists() {
+		log.Errorf("failed to fetch reminder state of split %d: %v", splitID, err)
+		return response.PaymentReminder{}, apperror.NewCode(apperror.Internal, "Failed to snooze reminders")
+	}
+
+	until := time.Now().AddDate(0, 0, req.Days)
+	err = s.reminderRepo.Update(ctx, map[string]any{constants.ID: reminder.ID}, map[string]any{
+		constants.SnoozedUntil: until,
+	})
+	if err.Exists() {
+		log.Errorf("failed to snooze reminders of split %d: %v", splitID, err)
+		return response.PaymentReminder{}, apperror.NewCode(apperror.Internal, "Failed to snooze reminders")
+	}
+
+	reminder.SnoozedUntil = &until
+
+	return adapter.BuildPaymentReminderResponse(reminder), apperror.Error{}
+}
+
+// NudgeDebtor sends the debtor of a split a reminder right away. Only the member who is owed can nudge, and once per
+// reminder.nudgeCooldown.
+func (s *Service) NudgeDebtor(
+	ctx context.Context,
+	userID, groupID, splitID uint64,
+) (response.PaymentReminder, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "ReminderService.NudgeDebtor")
+	defer span.End()
+
+	log := logger.With(ctx, "NudgeDebtor")
+
+	split, err := s.getOutstandingSplit(ctx, userID, groupID, splitID)
+	if err.Exists() {
+		return response.PaymentReminder{}, err
+	}
+
+	if split.ToPayUserID != userID {
+		log.Warnf("user %d is not owed split %d", userID, splitID)
+		return response.PaymentReminder{}, apperror.NewCode(apperror.PermissionDenied, "Only the member who is owed can nudge")
+	}
+
+	reminder, err := s.getOrCreateReminder(ctx, split)
+	if err.Exists() {
+		log.Errorf("failed to fetch reminder state of split %d: %v", splitID, err)
+		return response.PaymentReminder{}, apperror.NewCode(apperror.Internal, "Failed to nudge")
+	}
+
+	// the row lock makes concurrent nudges of the same debt wait for each other, so the cooldown holds between them.
+	// The nudge is recorded and committed before it is sent, nothing goes out while the row is locked.
+	var now time.Time
+	var previous *time.Time
+	err = s.reminderRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		var err apperror.Error
+		reminder, err = s.reminderRepo.Get(txCtx, map[string]any{constants.ID: reminder.ID}, baseRepository.ForUpdate)
+		if err.Exists() {
+			return err
+		}
+
+		// postgres keeps microseconds, the claim is matched on this value when it is released
+		now = time.Now().Truncate(time.Microsecond)
+		if allowedAt := reminder.NudgeAllowedAt(config.GetDuration("reminder.nudgeCooldown")); now.Before(allowedAt) {
+			log.Warnf("user %d nudged the debtor of split %d less than a cooldown ago", userID, splitID)
+			return apperror.Wrap(&NudgeCooldownError{Until: allowedAt}, apperror.NudgeCooldown,
+				"Member was nudged recently, try again later")
+		}
+
+		previous = reminder.LastNudgedAt
+		reminder.LastNudgedAt = &now
+
+		return s.reminderRepo.Update(txCtx, map[string]any{constants.ID: reminder.ID}, map[string]any{
+			constants.LastNudgedAt: now,
+		})
+	})
+	if errors.Is(err, apperror.NudgeCooldown) {
+		return response.PaymentReminder{}, err
+	}
+	if err.Exists() {
+		log.Errorf("failed to nudge the debtor of split %d: %v", splitID, err)
+		return response.PaymentReminder{}, apperror.NewCode(apperror.Internal, "Failed to nudge")
+	}
+
+	if err = s.notify(ctx, nudgeKind, split); err.Exists() {
+		log.Errorf("failed to nudge the debtor of split %d: %v", splitID, err)
+		// the creditor can try again right away instead of waiting out a cooldown for a nudge that never arrived
+		if releaseErr := s.releaseClaim(ctx, reminder.ID, constants.LastNudgedAt, now, previous); releaseErr.Exists() {
+			log.Warnf("nudge of split %d stays on cooldown: %v", splitID, releaseErr)
+		}
+		return response.PaymentReminder{}, apperror.NewCode(apperror.Internal, "Failed to nudge")
+	}
+
+	metrics.PaymentRemindersSent.WithLabelValues(nudgeKind).Inc()
+
+	return adapter.BuildPaymentReminderResponse(reminder), apperror.Error{}
+}
+
+// getOutstandingSplit fetches a split of the group that is not paid yet, for a member of the group
+func (s *Service) getOutstandingSplit(ctx context.Context, userID, groupID, splitID uint64) (model.BillSplit, apperror.Error) {
+	log := logger.With(ctx, "getOutstandingSplit")
+
+	hasPermission, err := s.groupSvc.ValidateUserGroupPermission(ctx, userID, groupID, model.View)
+	if err.Exists() || !hasPermission {
+		log.Warnf("user %d cannot view the splits of group %d: %v", userID, groupID, err)
+		return model.BillSplit{}, err
+	}
+
+	split, err := s.billSplitRepo.Get(ctx, map[string]any{
+		constants.ID:      splitID,
+		constants.GroupID: groupID,
+	})
+	if errors.Is(err, apperror.NotFound) {
+		return model.BillSplit{}, apperror.NewCode(apperror.SplitNotFound, "Bill split not found")
+	}
+	if err.Exists() {
+		log.Errorf("failed to retrieve split %d of group %d: %v", splitID, groupID, err)
+		return model.BillSplit{}, apperror.NewCode(apperror.Internal, "Failed to retrieve bill split")
+	}
+
+	if split.IsPaid {
+		return model.BillSplit{}, apperror.NewCode(apperror.NothingToSettle, "This split is already paid")
+	}
+
+	return split, apperror.Error{}
+}
+
+// getOrCreateReminder returns the reminder state of the debtor and creditor of split, creating it the first time
+func (s *Service) getOrCreateReminder(ctx context.Context, split model.BillSplit) (model.PaymentReminder, apperror.Error) {
+	ctx = postgres.WithConsistency(ctx, postgres.NewConsistency(true))
+	filter := map[string]any{
+		constants.GroupID:     split.GroupID,
+		constants.UserID:      split.UserID,
+		constants.ToPayUserID: split.ToPayUserID,
+	}
+
+	reminder, err := s.reminderRepo.Get(ctx, filter)
+	if !errors.Is(err, apperror.NotFound) {
+		return reminder, err
+	}
+
+	reminder = model.PaymentReminder{GroupID: split.GroupID, UserID: split.UserID, ToPayUserID: split.ToPayUserID}
+	err = s.reminderRepo.Create(ctx, &reminder)
+	// created by a concurrent request in the meantime
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return s.reminderRepo.Get(ctx, filter)
+	}
+
+	return reminder, err
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"main/constants"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/logger"
+	"main/pkg/metrics"
+	"main/pkg/notify"
+	"main/pkg/tracing"
+	baseRepository "main/repository"
+	"slices"
+	"strings"
+	"time"
+)
+
+// kinds of the notifications sent about outstanding splits
+const (
+	reminderKind = "payment.reminder"
+	nudgeKind    = "payment.nudge"
+)
+
+// SendDueReminders reminds the debtors of every group with reminders enabled of the splits they have not paid, once
+// per interval of the group
+func (s *Service) SendDueReminders(ctx context.Context) {
+	ctx, span := tracing.Start(ctx, "ReminderService.SendDueReminders")
+	defer span.End()
+
+	log := logger.With(ctx, "SendDueReminders")
+
+	settings, err := s.reminderSettingRepo.GetAll(ctx, map[string]any{constants.Enabled: true})
+	if err.Exists() {
+		log.Errorf("failed to fetch reminder settings: %v", err)
+		return
+	}
+
+	for _, setting := range settings {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err = s.remindGroup(ctx, setting.GroupID); err.Exists() {
+			log.Warnf("failed to send the reminders of group %d: %v", setting.GroupID, err)
+		}
+	}
+}
+
+// claimedReminder is a reminder recorded as sent, with the time it replaced in case sending fails
+type claimedReminder struct {
+	reminderID uint64
+	claimedAt  time.Time
+	previous   *time.Time
+	message    notify.Message
+}
+
+// remindGroup sends the due reminders of one group. The settings row is locked while they are claimed, so one
+// instance at a time claims a group and the others pass over it. Claims are committed before anything is sent, a
+// rollback can not have debtors reminded twice and no notification is sent while the row is locked.
+func (s *Service) remindGroup(ctx context.Context, groupID uint64) apperror.Error {
+	log := logger.With(ctx, "remindGroup")
+
+	var claimed []claimedReminder
+	err := s.reminderSettingRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		setting, err := s.reminderSettingRepo.Get(txCtx, map[string]any{
+			constants.GroupID: groupID,
+			constants.Enabled: true,
+		}, baseRepository.ForUpdateSkipLocked)
+		if errors.Is(err, apperror.NotFound) {
+			// another instance is on it, or reminders were turned off in the meantime
+			return apperror.Error{}
+		}
+		if err.Exists() {
+			return err
+		}
+
+		group, err := s.groupRepo.Get(txCtx, map[string]any{constants.ID: groupID})
+		if errors.Is(err, apperror.NotFound) {
+			// the group is in the trash, its debts are not chased until it is restored
+			return apperror.Error{}
+		}
+		if err.Exists() {
+			return err
+		}
+
+		splits, err := s.billSplitRepo.GetAll(txCtx, map[string]any{
+			constants.GroupID: groupID,
+			constants.IsPaid:  false,
+		})
+		if err.Exists() || len(splits) == 0 {
+			return err
+		}
+
+		reminders, err := s.reminderRepo.GetAll(txCtx, map[string]any{constants.GroupID: groupID})
+		if err.Exists() {
+			return err
+		}
+		byPair := model.PaymentReminders(reminders).MapByPair()
+
+		userIDs := make([]uint64, 0, 2*len(splits))
+		for _, split := range splits {
+			userIDs = append(userIDs, split.UserID, split.ToPayUserID)
+		}
+
+		users, err := s.userSvc.FetchFilteredUsers(txCtx, map[string]any{constants.ID: userIDs})
+		if err.Exists() {
+			return err
+		}
+		byID := users.MapByID()
+
+		// a debtor owing the same creditor in several currencies gets one reminder about all of them
+		pairs := make([][2]uint64, 0, len(splits))
+		splitsByPair := make(map[[2]uint64][]model.BillSplit)
+		for _, split := range splits {
+			pair := [2]uint64{split.UserID, split.ToPayUserID}
+			if _, ok := splitsByPair[pair]; !ok {
+				pairs = append(pairs, pair)
+			}
+			splitsByPair[pair] = append(splitsByPair[pair], split)
+		}
+
+		// postgres keeps microseconds, the claim is matched on this value when it is released
+		now := time.Now().Truncate(time.Microsecond)
+		for _, pair := range pairs {
+			reminder := byPair[pair]
+			due := slices.ContainsFunc(splitsByPair[pair], func(split model.BillSplit) bool {
+				return reminder.Due(split, setting.Interval(), now)
+			})
+			if !due {
+				continue
+			}
+
+			previous := reminder.LastRemindedAt
+			if reminder.ID == 0 {
+				reminder = model.PaymentReminder{
+					GroupID:        groupID,
+					UserID:         pair[0],
+					ToPayUserID:    pair[1],
+					LastRemindedAt: &now,
+				}
+				err = s.reminderRepo.Create(txCtx, &reminder)
+			} else {
+				err = s.reminderRepo.Update(txCtx, map[string]any{constants.ID: reminder.ID}, map[string]any{
+					constants.LastRemindedAt: now,
+				})
+			}
+			if err.Exists() {
+				return err
+			}
+
+			claimed = append(claimed, claimedReminder{
+				reminderID: reminder.ID,
+				claimedAt:  now,
+				previous:   previous,
+				message:    newMessage(reminderKind, group, splitsByPair[pair], byID),
+			})
+		}
+
+		return apperror.Error{}
+	})
+	if err.Exists() {
+		return err
+	}
+
+	for _, claim := range claimed {
+		if sendErr := notify.Default().Notify(ctx, claim.message); sendErr != nil {
+			log.Warnf("failed to remind user %d in group %d: %v", claim.message.UserID, groupID, sendErr)
+			// the reminder is due again and sent on the next run
+			if err = s.releaseClaim(ctx, claim.reminderID, constants.LastRemindedAt, claim.claimedAt, claim.previous); err.Exists() {
+				log.Warnf("reminder %d is not retried before the next interval: %v", claim.reminderID, err)
+			}
+			continue
+		}
+		metrics.PaymentRemindersSent.WithLabelValues(reminderKind).Inc()
+	}
+
+	return apperror.Error{}
+}
+
+// releaseClaim puts back the time a reminder was claimed at when the notification could not be sent, unless a later
+// claim replaced it in the meantime
+func (s *Service) releaseClaim(ctx context.Context, reminderID uint64, column string, claimedAt time.Time, previous *time.Time) apperror.Error {
+	return s.reminderRepo.Update(ctx, map[string]any{
+		constants.ID: reminderID,
+		column:       claimedAt,
+	}, map[string]any{
+		column: previous,
+	})
+}
+
+// notify fetches the group and members of split and tells the debtor what they owe
+func (s *Service) notify(ctx context.Context, kind string, split model.BillSplit) apperror.Error {
+	group, err := s.groupRepo.Get(ctx, map[string]any{constants.ID: split.GroupID})
+	if err.Exists() {
+		return err
+	}
+
+	users, err := s.userSvc.FetchFilteredUsers(ctx, map[string]any{
+		constants.ID: []uint64{split.UserID, split.ToPayUserID},
+	})
+	if err.Exists() {
+		return err
+	}
+
+	if sendErr := notify.Default().Notify(ctx, newMessage(kind, group, []model.BillSplit{split}, users.MapByID())); sendErr != nil {
+		return apperror.Wrap(sendErr, apperror.Internal, "Failed to send notification")
+	}
+
+	return apperror.Error{}
+}
+
+// newMessage words a scheduled reminder or a nudge from the creditor to the debtor of splits, which are the debts
+// of one debtor to one creditor
+func newMessage(kind string, group model.Group, splits []model.BillSplit, users map[uint64]model.User) notify.Message {
+	debtor, creditor := users[splits[0].UserID], users[splits[0].ToPayUserID]
+
+	amounts := make([]string, 0, len(splits))
+	since := splits[0].CreatedAt
+	for _, split := range splits {
+		amounts = append(amounts, strings.TrimSpace(fmt.Sprintf("%.2f %s", split.AmountDue, split.Currency)))
+		if split.CreatedAt.Before(since) {
+			since = split.CreatedAt
+		}
+	}
+	owed := strings.Join(amounts, " and ")
+
+	msg := notify.Message{
+		Kind:    kind,
+		UserID:  splits[0].UserID,
+		Email:   debtor.Email,
+		Subject: fmt.Sprintf("You owe %s %s in %s", creditor.Name, owed, group.Name),
+		Body: fmt.Sprintf("You still owe %s %s in %s since %s. Record a settlement in the group once you paid.",
+			creditor.Name, owed, group.Name, since.Format(time.DateOnly)),
+	}
+	if kind == nudgeKind {
+		msg.Subject = fmt.Sprintf("%s asks you to settle up in %s", creditor.Name, group.Name)
+	}
+
+	return msg
+}
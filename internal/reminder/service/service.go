@@ -0,0 +1,47 @@
+package service
+
+import (
+	billSplitRepo "main/internal/bill_split/repository"
+	groupRepo "main/internal/group/repository"
+	groupSvc "main/internal/group/service"
+	reminderRepo "main/internal/reminder/repository"
+	reminderSettingRepo "main/internal/reminder_setting/repository"
+	userSvc "main/internal/user/service"
+	"sync"
+)
+
+type Service struct {
+	reminderRepo        reminderRepo.Interface
+	reminderSettingRepo reminderSettingRepo.Interface
+	billSplitRepo       billSplitRepo.Interface
+	groupRepo           groupRepo.Interface
+	groupSvc            groupSvc.Interface
+	userSvc             userSvc.Interface
+}
+
+var (
+	syncOnce sync.Once
+	svc      *Service
+)
+
+func NewService(
+	reminderRepo reminderRepo.Interface,
+	reminderSettingRepo reminderSettingRepo.Interface,
+	billSplitRepo billSplitRepo.Interface,
+	groupRepo groupRepo.Interface,
+	groupSvc groupSvc.Interface,
+	userSvc userSvc.Interface,
+) *Service {
+	syncOnce.Do(func() {
+		svc = &Service{
+			reminderRepo:        reminderRepo,
+			reminderSettingRepo: reminderSettingRepo,
+			billSplitRepo:       billSplitRepo,
+			groupRepo:           groupRepo,
+			groupSvc:            groupSvc,
+			userSvc:             userSvc,
+		}
+	})
+
+	return svc
+}
@@ -0,0 +1,69 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run -mod=mod github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package service
+
+import (
+	"context"
+	repository11 "main/internal/activity/repository"
+	service6 "main/internal/activity/service"
+	repository9 "main/internal/auth/repository"
+	service3 "main/internal/auth/service"
+	repository6 "main/internal/bill/repository"
+	service2 "main/internal/bill/service"
+	repository7 "main/internal/bill_share/repository"
+	repository3 "main/internal/bill_split/repository"
+	repository4 "main/internal/group/repository"
+	service9 "main/internal/group/service"
+	repository5 "main/internal/group_permission/repository"
+	"main/internal/group_permission/service"
+	repository10 "main/internal/otp/repository"
+	service4 "main/internal/otp/service"
+	repository14 "main/internal/outbox/repository"
+	service8 "main/internal/outbox/service"
+	repository16 "main/internal/outbox_delivery/repository"
+	repository15 "main/internal/outbox_position/repository"
+	"main/internal/reminder/repository"
+	repository2 "main/internal/reminder_setting/repository"
+	repository8 "main/internal/user/repository"
+	service5 "main/internal/user/service"
+	repository12 "main/internal/webhook/repository"
+	service7 "main/internal/webhook/service"
+	repository13 "main/internal/webhook_delivery/repository"
+	"main/pkg/db/postgres"
+)
+
+// Injectors from wire.go:
+
+func Wire(ctx context.Context, db *postgres.DbCluster) *Service {
+	repositoryRepository := repository.NewRepository(db)
+	repository17 := repository2.NewRepository(db)
+	repository18 := repository3.NewRepository(db)
+	repository19 := repository4.NewRepository(db)
+	repository20 := repository5.NewRepository(db)
+	serviceService := service.NewService(repository20)
+	repository21 := repository6.NewRepository(db)
+	repository22 := repository7.NewRepository(db)
+	service10 := service2.NewService(repository21, repository22)
+	repository23 := repository8.NewRepository(db)
+	repository24 := repository9.NewRepository(db)
+	service11 := service3.NewService(repository24)
+	repository25 := repository10.NewRepository(db)
+	service12 := service4.NewService(repository25)
+	service13 := service5.NewService(repository23, service11, service12)
+	repository26 := repository11.NewRepository(db)
+	service14 := service6.NewService(repository26)
+	repository27 := repository12.NewRepository(db)
+	repository28 := repository13.NewRepository(db)
+	service15 := service7.NewService(repository27, repository28)
+	repository29 := repository14.NewRepository(db)
+	repository30 := repository15.NewRepository(db)
+	repository31 := repository16.NewRepository(db)
+	service16 := service8.NewService(repository29, repository30, repository31)
+	service17 := service9.NewService(repository19, serviceService, service10, service13, service14, service15, service16)
+	service18 := NewService(repositoryRepository, repository17, repository18, repository19, service17, service13)
+	return service18
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"main/internal/controller/request"
+	"main/internal/controller/response"
+	"main/pkg/apperror"
+	"main/repository/query"
+)
+
+type Interface interface {
+	RecordSettlement(
+		ctx context.Context,
+		userID, groupID uint64,
+		req request.RecordSettlementRequest,
+	) (response.Settlement, apperror.Error)
+
+	GetGroupSettlements(
+		ctx context.Context,
+		userID, groupID uint64,
+		q *query.Query,
+	) ([]response.Settlement, query.Page, apperror.Error)
+}
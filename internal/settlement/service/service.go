@@ -0,0 +1,43 @@
+package service
+
+import (
+	billSplitRepo "main/internal/bill_split/repository"
+	groupSvc "main/internal/group/service"
+	outboxSvc "main/internal/outbox/service"
+	settlementRepo "main/internal/settlement/repository"
+	userSvc "main/internal/user/service"
+	"sync"
+)
+
+type Service struct {
+	settlementRepo settlementRepo.Interface
+	billSplitRepo  billSplitRepo.Interface
+	groupSvc       groupSvc.Interface
+	outboxSvc      outboxSvc.Interface
+	userSvc        userSvc.Interface
+}
+
+var (
+	syncOnce sync.Once
+	svc      *Service
+)
+
+func NewService(
+	settlementRepo settlementRepo.Interface,
+	billSplitRepo billSplitRepo.Interface,
+	groupSvc groupSvc.Interface,
+	outboxSvc outboxSvc.Interface,
+	userSvc userSvc.Interface,
+) *Service {
+	syncOnce.Do(func() {
+		svc = &Service{
+			settlementRepo: settlementRepo,
+			billSplitRepo:  billSplitRepo,
+			groupSvc:       groupSvc,
+			outboxSvc:      outboxSvc,
+			userSvc:        userSvc,
+		}
+	})
+
+	return svc
+}
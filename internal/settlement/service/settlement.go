@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"main/constants"
+	"main/internal/controller/adapter"
+	"main/internal/controller/request"
+	"main/internal/controller/response"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/logger"
+	"main/pkg/metrics"
+	"main/pkg/tracing"
+	baseRepository "main/repository"
+	"main/repository/query"
+	"slices"
+	"strings"
+)
+
+// settledTolerance absorbs float rounding, a split with less than half a cent left is paid
+const settledTolerance = 0.005
+
+// RecordSettlement stores a payment from the user to one of their creditors in the group and pays down the split
+// between them in the settlement's currency. A settlement can not exceed what is still due.
+func (s *Service) RecordSettlement(
+	ctx context.Context,
+	userID, groupID uint64,
+	req request.RecordSettlementRequest,
+) (response.Settlement, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "SettlementService.RecordSettlement")
+	defer span.End()
+
+	log := logger.With(ctx, "RecordSettlement")
+
+	hasPermission, err := s.groupSvc.ValidateUserGroupPermission(ctx, userID, groupID, model.View)
+	if err.Exists() || !hasPermission {
+		log.Warnf("user %d cannot settle in group %d: %v", userID, groupID, err)
+		return response.Settlement{}, err
+	}
+
+	settlement := model.Settlement{
+		GroupID:    groupID,
+		FromUserID: userID,
+		ToUserID:   req.ToUserID,
+		Amount:     req.Amount,
+		Currency:   req.Currency,
+		Note:       req.Note,
+	}
+
+	err = s.settlementRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		// the split stays locked until the settlement is stored, concurrent settlements of it queue up behind this one
+		splits, fetchErr := s.billSplitRepo.GetAll(txCtx, map[string]any{
+			constants.GroupID:     groupID,
+			constants.UserID:      userID,
+			constants.ToPayUserID: req.ToUserID,
+			constants.IsPaid:      false,
+		}, baseRepository.ForUpdate)
+		if fetchErr.Exists() {
+			log.Errorf("failed to fetch splits of user %d in group %d: %v", userID, groupID, fetchErr)
+			return apperror.NewCode(apperror.Internal, "Failed to fetch bill splits")
+		}
+
+		if len(splits) == 0 {
+			log.Warnf("user %d owes nothing to user %d in group %d", userID, req.ToUserID, groupID)
+			return apperror.NewCode(apperror.NothingToSettle, "You do not owe this member anything")
+		}
+
+		// a debt is only paid down in the currency it was run up in
+		index := slices.IndexFunc(splits, func(split model.BillSplit) bool {
+			return split.Currency == req.Currency
+		})
+		if index < 0 {
+			currencies := make([]string, 0, len(splits))
+			for _, split := range splits {
+				// splits calculated before they carried a currency
+				if split.Currency != "" {
+					currencies = append(currencies, split.Currency)
+				}
+			}
+			log.Warnf("user %d settled in %s but owes user %d in %v", userID, req.Currency, req.ToUserID, currencies)
+
+			message := "must be the currency of the debt: " + strings.Join(currencies, ", ")
+			if len(currencies) == 0 {
+				message = "cannot be matched to the debt, recalculate the splits of the group first"
+			}
+
+			return apperror.NewValidation(apperror.FieldError{
+				Field:   constants.Currency,
+				Rule:    "debt_currency",
+				Message: message,
+			})
+		}
+
+		split := splits[index]
+		if req.Amount > split.AmountDue+settledTolerance {
+			log.Warnf("settlement of %.2f exceeds the %.2f user %d owes", req.Amount, split.AmountDue, userID)
+
+			return apperror.NewCode(apperror.InvalidAmount, "Amount exceeds what is due").
+				With("amount_due", split.AmountDue)
+		}
+
+		remaining := split.AmountDue - req.Amount
+		if createErr := s.settlementRepo.Create(txCtx, &settlement); createErr.Exists() {
+			log.Errorf("failed to store settlement in group %d: %v", groupID, createErr)
+			return apperror.NewCode(apperror.Internal, "Failed to record settlement")
+		}
+
+		updateErr := s.billSplitRepo.Update(txCtx, map[string]any{constants.ID: split.ID}, map[string]any{
+			constants.AmountDue: gorm.Expr("GREATEST(amount_due - ?, 0)", req.Amount),
+			constants.IsPaid:    remaining < settledTolerance,
+		})
+		if updateErr.Exists() {
+			log.Errorf("failed to pay down split %d: %v", split.ID, updateErr)
+			return apperror.NewCode(apperror.Internal, "Failed to update bill split")
+		}
+
+		return s.outboxSvc.Publish(txCtx, model.OutboxEvent{
+			GroupID: groupID,
+			ActorID: userID,
+			Type:    model.SettlementRecorded,
+			Data: model.ActivityData{
+				SettlementID: settlement.ID,
+				UserID:       settlement.ToUserID,
+				Amount:       settlement.Amount,
+				Currency:     settlement.Currency,
+			},
+		})
+	})
+	if err.Exists() {
+		return response.Settlement{}, err
+	}
+
+	metrics.SettlementsRecorded.Inc()
+
+	users, err := s.userSvc.FetchFilteredUsers(ctx, map[string]any{
+		constants.ID: []uint64{settlement.FromUserID, settlement.ToUserID},
+	})
+	if err.Exists() {
+		log.Errorf("failed to fetch members of settlement %d: %v", settlement.ID, err)
+		return response.Settlement{}, apperror.NewCode(apperror.Internal, "Failed to fetch users")
+	}
+
+	return adapter.BuildSettlementResponse(settlement, users.MapByID()), apperror.Error{}
+}
+
+func (s *Service) GetGroupSettlements(
+	ctx context.Context,
+	userID, groupID uint64,
+	q *query.Query,
+) ([]response.Settlement, query.Page, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "SettlementService.GetGroupSettlements")
+	defer span.End()
+
+	log := logger.With(ctx, "GetGroupSettlements")
+
+	hasPermission, err := s.groupSvc.ValidateUserGroupPermission(ctx, userID, groupID, model.View)
+	if err.Exists() || !hasPermission {
+		log.Warnf("user %d cannot view settlements of group %d: %v", userID, groupID, err)
+		return nil, query.Page{}, err
+	}
+
+	settlements, page, err := s.settlementRepo.GetAllWithPagination(ctx, map[string]any{
+		constants.GroupID: groupID,
+	}, q)
+	if err.Exists() {
+		log.Errorf("failed to fetch settlements of group %d: %v", groupID, err)
+		return nil, page, apperror.NewCode(apperror.Internal, "Failed to fetch settlements")
+	}
+
+	users, err := s.userSvc.FetchFilteredUsers(ctx, map[string]any{
+		constants.ID: model.Settlements(settlements).ExtractUniqueUserIDs(),
+	})
+	if err.Exists() {
+		log.Errorf("failed to fetch members of settlements in group %d: %v", groupID, err)
+		return nil, page, apperror.NewCode(apperror.Internal, "Failed to fetch users")
+	}
+
+	return adapter.BuildSettlementsResponse(settlements, users), page, apperror.Error{}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"cmp"
+	"main/internal/model"
+	"slices"
+	"time"
+)
+
+// buildStatement sums up a month of a group. bills and settlements are everything up to the end of the month,
+// what came before from only counts towards the opening balances. Each currency is summed up on its own.
+func buildStatement(
+	group model.Group,
+	from, to time.Time,
+	bills model.Bills,
+	settlements model.Settlements,
+	users model.Users,
+) model.StatementData {
+	billsByCurrency := make(map[string]model.Bills)
+	for _, bill := range bills {
+		billsByCurrency[bill.Currency] = append(billsByCurrency[bill.Currency], bill)
+	}
+
+	settlementsByCurrency := make(map[string]model.Settlements)
+	for _, settlement := range settlements {
+		settlementsByCurrency[settlement.Currency] = append(settlementsByCurrency[settlement.Currency], settlement)
+	}
+
+	data := model.StatementData{GroupName: group.Name, From: from, To: to}
+
+	currencies := make([]string, 0, len(billsByCurrency)+len(settlementsByCurrency))
+	for currency := range billsByCurrency {
+		currencies = append(currencies, currency)
+	}
+	for currency := range settlementsByCurrency {
+		currencies = append(currencies, currency)
+	}
+	slices.Sort(currencies)
+
+	names := users.MapByID()
+	data.Currencies = make([]model.StatementCurrency, 0, len(currencies))
+	for _, currency := range slices.Compact(currencies) {
+		data.Currencies = append(data.Currencies,
+			buildCurrency(currency, from, billsByCurrency[currency], settlementsByCurrency[currency], names))
+	}
+
+	data.Settlements = make([]model.StatementSettlement, 0)
+	for _, settlement := range settlements {
+		if settlement.CreatedAt.Before(from) {
+			continue
+		}
+
+		data.Settlements = append(data.Settlements, model.StatementSettlement{
+			ID:         settlement.ID,
+			FromUserID: settlement.FromUserID,
+			ToUserID:   settlement.ToUserID,
+			Amount:     settlement.Amount,
+			Currency:   settlement.Currency,
+			Note:       settlement.Note,
+			CreatedAt:  settlement.CreatedAt,
+		})
+	}
+
+	return data
+}
+
+// buildCurrency sums up the bills and settlements of one currency
+func buildCurrency(
+	currency string,
+	from time.Time,
+	bills model.Bills,
+	settlements model.Settlements,
+	names map[uint64]model.User,
+) model.StatementCurrency {
+	var before model.Bills
+	var beforeSettlements model.Settlements
+	members := make(map[uint64]*model.StatementMember)
+	member := func(userID uint64) *model.StatementMember {
+		if _, ok := members[userID]; !ok {
+			members[userID] = &model.StatementMember{UserID: userID}
+		}
+
+		return members[userID]
+	}
+
+	result := model.StatementCurrency{Currency: currency}
+	categories := make(map[string]*model.StatementCategory)
+	memberCategories := make(map[uint64]map[string]*model.StatementCategory)
+
+	for _, bill := range bills {
+		if bill.CreatedAt.Before(from) {
+			before = append(before, bill)
+			continue
+		}
+
+		result.TotalSpent += bill.PaidAmount
+		line := member(bill.UserID)
+		line.Paid += bill.PaidAmount
+		line.Bills++
+
+		addToCategory(categories, bill)
+		if memberCategories[bill.UserID] == nil {
+			memberCategories[bill.UserID] = make(map[string]*model.StatementCategory)
+		}
+		addToCategory(memberCategories[bill.UserID], bill)
+	}
+
+	for _, settlement := range settlements {
+		if settlement.CreatedAt.Before(from) {
+			beforeSettlements = append(beforeSettlements, settlement)
+			continue
+		}
+
+		member(settlement.FromUserID).SettledOut += settlement.Amount
+		member(settlement.ToUserID).SettledIn += settlement.Amount
+	}
+
+	for userID, balance := range model.GroupBalances(before, beforeSettlements) {
+		member(userID).OpeningBalance = balance
+	}
+	for userID, balance := range model.GroupBalances(bills, settlements) {
+		member(userID).ClosingBalance = balance
+	}
+
+	result.Members = make([]model.StatementMember, 0, len(members))
+	for userID, line := range members {
+		line.Name = names[userID].Name
+		line.Categories = sortedCategories(memberCategories[userID])
+		result.Members = append(result.Members, *line)
+	}
+	slices.SortFunc(result.Members, func(a, b model.StatementMember) int {
+		return cmp.Or(cmp.Compare(b.Paid, a.Paid), cmp.Compare(a.UserID, b.UserID))
+	})
+
+	result.Categories = sortedCategories(categories)
+
+	return result
+}
+
+func addToCategory(categories map[string]*model.StatementCategory, bill model.Bill) {
+	if _, ok := categories[bill.Category]; !ok {
+		categories[bill.Category] = &model.StatementCategory{Category: bill.Category}
+	}
+
+	categories[bill.Category].Total += bill.PaidAmount
+	categories[bill.Category].Bills++
+}
+
+// sortedCategories lists categories by total, the largest first
+func sortedCategories(categories map[string]*model.StatementCategory) []model.StatementCategory {
+	result := make([]model.StatementCategory, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, *category)
+	}
+	slices.SortFunc(result, func(a, b model.StatementCategory) int {
+		return cmp.Or(cmp.Compare(b.Total, a.Total), cmp.Compare(a.Category, b.Category))
+	})
+
+	return result
+}
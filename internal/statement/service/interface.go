@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+	"main/internal/controller/response"
+	"main/pkg/apperror"
+	"time"
+)
+
+type Interface interface {
+	GetGroupStatement(ctx context.Context, userID, groupID uint64, month time.Time) (response.GroupStatement, apperror.Error)
+	GetUserStatement(ctx context.Context, userID uint64, month time.Time) (response.UserStatement, apperror.Error)
+	GenerateStatements(ctx context.Context)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"github.com/google/wire"
+	activityRepo "main/internal/activity/repository"
+	activitySvc "main/internal/activity/service"
+	authRepo "main/internal/auth/repository"
+	authSvc "main/internal/auth/service"
+	billRepo "main/internal/bill/repository"
+	billSvc "main/internal/bill/service"
+	billShareRepo "main/internal/bill_share/repository"
+	billSplitRepo "main/internal/bill_split/repository"
+	groupRepo "main/internal/group/repository"
+	groupSvc "main/internal/group/service"
+	groupPermissionRepo "main/internal/group_permission/repository"
+	groupPermissionSvc "main/internal/group_permission/service"
+	otpRepo "main/internal/otp/repository"
+	otpSvc "main/internal/otp/service"
+	outboxRepo "main/internal/outbox/repository"
+	outboxSvc "main/internal/outbox/service"
+	outboxDeliveryRepo "main/internal/outbox_delivery/repository"
+	outboxPositionRepo "main/internal/outbox_position/repository"
+	settlementRepo "main/internal/settlement/repository"
+	statementRepo "main/internal/statement/repository"
+	userRepo "main/internal/user/repository"
+	userSvc "main/internal/user/service"
+	webhookRepo "main/internal/webhook/repository"
+	webhookSvc "main/internal/webhook/service"
+	webhookDeliveryRepo "main/internal/webhook_delivery/repository"
+)
+
+var ProviderSet = wire.NewSet(
+	NewService,
+	statementRepo.NewRepository,
+	settlementRepo.NewRepository,
+	billSplitRepo.NewRepository,
+	billSvc.NewService,
+	billRepo.NewRepository,
+	billShareRepo.NewRepository,
+	groupRepo.NewRepository,
+	groupSvc.NewService,
+	groupPermissionRepo.NewRepository,
+	groupPermissionSvc.NewService,
+	userRepo.NewRepository,
+	userSvc.NewService,
+	authRepo.NewRepository,
+	authSvc.NewService,
+	otpRepo.NewRepository,
+	otpSvc.NewService,
+	activitySvc.NewService,
+	activityRepo.NewRepository,
+	webhookSvc.NewService,
+	webhookRepo.NewRepository,
+	webhookDeliveryRepo.NewRepository,
+	outboxSvc.NewService,
+	outboxRepo.NewRepository,
+	outboxPositionRepo.NewRepository,
+	outboxDeliveryRepo.NewRepository,
+
+	// bind each one of the interfaces
+	wire.Bind(new(Interface), new(*Service)),
+	wire.Bind(new(statementRepo.Interface), new(*statementRepo.Repository)),
+	wire.Bind(new(settlementRepo.Interface), new(*settlementRepo.Repository)),
+	wire.Bind(new(billSplitRepo.Interface), new(*billSplitRepo.Repository)),
+	wire.Bind(new(billSvc.Interface), new(*billSvc.Service)),
+	wire.Bind(new(billRepo.Interface), new(*billRepo.Repository)),
+	wire.Bind(new(billShareRepo.Interface), new(*billShareRepo.Repository)),
+	wire.Bind(new(groupRepo.Interface), new(*groupRepo.Repository)),
+	wire.Bind(new(groupSvc.Interface), new(*groupSvc.Service)),
+	wire.Bind(new(groupPermissionRepo.Interface), new(*groupPermissionRepo.Repository)),
+	wire.Bind(new(groupPermissionSvc.Interface), new(*groupPermissionSvc.Service)),
+	wire.Bind(new(userRepo.Interface), new(*userRepo.Repository)),
+	wire.Bind(new(userSvc.Interface), new(*userSvc.Service)),
+	wire.Bind(new(authRepo.Interface), new(*authRepo.Repository)),
+	wire.Bind(new(authSvc.Interface), new(*authSvc.Service)),
+	wire.Bind(new(otpSvc.Interface), new(*otpSvc.Service)),
+	wire.Bind(new(otpRepo.Interface), new(*otpRepo.Repository)),
+	wire.Bind(new(activitySvc.Interface), new(*activitySvc.Service)),
+	wire.Bind(new(activityRepo.Interface), new(*activityRepo.Repository)),
+	wire.Bind(new(webhookSvc.Interface), new(*webhookSvc.Service)),
+	wire.Bind(new(webhookRepo.Interface), new(*webhookRepo.Repository)),
+	wire.Bind(new(webhookDeliveryRepo.Interface), new(*webhookDeliveryRepo.Repository)),
+	wire.Bind(new(outboxSvc.Interface), new(*outboxSvc.Service)),
+	wire.Bind(new(outboxRepo.Interface), new(*outboxRepo.Repository)),
+	wire.Bind(new(outboxPositionRepo.Interface), new(*outboxPositionRepo.Repository)),
+	wire.Bind(new(outboxDeliveryRepo.Interface), new(*outboxDeliveryRepo.Repository)),
+)
@@ -0,0 +1,55 @@
+package service
+
+import (
+	billRepo "main/internal/bill/repository"
+	billShareRepo "main/internal/bill_share/repository"
+	groupRepo "main/internal/group/repository"
+	groupSvc "main/internal/group/service"
+	groupPermissionSvc "main/internal/group_permission/service"
+	settlementRepo "main/internal/settlement/repository"
+	statementRepo "main/internal/statement/repository"
+	userSvc "main/internal/user/service"
+	"sync"
+)
+
+type Service struct {
+	statementRepo      statementRepo.Interface
+	groupRepo          groupRepo.Interface
+	billRepo           billRepo.Interface
+	billShareRepo      billShareRepo.Interface
+	settlementRepo     settlementRepo.Interface
+	groupSvc           groupSvc.Interface
+	groupPermissionSvc groupPermissionSvc.Interface
+	userSvc            userSvc.Interface
+}
+
+var (
+	syncOnce sync.Once
+	svc      *Service
+)
+
+func NewService(
+	statementRepo statementRepo.Interface,
+	groupRepo groupRepo.Interface,
+	billRepo billRepo.Interface,
+	billShareRepo billShareRepo.Interface,
+	settlementRepo settlementRepo.Interface,
+	groupSvc groupSvc.Interface,
+	groupPermissionSvc groupPermissionSvc.Interface,
+	userSvc userSvc.Interface,
+) *Service {
+	syncOnce.Do(func() {
+		svc = &Service{
+			statementRepo:      statementRepo,
+			groupRepo:          groupRepo,
+			billRepo:           billRepo,
+			billShareRepo:      billShareRepo,
+			settlementRepo:     settlementRepo,
+			groupSvc:           groupSvc,
+			groupPermissionSvc: groupPermissionSvc,
+			userSvc:            userSvc,
+		}
+	})
+
+	return svc
+}
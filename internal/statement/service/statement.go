@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"main/constants"
+	"main/internal/controller/adapter"
+	"main/internal/controller/response"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/db/postgres"
+	"main/pkg/logger"
+	"main/pkg/tracing"
+	"slices"
+	"time"
+)
+
+// GetGroupStatement returns the statement of a group for a month that is over, generating it on the first request
+// when the monthly run has not got to it yet
+func (s *Service) GetGroupStatement(
+	ctx context.Context,
+	userID, groupID uint64,
+	month time.Time,
+) (response.GroupStatement, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "StatementService.GetGroupStatement")
+	defer span.End()
+
+	log := logger.With(ctx, "GetGroupStatement")
+
+	hasPermission, err := s.groupSvc.ValidateUserGroupPermission(ctx, userID, groupID, model.View)
+	if err.Exists() || !hasPermission {
+		log.Warnf("user %d cannot view the statements of group %d: %v", userID, groupID, err)
+		return response.GroupStatement{}, err
+	}
+
+	from, to := model.StatementPeriod(month)
+	if err = checkMonthOver(to); err.Exists() {
+		return response.GroupStatement{}, err
+	}
+
+	group, err := s.groupRepo.Get(ctx, map[string]any{constants.ID: groupID})
+	if errors.Is(err, apperror.NotFound) {
+		return response.GroupStatement{}, apperror.NewCode(apperror.GroupNotFound, "Group not found")
+	}
+	if err.Exists() {
+		log.Errorf("failed to retrieve group %d: %v", groupID, err)
+		return response.GroupStatement{}, apperror.NewCode(apperror.Internal, "Failed to retrieve group")
+	}
+
+	if !group.CreatedAt.Before(to) {
+		return response.GroupStatement{}, apperror.NewCode(apperror.StatementNotFound, "Group did not exist in this month")
+	}
+
+	statement, err := s.groupStatement(ctx, group, from)
+	if err.Exists() {
+		log.Errorf("failed to get the %s statement of group %d: %v", from.Format(model.StatementMonthLayout), groupID, err)
+		return response.GroupStatement{}, apperror.NewCode(apperror.Internal, "Failed to get statement")
+	}
+
+	return adapter.BuildGroupStatementResponse(statement), apperror.Error{}
+}
+
+// GetUserStatement adds up the statements of the groups the user is a member of for a month that is over
+func (s *Service) GetUserStatement(ctx context.Context, userID uint64, month time.Time) (response.UserStatement, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "StatementService.GetUserStatement")
+	defer span.End()
+
+	log := logger.With(ctx, "GetUserStatement")
+
+	from, to := model.StatementPeriod(month)
+	if err := checkMonthOver(to); err.Exists() {
+		return response.UserStatement{}, err
+	}
+
+	users, err := s.userSvc.FetchFilteredUsers(ctx, map[string]any{constants.ID: userID})
+	if err.Exists() || len(users) == 0 {
+		log.Errorf("failed to retrieve user %d: %v", userID, err)
+		return response.UserStatement{}, apperror.NewCode(apperror.UserNotFound, "User not found")
+	}
+
+	memberships, err := s.groupPermissionSvc.FetchUserGroup(ctx, userID)
+	if err.Exists() {
+		log.Errorf("failed to fetch the groups of user %d: %v", userID, err)
+		return response.UserStatement{}, apperror.NewCode(apperror.Internal, "Failed to fetch user groups")
+	}
+
+	statements := make(model.Statements, 0)
+	if groupIDs := memberships.GetUniqueGroupIDs(); len(groupIDs) > 0 {
+		groups, err := s.groupRepo.GetAll(ctx, map[string]any{constants.ID: groupIDs}, createdBefore(to))
+		if err.Exists() {
+			log.Errorf("failed to fetch the groups of user %d: %v", userID, err)
+			return response.UserStatement{}, apperror.NewCode(apperror.Internal, "Failed to fetch user groups")
+		}
+
+		for _, group := range groups {
+			statement, err := s.groupStatement(ctx, group, from)
+			if err.Exists() {
+				log.Errorf("failed to get the %s statement of group %d: %v", from.Format(model.StatementMonthLayout), group.ID, err)
+				return response.UserStatement{}, apperror.NewCode(apperror.Internal, "Failed to get statement")
+			}
+			statements = append(statements, statement)
+		}
+	}
+
+	return adapter.BuildUserStatementResponse(users[0], from, statements), apperror.Error{}
+}
+
+// GenerateStatements generates the statements of the month that just ended for every group that existed in it. It
+// runs from the monthly-statements worker, a run that finds them all generated does nothing.
+func (s *Service) GenerateStatements(ctx context.Context) {
+	ctx, span := tracing.Start(ctx, "StatementService.GenerateStatements")
+	defer span.End()
+
+	log := logger.With(ctx, "GenerateStatements")
+
+	current, _ := model.StatementPeriod(time.Now().UTC())
+	from, to := model.StatementPeriod(current.AddDate(0, -1, 0))
+	month := from.Format(model.StatementMonthLayout)
+
+	groups, err := s.groupRepo.GetAll(ctx, map[string]any{}, createdBefore(to))
+	if err.Exists() {
+		log.Errorf("failed to fetch groups: %v", err)
+		return
+	}
+
+	generated, err := s.statementRepo.GetAll(ctx, map[string]any{constants.Month: month})
+	if err.Exists() {
+		log.Errorf("failed to fetch the %s statements: %v", month, err)
+		return
+	}
+
+	done := make(map[uint64]struct{}, len(generated))
+	for _, statement := range generated {
+		done[statement.GroupID] = struct{}{}
+	}
+
+	for _, group := range groups {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, ok := done[group.ID]; ok {
+			continue
+		}
+
+		if _, err = s.groupStatement(ctx, group, from); err.Exists() {
+			log.Warnf("failed to generate the %s statement of group %d: %v", month, group.ID, err)
+		}
+	}
+}
+
+// groupStatement returns the stored statement of group for the month starting at from, or generates and stores it
+func (s *Service) groupStatement(ctx context.Context, group model.Group, from time.Time) (model.Statement, apperror.Error) {
+	from, to := model.StatementPeriod(from)
+	filter := map[string]any{
+		constants.GroupID: group.ID,
+		constants.Month:   from.Format(model.StatementMonthLayout),
+	}
+
+	statement, err := s.statementRepo.Get(ctx, filter)
+	if !errors.Is(err, apperror.NotFound) {
+		return statement, err
+	}
+
+	bills, err := s.billRepo.GetAll(ctx, map[string]any{constants.GroupID: group.ID}, createdBefore(to))
+	if err.Exists() {
+		return model.Statement{}, err
+	}
+
+	shares := make(model.BillShares, 0)
+	if len(bills) > 0 {
+		shares, err = s.billShareRepo.GetAll(ctx, map[string]any{constants.BillID: model.Bills(bills).IDs()})
+		if err.Exists() {
+			return model.Statement{}, err
+		}
+	}
+	bills = model.Bills(bills).WithShares(shares)
+
+	settlements, err := s.settlementRepo.GetAll(ctx, map[string]any{constants.GroupID: group.ID}, createdBefore(to))
+	if err.Exists() {
+		return model.Statement{}, err
+	}
+
+	userIDs := slices.Concat(
+		model.Bills(bills).ExtractUniqueUserIDs(),
+		shares.ExtractUniqueUserIDs(),
+		model.Settlements(settlements).ExtractUniqueUserIDs(),
+	)
+	users := make(model.Users, 0)
+	if len(userIDs) > 0 {
+		if users, err = s.userSvc.FetchFilteredUsers(ctx, map[string]any{constants.ID: userIDs}); err.Exists() {
+			return model.Statement{}, err
+		}
+	}
+
+	statement = model.Statement{
+		GroupID: group.ID,
+		Month:   from.Format(model.StatementMonthLayout),
+		Data:    buildStatement(group, from, to, bills, settlements, users),
+	}
+
+	err = s.statementRepo.Create(ctx, &statement)
+	// generated by the worker or a concurrent request in the meantime
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return s.statementRepo.Get(postgres.WithConsistency(ctx, postgres.NewConsistency(true)), filter)
+	}
+
+	return statement, err
+}
+
+// checkMonthOver rejects months that end after now, their statement is not final yet
+func checkMonthOver(to time.Time) apperror.Error {
+	if to.After(time.Now()) {
+		return apperror.NewCode(apperror.StatementNotReady, "Statements are available once the month is over")
+	}
+
+	return apperror.Error{}
+}
+
+// createdBefore keeps the rows created before t
+func createdBefore(t time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(constants.CreatedAt+" < ?", t)
+	}
+}
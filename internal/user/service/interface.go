@@ -9,7 +9,6 @@ import (
 
 type Interface interface {
 	FetchFilteredUsers(ctx context.Context, filter map[string]any) (model.Users, apperror.Error)
-	GetUsers(ctx context.Context, currentUserID uint64) (model.Users, apperror.Error)
 	CreateUserAccount(ctx context.Context, req ctrlReq.RegisterRequest) apperror.Error
 	AuthenticateUser(ctx context.Context, email, password string) (model.AuthToken, apperror.Error)
 	SendActivationEmail(ctx context.Context, email string) apperror.Error
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"main/constants"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/logger"
+	"time"
+)
+
+// AccountLockedError is returned by AuthenticateUser while an account is locked after repeated wrong passwords
+type AccountLockedError struct {
+	Until time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return "Account is temporarily locked after too many failed login attempts"
+}
+
+func accountLocked(until time.Time) apperror.Error {
+	return apperror.Wrap(&AccountLockedError{Until: until}, apperror.AccountLocked, "Too many failed login attempts, try again later")
+}
+
+// recordFailedLogin counts a wrong password and locks the account once auth.lockout.maxAttempts is reached.
+// The lock starts a fresh count, so every lockout period grants maxAttempts new tries. The count is the one the
+// increment returns, the user read before the password check may be behind concurrent attempts.
+func (s *Service) recordFailedLogin(ctx context.Context, user model.User) apperror.Error {
+	log := logger.With(ctx, "recordFailedLogin")
+
+	maxAttempts := viper.GetInt("auth.lockout.maxAttempts")
+	if maxAttempts <= 0 {
+		return apperror.Error{}
+	}
+
+	updated, err := s.repo.UpdateReturning(ctx, map[string]any{constants.ID: user.ID}, map[string]any{
+		constants.FailedLoginAttempts: gorm.Expr(constants.FailedLoginAttempts + " + 1"),
+	})
+	if err.Exists() {
+		log.Errorf("failed to record failed login for user %d: %v", user.ID, err)
+		return err
+	}
+	if len(updated) == 0 || updated[0].FailedLoginAttempts < maxAttempts {
+		return apperror.Error{}
+	}
+
+	lockedUntil := time.Now().Add(viper.GetDuration("auth.lockout.duration"))
+	err = s.repo.Update(ctx, map[string]any{constants.ID: user.ID}, map[string]any{
+		constants.FailedLoginAttempts: 0,
+		constants.LockedUntil:         lockedUntil,
+	})
+	if err.Exists() {
+		log.Errorf("failed to lock user %d: %v", user.ID, err)
+		return err
+	}
+
+	log.Warnf("user %d locked until %s after %d failed logins", user.ID, lockedUntil.Format(time.RFC3339), maxAttempts)
+
+	return accountLocked(lockedUntil)
+}
+
+// resetFailedLogins clears the count and an expired lock after a successful login
+func (s *Service) resetFailedLogins(ctx context.Context, user model.User) apperror.Error {
+	if user.FailedLoginAttempts == 0 && user.LockedUntil == nil {
+		return apperror.Error{}
+	}
+
+	return s.repo.Update(ctx, map[string]any{constants.ID: user.ID}, map[string]any{
+		constants.FailedLoginAttempts: 0,
+		constants.LockedUntil:         nil,
+	})
+}
@@ -2,70 +2,59 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
-	"log"
+	"log/slog"
 	"main/constants"
 	ctrlReq "main/internal/controller/request"
 	"main/internal/model"
 	"main/pkg/apperror"
-	"main/util"
-	"net/http"
+	"main/pkg/logger"
+	"main/pkg/tracing"
+	"time"
 )
 
 func (s *Service) FetchFilteredUsers(ctx context.Context, filter map[string]any) (model.Users, apperror.Error) {
-	logTag := util.LogPrefix(ctx, "FetchFilteredUsers")
+	ctx, span := tracing.Start(ctx, "UserService.FetchFilteredUsers")
+	defer span.End()
 
-	users, err := s.repo.GetAll(ctx, filter)
-	if err.Exists() {
-		log.Printf("%s failed to fetch users with provided filters: %v", logTag, err)
+	log := logger.With(ctx, "FetchFilteredUsers")
 
-		return nil, apperror.NewWithMessage("Failed to fetch users", http.StatusBadRequest)
-	}
-
-	return users, apperror.Error{}
-}
-
-func (s *Service) GetUsers(ctx context.Context, currentUserID uint64) (model.Users, apperror.Error) {
-	logTag := util.LogPrefix(ctx, "ListOtherUsers")
-
-	scopes := []func(db *gorm.DB) *gorm.DB{
-		func(db *gorm.DB) *gorm.DB {
-			return db.Where("id != ?", currentUserID)
-		},
-	}
-
-	users, err := s.repo.GetAll(ctx, nil, scopes...)
+	users, err := s.repo.GetAll(ctx, filter)
 	if err.Exists() {
-		log.Printf("%s failed to fetch users excluding current user %d: %v", logTag, currentUserID, err)
+		log.Errorf("failed to fetch users with provided filters: %v", err)
 
-		return nil, apperror.NewWithMessage("Failed to fetch users", http.StatusBadRequest)
+		return nil, apperror.NewCode(apperror.Internal, "Failed to fetch users")
 	}
 
 	return users, apperror.Error{}
 }
 
 func (s *Service) CreateUserAccount(ctx context.Context, req ctrlReq.RegisterRequest) apperror.Error {
-	logTag := util.LogPrefix(ctx, "CreateUserAccount")
+	ctx, span := tracing.Start(ctx, "UserService.CreateUserAccount")
+	defer span.End()
+
+	log := logger.With(ctx, "CreateUserAccount")
 
 	users, err := s.repo.GetAll(ctx, map[string]any{constants.Email: req.Email})
 	if err.Exists() {
-		log.Printf("%s: failed to check existing user for email %s: %v", logTag, req.Email, err)
+		log.Errorf("failed to check existing user for email %s: %v", req.Email, err)
 
-		return apperror.NewWithMessage("Failed to validate user", http.StatusBadRequest)
+		return apperror.NewCode(apperror.Internal, "Failed to validate user")
 	}
 
 	if len(users) > 0 {
-		log.Printf("%s: user already exists with email %s", logTag, req.Email)
-		return apperror.NewWithMessage("User already exists", http.StatusConflict)
+		log.Warnf("user already exists with email %s", req.Email)
+		return apperror.NewCode(apperror.UserAlreadyExists, "User already exists")
 	}
 
 	hashedPass, hashErr := hashPassword(req.Password)
 	if hashErr.Exists() {
-		log.Printf("%s: failed to hash password for email %s: %v", logTag, req.Email, hashErr)
+		log.Errorf("failed to hash password for email %s: %v", req.Email, hashErr)
 
-		return apperror.NewWithMessage("Failed to process password", http.StatusBadRequest)
+		return apperror.NewCode(apperror.Internal, "Failed to process password")
 	}
 
 	user := model.User{
@@ -76,60 +65,86 @@ func (s *Service) CreateUserAccount(ctx context.Context, req ctrlReq.RegisterReq
 	}
 	createErr := s.repo.Create(ctx, &user)
 	if createErr.Exists() {
-		log.Printf("%s: failed to create user for email %s: %v", logTag, req.Email, createErr)
+		log.Errorf("failed to create user for email %s: %v", req.Email, createErr)
+
+		// a concurrent registration can still win the race past the lookup above
+		if errors.Is(createErr, gorm.ErrDuplicatedKey) {
+			return apperror.NewCode(apperror.UserAlreadyExists, "User already exists")
+		}
 
-		return apperror.NewWithMessage("Failed to create user", http.StatusBadRequest)
+		return apperror.NewCode(apperror.Internal, "Failed to create user")
 	}
 
 	return apperror.Error{}
 }
 
 func (s *Service) AuthenticateUser(ctx context.Context, email, password string) (model.AuthToken, apperror.Error) {
-	logTag := util.LogPrefix(ctx, "AuthenticateUser")
+	ctx, span := tracing.Start(ctx, "UserService.AuthenticateUser")
+	defer span.End()
+
+	log := logger.With(ctx, "AuthenticateUser")
 
 	user, err := s.repo.Get(ctx, map[string]any{constants.Email: email})
 	if err.Exists() {
-		log.Printf("%s failed to get user by email %s: %v", logTag, email, err)
+		log.Errorf("failed to get user by email %s: %v", email, err)
 
-		return model.AuthToken{}, apperror.NewWithMessage("User not found", http.StatusNotFound)
+		return model.AuthToken{}, apperror.NewCode(apperror.UserNotFound, "User not found")
 	}
 
 	if !user.IsActive {
-		log.Printf("%s user %s is not active", logTag, email)
+		log.Warnf("user %s is not active", email)
+
+		return model.AuthToken{}, apperror.NewCode(apperror.AccountInactive, "User account is not active")
+	}
 
-		return model.AuthToken{}, apperror.NewWithMessage("User account is not active", http.StatusUnauthorized)
+	// checked before the password so a locked account cannot be brute forced at all
+	if user.IsLocked(time.Now()) {
+		log.Warnf("login attempt for locked user %s", email)
+
+		return model.AuthToken{}, accountLocked(*user.LockedUntil)
 	}
 
 	if !checkPasswordHash(user.Password, password) {
-		log.Printf("%s invalid password for user %s", logTag, email)
+		log.Warnf("invalid password for user %s", email)
+
+		if lockErr := s.recordFailedLogin(ctx, user); errors.As(lockErr, new(*AccountLockedError)) {
+			return model.AuthToken{}, lockErr
+		}
+
+		return model.AuthToken{}, apperror.NewCode(apperror.InvalidCredentials, "Invalid credentials")
+	}
 
-		return model.AuthToken{}, apperror.NewWithMessage("Invalid credentials", http.StatusUnauthorized)
+	if err = s.resetFailedLogins(ctx, user); err.Exists() {
+		log.Errorf("failed to reset failed logins for user %d: %v", user.ID, err)
 	}
 
 	return s.authSvc.GenerateOrUpdateAuthToken(ctx, user.ID)
 }
 
 func (s *Service) SendActivationEmail(ctx context.Context, email string) apperror.Error {
-	logTag := util.LogPrefix(ctx, "SendActivationEmail")
+	ctx, span := tracing.Start(ctx, "UserService.SendActivationEmail")
+	defer span.End()
+
+	log := logger.With(ctx, "SendActivationEmail")
 
 	user, err := s.repo.Get(ctx, map[string]any{constants.Email: email})
 	if err.Exists() {
-		log.Printf("%s failed to fetch user %s: %v", logTag, email, err)
+		log.Errorf("failed to fetch user %s: %v", email, err)
 
-		return apperror.NewWithMessage("User lookup failed", http.StatusBadRequest)
+		return apperror.NewCode(apperror.Internal, "User lookup failed")
 	}
 
 	if user.IsActive {
-		log.Printf("%s user %s is already active", logTag, email)
+		log.Warnf("user %s is already active", email)
 
-		return apperror.NewWithMessage("Account is already activated", http.StatusBadRequest)
+		return apperror.NewCode(apperror.AccountAlreadyActive, "Account is already activated")
 	}
 
 	otp, err := s.otpSvc.GenerateOTP(ctx, user.ID, model.Activation)
 	if err.Exists() {
-		log.Printf("%s failed to generate OTP for user %d: %v", logTag, user.ID, err)
+		log.Errorf("failed to generate OTP for user %d: %v", user.ID, err)
 
-		return apperror.NewWithMessage("Failed to generate OTP", http.StatusBadRequest)
+		return apperror.NewCode(apperror.Internal, "Failed to generate OTP")
 	}
 
 	// TODO: Send OTP via email service
@@ -138,97 +153,114 @@ func (s *Service) SendActivationEmail(ctx context.Context, email string) apperro
 }
 
 func (s *Service) ActivateUserAccount(ctx context.Context, email, password, otp string) apperror.Error {
-	logTag := util.LogPrefix(ctx, "ActivateUserAccount")
+	ctx, span := tracing.Start(ctx, "UserService.ActivateUserAccount")
+	defer span.End()
+
+	log := logger.With(ctx, "ActivateUserAccount")
 
 	user, err := s.repo.Get(ctx, map[string]any{constants.Email: email})
 	if err.Exists() {
-		log.Printf("%s failed to get user by email %s: %v", logTag, email, err)
+		log.Errorf("failed to get user by email %s: %v", email, err)
 
-		return apperror.NewWithMessage("Failed to fetch user", http.StatusBadRequest)
+		return apperror.NewCode(apperror.Internal, "Failed to fetch user")
 	}
 
 	if user.IsActive {
-		log.Printf("%s user %s is already active", logTag, email)
+		log.Warnf("user %s is already active", email)
 
-		return apperror.NewWithMessage("Account already activated", http.StatusBadRequest)
+		return apperror.NewCode(apperror.AccountAlreadyActive, "Account already activated")
 	}
 
 	isValid, err := s.otpSvc.ValidateOTP(ctx, user.ID, model.Activation, otp)
 	if err.Exists() {
-		log.Printf("%s OTP validation failed for user %d: %v", logTag, user.ID, err)
+		log.Errorf("OTP validation failed for user %d: %v", user.ID, err)
 		return err
 	}
 
 	if !isValid {
-		log.Printf("%s invalid or expired OTP for user %d", logTag, user.ID)
-		return apperror.NewWithMessage("Invalid or expired OTP", http.StatusBadRequest)
+		log.Warnf("invalid or expired OTP for user %d", user.ID)
+		return apperror.NewCode(apperror.InvalidOTP, "Invalid or expired OTP")
 	}
 
 	user.IsActive = true
 	user.Password = password
-	err = s.UpdateUserProfile(ctx, user)
-	if err.Exists() {
-		log.Printf("%s - Failed to update user profile after successful OTP validation for user ID %d: %v", logTag, user.ID, err)
-		return err
-	}
 
-	return s.otpSvc.MarkOTPUsed(ctx, user.ID, otp)
+	// activation and OTP consumption must not be applied separately
+	return s.repo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		err := s.UpdateUserProfile(txCtx, user)
+		if err.Exists() {
+			log.Errorf("failed to update user profile after successful OTP validation for user ID %d: %v", user.ID, err)
+			return err
+		}
+
+		return s.otpSvc.MarkOTPUsed(txCtx, user.ID, otp)
+	})
 }
 
 func (s *Service) UpdateUserProfile(ctx context.Context, user model.User) apperror.Error {
-	logTag := util.LogPrefix(ctx, "UpdateUserProfile")
+	ctx, span := tracing.Start(ctx, "UserService.UpdateUserProfile")
+	defer span.End()
+
+	log := logger.With(ctx, "UpdateUserProfile")
 
 	if len(user.Email) > 0 {
 		users, err := s.repo.GetAll(ctx, map[string]any{constants.Email: user.Email})
 		if err.Exists() {
-			log.Printf("%s failed to check email %s: %v", logTag, user.Email, err)
+			log.Errorf("failed to check email %s: %v", user.Email, err)
 
-			return apperror.NewWithMessage("Something went wrong while checking email", http.StatusBadRequest)
+			return apperror.NewCode(apperror.Internal, "Something went wrong while checking email")
 		}
 
 		if len(users) > 0 && users[0].ID != user.ID {
-			log.Printf("%s email %s is already used by user %d", logTag, user.Email, users[0].ID)
+			log.Warnf("email %s is already used by user %d", user.Email, users[0].ID)
 
-			return apperror.NewWithMessage("This email is already registered", http.StatusBadRequest)
+			return apperror.NewCode(apperror.UserAlreadyExists, "This email is already registered")
 		}
 	}
 
 	if len(user.Password) > 0 {
 		hashedPassword, err := hashPassword(user.Password)
 		if err.Exists() {
-			log.Printf("%s failed to hash password: %v", logTag, err)
+			log.Errorf("failed to hash password: %v", err)
 
-			return apperror.NewWithMessage("Failed to hash password", http.StatusBadRequest)
+			return apperror.NewCode(apperror.Internal, "Failed to hash password")
 		}
 		user.Password = hashedPassword
 	}
 
 	err := s.repo.Update(ctx, map[string]any{constants.ID: user.ID}, &user)
 	if err.Exists() {
-		log.Printf("%s failed to update user %d: %v", logTag, user.ID, err)
+		log.Errorf("failed to update user %d: %v", user.ID, err)
+
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return apperror.NewCode(apperror.UserAlreadyExists, "This email is already registered")
+		}
 
-		return apperror.NewWithMessage("Failed to update user", http.StatusBadRequest)
+		return apperror.NewCode(apperror.Internal, "Failed to update user")
 	}
 
 	return s.authSvc.MarkTokenExpired(ctx, user.ID)
 }
 
 func (s *Service) IsUserValid(ctx context.Context, userID uint64) bool {
-	logTag := util.LogPrefix(ctx, "IsUserValid")
+	ctx, span := tracing.Start(ctx, "UserService.IsUserValid")
+	defer span.End()
+
+	log := logger.With(ctx, "IsUserValid")
 
 	user, err := s.repo.Get(ctx, map[string]interface{}{constants.ID: userID})
 	if err.Exists() {
-		log.Printf("%s failed to fetch user %d: %v", logTag, userID, err)
+		log.Errorf("failed to fetch user %d: %v", userID, err)
 		return false
 	}
 
 	if user.ID == 0 {
-		log.Printf("%s user %d not found", logTag, userID)
+		log.Warnf("user %d not found", userID)
 		return false
 	}
 
 	if !user.IsActive {
-		log.Printf("%s user %d is inactive", logTag, userID)
+		log.Warnf("user %d is inactive", userID)
 		return false
 	}
 
@@ -238,9 +270,9 @@ func (s *Service) IsUserValid(ctx context.Context, userID uint64) bool {
 func hashPassword(password string) (string, apperror.Error) {
 	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		log.Printf("Hashing error: %v", err)
+		slog.Error("failed to hash password", slog.Any("error", err))
 
-		return "", apperror.NewWithMessage("Failed to hash password", http.StatusBadRequest)
+		return "", apperror.NewCode(apperror.Internal, "Failed to hash password")
 	}
 
 	return string(hashed), apperror.Error{}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	config "github.com/spf13/viper"
+	"gorm.io/gorm"
+	"main/constants"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/logger"
+	"main/pkg/tracing"
+	"main/pkg/webhook"
+	baseRepository "main/repository"
+	"slices"
+	"time"
+)
+
+// Enqueue writes a pending delivery for every webhook of the group subscribed to the event, the dispatcher sends
+// them from there. It subscribes the webhooks to the outbox.
+func (s *Service) Enqueue(ctx context.Context, event model.OutboxEvent) apperror.Error {
+	ctx, span := tracing.Start(ctx, "WebhookService.Enqueue")
+	defer span.End()
+
+	log := logger.With(ctx, "Enqueue")
+
+	if !slices.Contains(model.WebhookEvents, event.Type) {
+		return apperror.Error{}
+	}
+
+	webhooks, err := s.webhookRepo.GetAll(ctx, map[string]any{constants.GroupID: event.GroupID})
+	if err.Exists() {
+		log.Errorf("failed to fetch webhooks of group %d: %v", event.GroupID, err)
+		return apperror.NewCode(apperror.Internal, "Failed to fetch webhooks")
+	}
+
+	payload, marshalErr := json.Marshal(model.WebhookPayload{
+		ID:         event.ID,
+		Type:       event.Type,
+		GroupID:    event.GroupID,
+		ActorID:    event.ActorID,
+		Data:       event.Data,
+		OccurredAt: event.CreatedAt,
+	})
+	if marshalErr != nil {
+		log.Errorf("failed to encode %s event of group %d: %v", event.Type, event.GroupID, marshalErr)
+		return apperror.NewCode(apperror.Internal, "Failed to encode webhook payload")
+	}
+
+	now := time.Now()
+	deliveries := make([]*model.WebhookDelivery, 0, len(webhooks))
+	for _, hook := range webhooks {
+		if hook.Subscribes(event.Type) {
+			delivery := newDelivery(hook.ID, event.Type, payload, now)
+			deliveries = append(deliveries, &delivery)
+		}
+	}
+	if len(deliveries) == 0 {
+		return apperror.Error{}
+	}
+
+	if err = s.webhookDeliveryRepo.CreateMany(ctx, deliveries); err.Exists() {
+		log.Errorf("failed to queue %d deliveries of %s in group %d: %v", len(deliveries), event.Type, event.GroupID, err)
+		return apperror.NewCode(apperror.Internal, "Failed to queue webhook deliveries")
+	}
+
+	return apperror.Error{}
+}
+
+// Dispatch sends one batch of due deliveries. A batch is claimed by pushing its next attempt a lease away, so other
+// instances pass over it, and a batch claimed by an instance that died is picked up again once the lease ran out.
+func (s *Service) Dispatch(ctx context.Context) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Dispatch")
+	defer span.End()
+
+	log := logger.With(ctx, "Dispatch")
+
+	deliveries, err := s.claimDueDeliveries(ctx, time.Now())
+	if err.Exists() {
+		log.Errorf("failed to claim due webhook deliveries: %v", err)
+		return
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+
+	webhookIDs := make([]uint64, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		webhookIDs = append(webhookIDs, delivery.WebhookID)
+	}
+
+	webhooks, err := s.webhookRepo.GetAll(ctx, map[string]any{constants.ID: webhookIDs})
+	if err.Exists() {
+		log.Errorf("failed to fetch webhooks of %d due deliveries: %v", len(deliveries), err)
+		return
+	}
+
+	byID := make(map[uint64]model.Webhook, len(webhooks))
+	for _, hook := range webhooks {
+		byID[hook.ID] = hook
+	}
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// deleting a webhook deletes its deliveries, one may still have been claimed just before
+		hook, ok := byID[delivery.WebhookID]
+		if !ok {
+			continue
+		}
+
+		s.deliver(ctx, hook, delivery)
+	}
+}
+
+func (s *Service) claimDueDeliveries(ctx context.Context, now time.Time) (model.WebhookDeliveries, apperror.Error) {
+	var deliveries model.WebhookDeliveries
+
+	err := s.webhookDeliveryRepo.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		var err apperror.Error
+		deliveries, err = s.webhookDeliveryRepo.GetAll(txCtx, map[string]any{
+			constants.Status: model.DeliveryPending,
+		}, dueBefore(now, config.GetInt("webhook.batchSize")), baseRepository.ForUpdateSkipLocked)
+		if err.Exists() || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uint64, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+
+		return s.webhookDeliveryRepo.Update(txCtx, map[string]any{constants.ID: ids}, map[string]any{
+			constants.NextAttemptAt: now.Add(config.GetDuration("webhook.claimLease")),
+		})
+	})
+
+	return deliveries, err
+}
+
+// deliver makes one attempt and records its outcome. A failed attempt is retried after an exponential backoff
+// until webhook.maxAttempts is reached, then the delivery is marked failed and only a redelivery sends it again.
+func (s *Service) deliver(ctx context.Context, hook model.Webhook, delivery model.WebhookDelivery) {
+	log := logger.With(ctx, "deliver")
+
+	result, sendErr := s.sender.Send(ctx, webhook.Request{
+		URL:        hook.URL,
+		Secret:     hook.Secret,
+		Event:      string(delivery.Event),
+		DeliveryID: delivery.ID,
+		Payload:    delivery.Payload,
+	})
+
+	now := time.Now()
+	attempts := delivery.Attempts + 1
+	updates := map[string]any{
+		constants.Attempts:     attempts,
+		constants.StatusCode:   result.StatusCode,
+		constants.ResponseBody: result.Body,
+		constants.LastError:    "",
+	}
+
+	switch {
+	case sendErr == nil:
+		updates[constants.Status] = model.DeliverySucceeded
+		updates[constants.DeliveredAt] = now
+	case attempts >= config.GetInt("webhook.maxAttempts"):
+		log.Warnf("giving up on delivery %d to webhook %d after %d attempts: %v", delivery.ID, hook.ID, attempts, sendErr)
+		updates[constants.Status] = model.DeliveryFailed
+		updates[constants.LastError] = sendErr.Error()
+	default:
+		backoff := webhook.Backoff(attempts,
+			config.GetDuration("webhook.backoff.initialInterval"),
+			config.GetDuration("webhook.backoff.maxInterval"))
+		updates[constants.NextAttemptAt] = now.Add(backoff)
+		updates[constants.LastError] = sendErr.Error()
+	}
+
+	if err := s.webhookDeliveryRepo.Update(ctx, map[string]any{constants.ID: delivery.ID}, updates); err.Exists() {
+		log.Errorf("failed to record attempt %d of delivery %d: %v", attempts, delivery.ID, err)
+	}
+}
+
+// dueBefore keeps the oldest deliveries whose next attempt is due, at most limit of them
+func dueBefore(now time.Time, limit int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(constants.NextAttemptAt+" <= ?", now).Order(constants.ID).Limit(limit)
+	}
+}
+
+func newDelivery(webhookID uint64, event model.ActivityType, payload []byte, now time.Time) model.WebhookDelivery {
+	return model.WebhookDelivery{
+		WebhookID:     webhookID,
+		Event:         event,
+		Payload:       payload,
+		Status:        model.DeliveryPending,
+		NextAttemptAt: now,
+	}
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/repository/query"
+)
+
+type Interface interface {
+	CreateWebhook(ctx context.Context, webhook *model.Webhook) apperror.Error
+	GetGroupWebhooks(ctx context.Context, groupID uint64) (model.Webhooks, apperror.Error)
+	GetWebhook(ctx context.Context, groupID, webhookID uint64) (model.Webhook, apperror.Error)
+	DeleteWebhook(ctx context.Context, groupID, webhookID uint64) apperror.Error
+
+	GetDeliveries(
+		ctx context.Context,
+		webhookID uint64,
+		q *query.Query,
+	) (model.WebhookDeliveries, query.Page, apperror.Error)
+
+	Redeliver(ctx context.Context, webhookID, deliveryID uint64) (model.WebhookDelivery, apperror.Error)
+
+	Enqueue(ctx context.Context, event model.OutboxEvent) apperror.Error
+	Dispatch(ctx context.Context)
+}
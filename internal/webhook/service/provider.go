@@ -0,0 +1,18 @@
+package service
+
+import (
+	"github.com/google/wire"
+	webhookRepo "main/internal/webhook/repository"
+	webhookDeliveryRepo "main/internal/webhook_delivery/repository"
+)
+
+var ProviderSet = wire.NewSet(
+	NewService,
+	webhookRepo.NewRepository,
+	webhookDeliveryRepo.NewRepository,
+
+	// bind each one of the interfaces
+	wire.Bind(new(Interface), new(*Service)),
+	wire.Bind(new(webhookRepo.Interface), new(*webhookRepo.Repository)),
+	wire.Bind(new(webhookDeliveryRepo.Interface), new(*webhookDeliveryRepo.Repository)),
+)
@@ -0,0 +1,32 @@
+package service
+
+import (
+	config "github.com/spf13/viper"
+	webhookRepo "main/internal/webhook/repository"
+	webhookDeliveryRepo "main/internal/webhook_delivery/repository"
+	"main/pkg/webhook"
+	"sync"
+)
+
+type Service struct {
+	webhookRepo         webhookRepo.Interface
+	webhookDeliveryRepo webhookDeliveryRepo.Interface
+	sender              *webhook.Sender
+}
+
+var (
+	syncOnce sync.Once
+	svc      *Service
+)
+
+func NewService(webhookRepo webhookRepo.Interface, webhookDeliveryRepo webhookDeliveryRepo.Interface) *Service {
+	syncOnce.Do(func() {
+		svc = &Service{
+			webhookRepo:         webhookRepo,
+			webhookDeliveryRepo: webhookDeliveryRepo,
+			sender:              webhook.NewSender(webhook.NewClient(config.GetDuration("webhook.timeout"))),
+		}
+	})
+
+	return svc
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"main/constants"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/logger"
+	"main/pkg/tracing"
+	"main/pkg/webhook"
+	"main/repository/query"
+	"time"
+)
+
+// CreateWebhook registers a webhook and generates the secret its deliveries are signed with
+func (s *Service) CreateWebhook(ctx context.Context, hook *model.Webhook) apperror.Error {
+	ctx, span := tracing.Start(ctx, "WebhookService.CreateWebhook")
+	defer span.End()
+
+	log := logger.With(ctx, "CreateWebhook")
+
+	if urlErr := webhook.CheckURL(ctx, hook.URL); urlErr != nil {
+		log.Warnf("rejected webhook url for group %d: %v", hook.GroupID, urlErr)
+		return apperror.NewValidation(apperror.FieldError{
+			Field:   constants.URL,
+			Rule:    "public_url",
+			Message: "must be an https URL of a publicly reachable host",
+		})
+	}
+
+	secret, secretErr := webhook.NewSecret()
+	if secretErr != nil {
+		log.Errorf("failed to generate webhook secret: %v", secretErr)
+		return apperror.NewCode(apperror.Internal, "Failed to create webhook")
+	}
+	hook.Secret = secret
+
+	if err := s.webhookRepo.Create(ctx, hook); err.Exists() {
+		log.Errorf("failed to create webhook for group %d: %v", hook.GroupID, err)
+		return apperror.NewCode(apperror.Internal, "Failed to create webhook")
+	}
+
+	return apperror.Error{}
+}
+
+func (s *Service) GetGroupWebhooks(ctx context.Context, groupID uint64) (model.Webhooks, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetGroupWebhooks")
+	defer span.End()
+
+	log := logger.With(ctx, "GetGroupWebhooks")
+
+	webhooks, err := s.webhookRepo.GetAll(ctx, map[string]any{constants.GroupID: groupID})
+	if err.Exists() {
+		log.Errorf("failed to fetch webhooks of group %d: %v", groupID, err)
+		return nil, apperror.NewCode(apperror.Internal, "Failed to fetch webhooks")
+	}
+
+	return webhooks, apperror.Error{}
+}
+
+func (s *Service) GetWebhook(ctx context.Context, groupID, webhookID uint64) (model.Webhook, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetWebhook")
+	defer span.End()
+
+	log := logger.With(ctx, "GetWebhook")
+
+	hook, err := s.webhookRepo.Get(ctx, map[string]any{
+		constants.ID:      webhookID,
+		constants.GroupID: groupID,
+	})
+	if errors.Is(err, apperror.NotFound) {
+		return hook, apperror.NewCode(apperror.WebhookNotFound, "Webhook not found")
+	}
+	if err.Exists() {
+		log.Errorf("failed to retrieve webhook %d of group %d: %v", webhookID, groupID, err)
+		return hook, apperror.NewCode(apperror.Internal, "Failed to retrieve webhook")
+	}
+
+	return hook, apperror.Error{}
+}
+
+// DeleteWebhook removes a webhook for good, its pending deliveries and delivery log go with it
+func (s *Service) DeleteWebhook(ctx context.Context, groupID, webhookID uint64) apperror.Error {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeleteWebhook")
+	defer span.End()
+
+	log := logger.With(ctx, "DeleteWebhook")
+
+	if _, err := s.GetWebhook(ctx, groupID, webhookID); err.Exists() {
+		return err
+	}
+
+	if err := s.webhookRepo.Delete(ctx, map[string]any{constants.ID: webhookID}); err.Exists() {
+		log.Errorf("failed to delete webhook %d: %v", webhookID, err)
+		return apperror.NewCode(apperror.Internal, "Failed to delete webhook")
+	}
+
+	return apperror.Error{}
+}
+
+// GetDeliveries returns one page of the delivery log of a webhook
+func (s *Service) GetDeliveries(
+	ctx context.Context,
+	webhookID uint64,
+	q *query.Query,
+) (model.WebhookDeliveries, query.Page, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetDeliveries")
+	defer span.End()
+
+	log := logger.With(ctx, "GetDeliveries")
+
+	deliveries, page, err := s.webhookDeliveryRepo.GetAllWithPagination(ctx, map[string]any{
+		constants.WebhookID: webhookID,
+	}, q)
+	if err.Exists() {
+		log.Errorf("failed to fetch deliveries of webhook %d: %v", webhookID, err)
+		return nil, page, apperror.NewCode(apperror.Internal, "Failed to fetch webhook deliveries")
+	}
+
+	return deliveries, page, apperror.Error{}
+}
+
+// Redeliver queues the payload of an earlier delivery again as a new delivery, the log keeps the original as it was
+func (s *Service) Redeliver(ctx context.Context, webhookID, deliveryID uint64) (model.WebhookDelivery, apperror.Error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Redeliver")
+	defer span.End()
+
+	log := logger.With(ctx, "Redeliver")
+
+	original, err := s.webhookDeliveryRepo.Get(ctx, map[string]any{
+		constants.ID:        deliveryID,
+		constants.WebhookID: webhookID,
+	})
+	if errors.Is(err, apperror.NotFound) {
+		return model.WebhookDelivery{}, apperror.NewCode(apperror.WebhookDeliveryNotFound, "Webhook delivery not found")
+	}
+	if err.Exists() {
+		log.Errorf("failed to retrieve delivery %d of webhook %d: %v", deliveryID, webhookID, err)
+		return model.WebhookDelivery{}, apperror.NewCode(apperror.Internal, "Failed to retrieve webhook delivery")
+	}
+
+	delivery := newDelivery(webhookID, original.Event, original.Payload, time.Now())
+	if err = s.webhookDeliveryRepo.Create(ctx, &delivery); err.Exists() {
+		log.Errorf("failed to queue redelivery of delivery %d: %v", deliveryID, err)
+		return model.WebhookDelivery{}, apperror.NewCode(apperror.Internal, "Failed to queue webhook delivery")
+	}
+
+	return delivery, apperror.Error{}
+}
@@ -2,24 +2,99 @@ package main
 
 import (
 	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
+	"log/slog"
 	"main/config"
 	initilizer "main/init"
+	"main/internal/health"
+	opostgres "main/pkg/db/postgres"
+	"main/pkg/worker"
 	"main/router"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 func main() {
-	ctx := context.TODO()
+	// cancelled on SIGINT/SIGTERM, which also aborts a database connect still backing off
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	config.InitConfig()
+	initilizer.InitLogger()
+	initilizer.InitValidation()
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := initilizer.Migrate(ctx, os.Args[2:]); err != nil {
+			panic("failed to run migrations: " + err.Error())
+		}
+		return
+	}
+
+	shutdownTracing, err := initilizer.InitTracing(ctx)
+	if err != nil {
+		panic("failed to initialise tracing: " + err.Error())
+	}
+
 	initilizer.Initialize(ctx)
+	initilizer.InitStream()
+
+	workers := worker.NewGroup()
+	initilizer.StartWorkers(ctx, workers)
+	healthHandler := health.NewHandler(opostgres.GetCluster().DbCluster, viper.GetDuration("server.readinessTimeout"))
 
 	app := gin.New()
+	// lets the gin context handed to services resolve values set on the request context, such as the trace span
+	app.ContextWithFallback = true
+	// ClientIP only believes X-Forwarded-For from these, with none it is the address of the connection
+	if err := app.SetTrustedProxies(viper.GetStringSlice("server.trustedProxies")); err != nil {
+		panic("failed to set trusted proxies: " + err.Error())
+	}
 
+	router.RegisterHealthRoutes(app, healthHandler, viper.GetBool("server.debugEndpoints"))
 	router.RegisterPublicRoutes(ctx, app)
 
-	port := viper.GetString("server.port")
-	if err := app.Run(port); err != nil {
-		panic("failed to start server: " + err.Error())
+	server := &http.Server{
+		Addr:    viper.GetString("server.port"),
+		Handler: app,
 	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panic("failed to start server: " + err.Error())
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	slog.Info("shutdown signal received, draining")
+
+	healthHandler.Drain()
+	time.Sleep(viper.GetDuration("server.drainDelay"))
+
+	// ctx is already cancelled, the shutdown steps get a fresh deadline of their own
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("server.shutdownTimeout"))
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("failed to drain in-flight requests", slog.Any("error", err))
+	}
+
+	if err := workers.Wait(shutdownCtx); err != nil {
+		slog.Error("background workers did not stop in time", slog.Any("error", err))
+	}
+
+	if err := opostgres.GetCluster().Close(); err != nil {
+		slog.Error("failed to close database pools", slog.Any("error", err))
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("failed to flush traces", slog.Any("error", err))
+	}
+
+	slog.Info("shutdown complete")
 }
@@ -7,7 +7,7 @@ import (
 	"main/constants"
 	"main/internal/jwt/private"
 	"main/internal/user/service"
-	"net/http"
+	"main/pkg/apperror"
 	"strings"
 	"sync"
 )
@@ -35,27 +35,27 @@ func (a *AuthMiddleware) Authenticate() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 		if !strings.HasPrefix(authHeader, "Bearer ") {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: missing Bearer token"})
-			ctx.Abort()
+			apperror.NewCode(apperror.Unauthenticated, "Missing Bearer token").AbortWithError(ctx)
 			return
 		}
 
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
 		claims, err := parseJWT(tokenStr)
 		if err != nil {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			ctx.Abort()
+			apperror.NewCode(apperror.InvalidToken, "").AbortWithError(ctx)
 			return
 		}
 
 		userDetails := claims.UserDetails
 		if !a.IsUserValid(ctx, userDetails.UserID) {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or inactive user"})
-			ctx.Abort()
+			apperror.NewCode(apperror.Unauthenticated, "Invalid or inactive user").AbortWithError(ctx)
 			return
 		}
 
 		ctx.Set(constants.PrivateUserDetails, &userDetails)
+		if claims.ExpiresAt != nil {
+			ctx.Set(constants.TokenExpiresAt, claims.ExpiresAt.Time)
+		}
 		ctx.Next()
 	}
 }
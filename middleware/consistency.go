@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"github.com/gin-gonic/gin"
+	"main/constants"
+	"main/pkg/db/postgres"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReadMasterUntilHeader carries the pin for clients that do not keep cookies, as "<unix milliseconds>.<signature>"
+const ReadMasterUntilHeader = "X-Read-Master-Until"
+
+type ReadYourWritesConfig struct {
+	// PinDuration keeps a client on the master after its writes, zero only routes reads of the writing request
+	PinDuration time.Duration
+	CookieName  string
+	// Secret signs the pins, without it no pin is handed out
+	Secret []byte
+}
+
+// ReadYourWrites attaches a per-request consistency tracker: once the request writes, its own reads go to the master.
+// With PinDuration set, the response also pins the client to the master for that long through a cookie and header,
+// so a follow-up GET does not miss the write on a lagging replica.
+func ReadYourWrites(cfg ReadYourWritesConfig) gin.HandlerFunc {
+	if len(cfg.Secret) == 0 {
+		cfg.PinDuration = 0
+	}
+
+	return func(ctx *gin.Context) {
+		consistency := postgres.NewConsistency(cfg.PinDuration > 0 && isPinned(ctx, cfg))
+
+		ctx.Set(constants.Consistency, consistency)
+		ctx.Request = ctx.Request.WithContext(postgres.WithConsistency(ctx.Request.Context(), consistency))
+
+		if cfg.PinDuration > 0 {
+			ctx.Writer = &pinWriter{ResponseWriter: ctx.Writer, pin: func(w gin.ResponseWriter) {
+				if consistency.HasWritten() {
+					pin(ctx, w, cfg)
+				}
+			}}
+		}
+
+		ctx.Next()
+	}
+}
+
+func isPinned(ctx *gin.Context, cfg ReadYourWritesConfig) bool {
+	value := ctx.GetHeader(ReadMasterUntilHeader)
+	if value == "" {
+		value, _ = ctx.Cookie(cfg.CookieName)
+	}
+
+	until, signature, found := strings.Cut(value, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(signPin(ctx, cfg, until))) {
+		return false
+	}
+
+	untilMilli, err := strconv.ParseInt(until, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	// a client can only ask for as much as it could have been given
+	remaining := time.Until(time.UnixMilli(untilMilli))
+	return remaining > 0 && remaining <= cfg.PinDuration
+}
+
+func pin(ctx *gin.Context, w gin.ResponseWriter, cfg ReadYourWritesConfig) {
+	until := strconv.FormatInt(time.Now().Add(cfg.PinDuration).UnixMilli(), 10)
+	value := until + "." + signPin(ctx, cfg, until)
+
+	w.Header().Set(ReadMasterUntilHeader, value)
+	http.SetCookie(w, &http.Cookie{
+		Name:     cfg.CookieName,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(cfg.PinDuration.Seconds()) + 1,
+		HttpOnly: true,
+		Secure:   ctx.Request.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// signPin signs the expiry of a pin for the credentials of the request, so a client can neither make up a pin nor
+// reuse one handed out to another session. Authentication runs after this middleware, hence the raw header.
+func signPin(ctx *gin.Context, cfg ReadYourWritesConfig, until string) string {
+	mac := hmac.New(sha256.New, cfg.Secret)
+	mac.Write([]byte(ctx.GetHeader("Authorization")))
+	mac.Write([]byte{0})
+	mac.Write([]byte(until))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// pinWriter runs pin right before the headers go out, which is the last moment a cookie can still be added
+type pinWriter struct {
+	gin.ResponseWriter
+	pin     func(w gin.ResponseWriter)
+	applied bool
+}
+
+func (w *pinWriter) apply() {
+	if !w.applied && !w.ResponseWriter.Written() {
+		w.applied = true
+		w.pin(w.ResponseWriter)
+	}
+}
+
+func (w *pinWriter) WriteHeaderNow() {
+	w.apply()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *pinWriter) Write(data []byte) (int, error) {
+	w.apply()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *pinWriter) WriteString(s string) (int, error) {
+	w.apply()
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"main/constants"
+	"main/pkg/apperror"
+)
+
+// Errors renders the error a handler or middleware reported through apperror.Error.AbortWithError as RFC 7807
+// application/problem+json with the status of its code. It must wrap every handler that reports errors and run
+// inside RequestLogger, so that the request log sees the final status.
+func Errors() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+		renderProblem(ctx)
+	}
+}
+
+// renderProblem writes the last reported error unless a response went out already
+func renderProblem(ctx *gin.Context) {
+	last := ctx.Errors.Last()
+	if last == nil || ctx.Writer.Written() {
+		return
+	}
+
+	var appErr apperror.Error
+	if last.IsType(gin.ErrorTypeBind) {
+		appErr = apperror.Validation(last.Err)
+	} else {
+		appErr = apperror.From(last.Err)
+	}
+
+	if requestID := ctx.GetString(constants.RequestID); requestID != "" {
+		appErr = appErr.With(constants.RequestID, requestID)
+	}
+
+	ctx.Header("Content-Type", apperror.ProblemContentType)
+	ctx.JSON(appErr.StatusCode(), appErr.Problem(ctx.Request.URL.Path))
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"main/internal/idempotency/service"
+	"main/internal/jwt/private"
+	"main/internal/model"
+	"main/pkg/apperror"
+	"main/pkg/logger"
+	"net/http"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentResponseSize = 1 << 20
+)
+
+// replayedHeaders are stored with a response and sent again with its replays, Content-Type is kept on its own
+var replayedHeaders = []string{"ETag", "Location", "Content-Location", "Last-Modified"}
+
+// Idempotency makes mutating requests that carry an Idempotency-Key safe to retry. The first request with a key
+// runs normally and its response is stored, later requests with the same key and payload get that response back
+// without running the handler again. Keys are scoped to the authenticated user, so this must run after Authenticate.
+// Bodies of keyed requests are buffered to be hashed, anything over maxBodySize is rejected before it is read in full.
+func Idempotency(svc service.Interface, maxBodySize int64) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isMutating(ctx.Request.Method) {
+			ctx.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			apperror.NewCode(apperror.BadRequest, "Idempotency-Key must be at most 255 characters").AbortWithError(ctx)
+			return
+		}
+
+		userID, err := private.GetUserID(ctx)
+		if err.Exists() {
+			err.AbortWithError(ctx)
+			return
+		}
+
+		path := ctx.Request.URL.RequestURI()
+		hash := sha256.New()
+		hash.Write([]byte(ctx.Request.Method + " " + path + "\n"))
+
+		// the body is hashed as it is read and kept for the handler, which reads it again
+		var body bytes.Buffer
+		limited := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBodySize)
+		if _, readErr := io.Copy(io.MultiWriter(hash, &body), limited); readErr != nil {
+			if tooLarge := new(http.MaxBytesError); errors.As(readErr, &tooLarge) {
+				apperror.NewCode(apperror.PayloadTooLarge, "Request body is too large").AbortWithError(ctx)
+				return
+			}
+			apperror.NewCode(apperror.BadRequest, "Failed to read request body").AbortWithError(ctx)
+			return
+		}
+		ctx.Request.Body = io.NopCloser(&body)
+
+		record, replay, err := svc.Reserve(ctx, model.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Method:      ctx.Request.Method,
+			Path:        path,
+			RequestHash: hex.EncodeToString(hash.Sum(nil)),
+		})
+		if err.Exists() {
+			err.AbortWithError(ctx)
+			return
+		}
+
+		if replay {
+			ctx.Header(IdempotentReplayedHeader, "true")
+			for name, value := range record.ResponseHeaders {
+				ctx.Header(name, value)
+			}
+			ctx.Data(record.StatusCode, record.ContentType, record.ResponseBody)
+			ctx.Abort()
+			return
+		}
+
+		writer := &recordingWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+
+		ctx.Next()
+		// errors are rendered by Errors further out, the stored response has to include them
+		renderProblem(ctx)
+
+		log := logger.With(ctx, "Idempotency")
+
+		// server errors and responses too large to keep are not replayed, the client may retry them with the same key
+		if writer.Status() >= http.StatusInternalServerError || writer.truncated {
+			if err = svc.Release(ctx, record.ID); err.Exists() {
+				log.Warnf("idempotency key %d stays reserved until it is abandoned: %v", record.ID, err)
+			}
+			return
+		}
+
+		headers := make(model.ResponseHeaders)
+		for _, name := range replayedHeaders {
+			if value := writer.Header().Get(name); value != "" {
+				headers[name] = value
+			}
+		}
+
+		err = svc.Complete(ctx, record.ID, writer.Status(), writer.Header().Get("Content-Type"), headers, writer.body.Bytes())
+		if err.Exists() {
+			log.Warnf("response for idempotency key %d was not stored: %v", record.ID, err)
+		}
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// recordingWriter keeps a copy of the response body for replay, up to maxIdempotentResponseSize
+type recordingWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (w *recordingWriter) record(data []byte) {
+	if w.truncated || w.body.Len()+len(data) > maxIdempotentResponseSize {
+		w.truncated = true
+		return
+	}
+
+	w.body.Write(data)
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.record(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.record([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
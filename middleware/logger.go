@@ -2,52 +2,99 @@ package middleware
 
 import (
 	"bytes"
-	"io/ioutil"
-	"log"
+	"context"
+	"io"
+	"log/slog"
+	"main/constants"
+	"main/pkg/env"
+	"main/pkg/logger"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// bodies are only captured for debug logging and never beyond this size
+const maxLoggedBodySize = 64 << 10
+
 type responseWriter struct {
 	gin.ResponseWriter
 	body *bytes.Buffer
 }
 
 func (rw responseWriter) Write(b []byte) (int, error) {
-	rw.body.Write(b)
+	if rw.body.Len() < maxLoggedBodySize {
+		rw.body.Write(b)
+	}
 	return rw.ResponseWriter.Write(b)
 }
 
+// RequestLogger writes one structured record per request, JSON bodies are added at debug level after redaction
 func RequestLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
+		debug := slog.Default().Enabled(c, slog.LevelDebug)
 
-		// Read the request body
 		var bodyBytes []byte
-		if c.Request.Body != nil {
-			bodyBytes, _ = ioutil.ReadAll(c.Request.Body)
+		if debug && c.Request.Body != nil && isJSON(c.Request.Header.Get("Content-Type")) {
+			bodyBytes, _ = io.ReadAll(io.LimitReader(c.Request.Body, maxLoggedBodySize))
+
+			// Restore the io.ReadCloser with the consumed prefix in front of whatever is left
+			c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(bodyBytes), c.Request.Body), c.Request.Body}
 		}
 
-		// Restore the io.ReadCloser to its original state
-		c.Request.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
+		rw := &responseWriter{body: &bytes.Buffer{}, ResponseWriter: c.Writer}
+		if debug {
+			c.Writer = rw
+		}
 
-		// Log Request
-		log.Println("====== Incoming Request ======")
-		log.Printf("Method: %s | URL: %s", c.Request.Method, c.Request.URL.String())
-		log.Printf("Query Params: %v", c.Request.URL.Query())
-		log.Printf("Body: %s", string(bodyBytes))
+		c.Next()
 
-		// Capture response
-		rw := &responseWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
-		c.Writer = rw
+		status := c.Writer.Status()
+		attrs := []slog.Attr{
+			slog.String(constants.RequestID, env.GetRequestID(c)),
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.String("query", c.Request.URL.RawQuery),
+			slog.Int("status", status),
+			slog.Duration("duration", time.Since(start)),
+			slog.Int("response_size", c.Writer.Size()),
+			slog.String("client_ip", c.ClientIP()),
+		}
 
-		c.Next()
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
 
-		// Log Response
-		duration := time.Since(start)
-		log.Println("====== Response ======")
-		log.Printf("Status: %d | Duration: %v", c.Writer.Status(), duration)
-		log.Printf("Response Body: %s", rw.body.String())
+		if debug {
+			attrs = append(attrs,
+				slog.Any("request_body", logger.RedactJSON(bodyBytes)),
+				slog.Any("response_body", logger.RedactJSON(rw.body.Bytes())),
+			)
+		}
+
+		slog.LogAttrs(context.Background(), statusLevel(status), "request completed", attrs...)
+	}
+}
+
+func statusLevel(status int) slog.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return slog.LevelError
+	case status >= http.StatusBadRequest:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
 	}
 }
+
+func isJSON(contentType string) bool {
+	return strings.HasPrefix(strings.ToLower(contentType), "application/json")
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"main/pkg/metrics"
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute labels requests that hit no route, so scanners cannot blow up label cardinality with raw paths
+const unmatchedRoute = "unmatched"
+
+// Metrics records request counts and latency per route template and status
+func Metrics() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		status := strconv.Itoa(ctx.Writer.Status())
+		metrics.HTTPRequests.WithLabelValues(ctx.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(ctx.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"main/constants"
+	"main/internal/jwt/private"
+	"main/pkg/apperror"
+	"main/pkg/ratelimit"
+	"strconv"
+	"strings"
+)
+
+// RateKey picks what a limiter counts requests by, an empty key lets the request through
+type RateKey func(ctx *gin.Context) string
+
+// ByIP keys on the client address, which comes from X-Forwarded-For only behind server.trustedProxies
+func ByIP(ctx *gin.Context) string {
+	return ctx.ClientIP()
+}
+
+// ByUser keys on the authenticated caller, it must run after Authenticate
+func ByUser(ctx *gin.Context) string {
+	userDetails, ok := ctx.Value(constants.PrivateUserDetails).(*private.UserDetails)
+	if !ok {
+		return ""
+	}
+
+	return strconv.FormatUint(userDetails.UserID, 10)
+}
+
+// ByEmail keys on the email field of a JSON body, the body stays cached for the handler's ShouldBindBodyWithJSON
+func ByEmail(ctx *gin.Context) string {
+	var body struct {
+		Email string `json:"email"`
+	}
+	if err := ctx.ShouldBindBodyWithJSON(&body); err != nil {
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimSpace(body.Email))
+}
+
+// RateLimit rejects requests over the limiter's rate with 429 and a Retry-After header.
+// A failing store lets requests through, losing the limit is better than locking every user out.
+func RateLimit(limiter *ratelimit.Limiter, key RateKey) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		k := key(ctx)
+		if k == "" {
+			ctx.Next()
+			return
+		}
+
+		allowed, retryAfter, err := limiter.Allow(ctx, k)
+		if err != nil {
+			slog.Error("rate limit store failed", slog.String("limiter", limiter.Name()), slog.Any("error", err))
+			ctx.Next()
+			return
+		}
+
+		if !allowed {
+			ctx.Header(ratelimit.RetryAfterHeader, ratelimit.RetryAfter(retryAfter))
+			apperror.NewCode(apperror.RateLimited, "Too many requests, please retry later").AbortWithError(ctx)
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"main/constants"
+	"regexp"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// client supplied IDs are kept only when they are short and safe to echo into logs and headers
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._\-]{1,128}$`)
+
+// RequestID reuses the caller's X-Request-ID or generates one, stores it for env.GetRequestID and echoes it back
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		ctx.Set(constants.RequestID, requestID)
+		ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), constants.RequestID, requestID))
+		ctx.Header(RequestIDHeader, requestID)
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"main/internal/jwt/private"
+	"main/pkg/env"
+	"main/pkg/tracing"
+	"net/http"
+	"strconv"
+)
+
+// Tracing continues the caller's W3C trace or starts one, and makes the span the parent of everything below it.
+// The engine needs ContextWithFallback so services receiving the gin context see the span stored on the request.
+func Tracing() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		parent := otel.GetTextMapPropagator().Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+
+		route := ctx.FullPath()
+		spanName := ctx.Request.Method + " " + route
+		if route == "" {
+			spanName = ctx.Request.Method + " " + unmatchedRoute
+		}
+
+		spanCtx, span := tracing.Tracer().Start(parent, spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(ctx.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(ctx.Request.URL.Path),
+				semconv.ClientAddress(ctx.ClientIP()),
+			),
+		)
+		defer span.End()
+
+		span.SetAttributes(tracing.RequestIDKey.String(env.GetRequestID(ctx)))
+
+		ctx.Request = ctx.Request.WithContext(spanCtx)
+		otel.GetTextMapPropagator().Inject(spanCtx, propagation.HeaderCarrier(ctx.Writer.Header()))
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+
+		if userID, err := private.GetUserID(ctx); !err.Exists() {
+			span.SetAttributes(semconv.EnduserID(strconv.FormatUint(userID, 10)))
+		}
+	}
+}
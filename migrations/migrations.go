@@ -0,0 +1,9 @@
+package migrations
+
+import "embed"
+
+// Dir is the directory inside FS holding the versioned migration files
+const Dir = "sql"
+
+//go:embed sql/*.sql
+var FS embed.FS
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS bill_histories;
+DROP TABLE IF EXISTS bill_splits;
+DROP TABLE IF EXISTS bills;
+DROP TABLE IF EXISTS group_user_permissions;
+DROP TABLE IF EXISTS groups;
+DROP TABLE IF EXISTS otps;
+DROP TABLE IF EXISTS auth_tokens;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,99 @@
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGSERIAL PRIMARY KEY,
+    name       TEXT,
+    email      TEXT,
+    password   TEXT,
+    is_active  BOOLEAN,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS auth_tokens (
+    id                 BIGSERIAL PRIMARY KEY,
+    user_id            BIGINT,
+    access_token       TEXT,
+    refresh_token      TEXT,
+    access_expires_at  TIMESTAMPTZ,
+    refresh_expires_at TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ,
+    updated_at         TIMESTAMPTZ,
+    deleted_at         TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_auth_tokens_deleted_at ON auth_tokens (deleted_at);
+
+CREATE TABLE IF NOT EXISTS otps (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT,
+    code       TEXT,
+    purpose    TEXT,
+    expires_at TIMESTAMPTZ,
+    used       BOOLEAN,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_otps_deleted_at ON otps (deleted_at);
+
+CREATE TABLE IF NOT EXISTS groups (
+    id          BIGSERIAL PRIMARY KEY,
+    owner_id    BIGINT,
+    name        TEXT,
+    description TEXT,
+    created_by  TEXT,
+    updated_by  TEXT,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_groups_deleted_at ON groups (deleted_at);
+
+CREATE TABLE IF NOT EXISTS group_user_permissions (
+    id              BIGSERIAL PRIMARY KEY,
+    group_id        BIGINT,
+    user_id         BIGINT,
+    permission_type TEXT,
+    is_active       BOOLEAN,
+    created_at      TIMESTAMPTZ,
+    updated_at      TIMESTAMPTZ,
+    deleted_at      TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_group_user_permissions_deleted_at ON group_user_permissions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS bills (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT,
+    group_id    BIGINT,
+    paid_amount DECIMAL,
+    description TEXT,
+    category    TEXT,
+    currency    TEXT,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ
+);
+-- databases bootstrapped by the old AutoMigrate may predate these columns
+ALTER TABLE bills ADD COLUMN IF NOT EXISTS category TEXT;
+ALTER TABLE bills ADD COLUMN IF NOT EXISTS currency TEXT;
+CREATE INDEX IF NOT EXISTS idx_bills_deleted_at ON bills (deleted_at);
+
+CREATE TABLE IF NOT EXISTS bill_splits (
+    id             BIGSERIAL PRIMARY KEY,
+    group_id       BIGINT,
+    to_pay_user_id BIGINT,
+    user_id        BIGINT,
+    amount_due     DECIMAL,
+    is_paid        BOOLEAN,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ,
+    deleted_at     TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_bill_splits_deleted_at ON bill_splits (deleted_at);
+
+CREATE TABLE IF NOT EXISTS bill_histories (
+    id      BIGSERIAL PRIMARY KEY,
+    bill_id BIGINT
+);
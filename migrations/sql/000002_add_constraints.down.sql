@@ -0,0 +1,80 @@
+DROP INDEX IF EXISTS idx_bill_histories_bill_id;
+ALTER TABLE bill_histories DROP CONSTRAINT IF EXISTS fk_bill_histories_bill;
+ALTER TABLE bill_histories ALTER COLUMN bill_id DROP NOT NULL;
+
+DROP INDEX IF EXISTS idx_bill_splits_to_pay_user_id;
+DROP INDEX IF EXISTS idx_bill_splits_user_id;
+DROP INDEX IF EXISTS idx_bill_splits_group_id;
+ALTER TABLE bill_splits
+    DROP CONSTRAINT IF EXISTS chk_bill_splits_distinct_users,
+    DROP CONSTRAINT IF EXISTS chk_bill_splits_amount_due,
+    DROP CONSTRAINT IF EXISTS fk_bill_splits_to_pay_user,
+    DROP CONSTRAINT IF EXISTS fk_bill_splits_user,
+    DROP CONSTRAINT IF EXISTS fk_bill_splits_group;
+ALTER TABLE bill_splits ALTER COLUMN is_paid DROP NOT NULL;
+ALTER TABLE bill_splits ALTER COLUMN is_paid DROP DEFAULT;
+ALTER TABLE bill_splits ALTER COLUMN amount_due DROP NOT NULL;
+ALTER TABLE bill_splits ALTER COLUMN to_pay_user_id DROP NOT NULL;
+ALTER TABLE bill_splits ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE bill_splits ALTER COLUMN group_id DROP NOT NULL;
+
+DROP INDEX IF EXISTS idx_bills_group_created_at;
+ALTER TABLE bills
+    DROP CONSTRAINT IF EXISTS chk_bills_paid_amount,
+    DROP CONSTRAINT IF EXISTS fk_bills_group,
+    DROP CONSTRAINT IF EXISTS fk_bills_user;
+ALTER TABLE bills ALTER COLUMN paid_amount DROP NOT NULL;
+ALTER TABLE bills ALTER COLUMN group_id DROP NOT NULL;
+ALTER TABLE bills ALTER COLUMN user_id DROP NOT NULL;
+
+DROP INDEX IF EXISTS idx_group_user_permissions_user_id;
+DROP INDEX IF EXISTS idx_group_user_permissions_group_user_type;
+ALTER TABLE group_user_permissions
+    DROP CONSTRAINT IF EXISTS chk_group_user_permissions_type,
+    DROP CONSTRAINT IF EXISTS fk_group_user_permissions_user,
+    DROP CONSTRAINT IF EXISTS fk_group_user_permissions_group;
+ALTER TABLE group_user_permissions ALTER COLUMN is_active DROP NOT NULL;
+ALTER TABLE group_user_permissions ALTER COLUMN is_active DROP DEFAULT;
+ALTER TABLE group_user_permissions ALTER COLUMN permission_type DROP NOT NULL;
+ALTER TABLE group_user_permissions ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE group_user_permissions ALTER COLUMN group_id DROP NOT NULL;
+
+DROP INDEX IF EXISTS idx_groups_owner_id;
+ALTER TABLE groups DROP CONSTRAINT IF EXISTS fk_groups_owner;
+ALTER TABLE groups ALTER COLUMN name DROP NOT NULL;
+ALTER TABLE groups ALTER COLUMN owner_id DROP NOT NULL;
+
+DROP INDEX IF EXISTS idx_otps_user_purpose_used;
+ALTER TABLE otps DROP CONSTRAINT IF EXISTS fk_otps_user;
+ALTER TABLE otps ALTER COLUMN used DROP NOT NULL;
+ALTER TABLE otps ALTER COLUMN used DROP DEFAULT;
+ALTER TABLE otps ALTER COLUMN purpose DROP NOT NULL;
+ALTER TABLE otps ALTER COLUMN code DROP NOT NULL;
+ALTER TABLE otps ALTER COLUMN user_id DROP NOT NULL;
+
+DROP INDEX IF EXISTS idx_auth_tokens_user_id;
+ALTER TABLE auth_tokens DROP CONSTRAINT IF EXISTS fk_auth_tokens_user;
+ALTER TABLE auth_tokens ALTER COLUMN user_id DROP NOT NULL;
+
+DROP INDEX IF EXISTS idx_users_email;
+ALTER TABLE users ALTER COLUMN is_active DROP NOT NULL;
+ALTER TABLE users ALTER COLUMN is_active DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN password DROP NOT NULL;
+ALTER TABLE users ALTER COLUMN email DROP NOT NULL;
+
+-- the rows the up migration quarantined go back where they came from, nothing holds them out anymore
+INSERT INTO groups SELECT (jsonb_populate_record(NULL::groups, row_data)).*
+FROM migration_quarantine WHERE source_table = 'groups';
+INSERT INTO bills SELECT (jsonb_populate_record(NULL::bills, row_data)).*
+FROM migration_quarantine WHERE source_table = 'bills';
+INSERT INTO bill_histories SELECT (jsonb_populate_record(NULL::bill_histories, row_data)).*
+FROM migration_quarantine WHERE source_table = 'bill_histories';
+INSERT INTO bill_splits SELECT (jsonb_populate_record(NULL::bill_splits, row_data)).*
+FROM migration_quarantine WHERE source_table = 'bill_splits';
+INSERT INTO group_user_permissions SELECT (jsonb_populate_record(NULL::group_user_permissions, row_data)).*
+FROM migration_quarantine WHERE source_table = 'group_user_permissions';
+INSERT INTO otps SELECT (jsonb_populate_record(NULL::otps, row_data)).*
+FROM migration_quarantine WHERE source_table = 'otps';
+INSERT INTO auth_tokens SELECT (jsonb_populate_record(NULL::auth_tokens, row_data)).*
+FROM migration_quarantine WHERE source_table = 'auth_tokens';
+DROP TABLE IF EXISTS migration_quarantine;
@@ -0,0 +1,175 @@
+-- Databases bootstrapped by the old AutoMigrate had no constraints and may hold rows that break the ones added
+-- below. They are cleaned up first, so the migration does not fail on them, and nothing is thrown away:
+--  * missing flags get their column default
+--  * duplicates the unique indexes reject are soft-deleted, the oldest row is kept (the newest for tokens)
+--  * rows no constraint can accept are moved to migration_quarantine as JSON, with the table they came from and why:
+--    tokens, OTPs, permissions and splits of users or groups that no longer exist, groups without an owner or
+--    members, bills without an existing group, payer or positive amount, and the history of those bills.
+--    Soft-deleting them is not enough, foreign keys and checks hold for soft-deleted rows too. Reverting the
+--    migration puts them back.
+CREATE TABLE IF NOT EXISTS migration_quarantine (
+    id             BIGSERIAL PRIMARY KEY,
+    source_table   TEXT        NOT NULL,
+    reason         TEXT        NOT NULL,
+    row_data       JSONB       NOT NULL,
+    quarantined_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+UPDATE users SET is_active = FALSE WHERE is_active IS NULL;
+UPDATE otps SET used = FALSE WHERE used IS NULL;
+UPDATE group_user_permissions SET is_active = TRUE WHERE is_active IS NULL;
+UPDATE bill_splits SET is_paid = FALSE WHERE is_paid IS NULL;
+
+-- a user without an email or a password can never log in, they keep their rows under a placeholder address
+UPDATE users SET email = 'user-' || id || '@invalid' WHERE email IS NULL;
+UPDATE users SET password = '' WHERE password IS NULL;
+UPDATE users u SET deleted_at = NOW()
+WHERE u.deleted_at IS NULL
+  AND EXISTS (SELECT 1 FROM users o WHERE o.email = u.email AND o.deleted_at IS NULL AND o.id < u.id);
+
+WITH moved AS (
+    DELETE FROM auth_tokens t
+    WHERE t.user_id IS NULL OR NOT EXISTS (SELECT 1 FROM users u WHERE u.id = t.user_id)
+    RETURNING t.*)
+INSERT INTO migration_quarantine (source_table, reason, row_data)
+SELECT 'auth_tokens', 'user does not exist', to_jsonb(moved) FROM moved;
+UPDATE auth_tokens t SET deleted_at = NOW()
+WHERE t.deleted_at IS NULL
+  AND EXISTS (SELECT 1 FROM auth_tokens o WHERE o.user_id = t.user_id AND o.deleted_at IS NULL AND o.id > t.id);
+
+WITH moved AS (
+    DELETE FROM otps o
+    WHERE o.user_id IS NULL OR o.code IS NULL OR o.purpose IS NULL
+       OR NOT EXISTS (SELECT 1 FROM users u WHERE u.id = o.user_id)
+    RETURNING o.*)
+INSERT INTO migration_quarantine (source_table, reason, row_data)
+SELECT 'otps', 'incomplete or user does not exist', to_jsonb(moved) FROM moved;
+
+-- a group whose owner is gone goes to its earliest member, a group left without members is unreachable
+UPDATE groups g SET owner_id = (
+    SELECT MIN(p.user_id) FROM group_user_permissions p JOIN users u ON u.id = p.user_id WHERE p.group_id = g.id
+)
+WHERE g.owner_id IS NULL OR NOT EXISTS (SELECT 1 FROM users u WHERE u.id = g.owner_id);
+UPDATE groups SET name = 'Untitled group' WHERE name IS NULL;
+WITH moved AS (
+    DELETE FROM groups g WHERE g.owner_id IS NULL
+    RETURNING g.*)
+INSERT INTO migration_quarantine (source_table, reason, row_data)
+SELECT 'groups', 'no owner and no members', to_jsonb(moved) FROM moved;
+
+WITH moved AS (
+    DELETE FROM group_user_permissions p
+    WHERE p.group_id IS NULL OR p.user_id IS NULL OR p.permission_type NOT IN ('View', 'Edit', 'Create', 'Delete')
+       OR p.permission_type IS NULL
+       OR NOT EXISTS (SELECT 1 FROM groups g WHERE g.id = p.group_id)
+       OR NOT EXISTS (SELECT 1 FROM users u WHERE u.id = p.user_id)
+    RETURNING p.*)
+INSERT INTO migration_quarantine (source_table, reason, row_data)
+SELECT 'group_user_permissions', 'invalid type or group or user does not exist', to_jsonb(moved) FROM moved;
+UPDATE group_user_permissions p SET deleted_at = NOW()
+WHERE p.deleted_at IS NULL
+  AND EXISTS (SELECT 1 FROM group_user_permissions o
+              WHERE o.group_id = p.group_id AND o.user_id = p.user_id AND o.permission_type = p.permission_type
+                AND o.deleted_at IS NULL AND o.id < p.id);
+
+WITH moved AS (
+    DELETE FROM bill_splits s
+    WHERE s.group_id IS NULL OR s.user_id IS NULL OR s.to_pay_user_id IS NULL
+       OR s.amount_due IS NULL OR s.amount_due < 0 OR s.user_id = s.to_pay_user_id
+       OR NOT EXISTS (SELECT 1 FROM groups g WHERE g.id = s.group_id)
+       OR NOT EXISTS (SELECT 1 FROM users u WHERE u.id = s.user_id)
+       OR NOT EXISTS (SELECT 1 FROM users u WHERE u.id = s.to_pay_user_id)
+    RETURNING s.*)
+INSERT INTO migration_quarantine (source_table, reason, row_data)
+SELECT 'bill_splits', 'invalid amount or group or user does not exist', to_jsonb(moved) FROM moved;
+
+WITH moved AS (
+    DELETE FROM bill_histories h
+    WHERE h.bill_id IS NULL
+       OR h.bill_id IN (
+           SELECT b.id FROM bills b
+           WHERE b.group_id IS NULL OR b.user_id IS NULL OR b.paid_amount IS NULL OR b.paid_amount <= 0
+              OR NOT EXISTS (SELECT 1 FROM groups g WHERE g.id = b.group_id)
+              OR NOT EXISTS (SELECT 1 FROM users u WHERE u.id = b.user_id))
+       OR NOT EXISTS (SELECT 1 FROM bills b WHERE b.id = h.bill_id)
+    RETURNING h.*)
+INSERT INTO migration_quarantine (source_table, reason, row_data)
+SELECT 'bill_histories', 'bill is quarantined or does not exist', to_jsonb(moved) FROM moved;
+WITH moved AS (
+    DELETE FROM bills b
+    WHERE b.group_id IS NULL OR b.user_id IS NULL OR b.paid_amount IS NULL OR b.paid_amount <= 0
+       OR NOT EXISTS (SELECT 1 FROM groups g WHERE g.id = b.group_id)
+       OR NOT EXISTS (SELECT 1 FROM users u WHERE u.id = b.user_id)
+    RETURNING b.*)
+INSERT INTO migration_quarantine (source_table, reason, row_data)
+SELECT 'bills', 'amount is not positive or group or payer does not exist', to_jsonb(moved) FROM moved;
+
+ALTER TABLE users ALTER COLUMN email SET NOT NULL;
+ALTER TABLE users ALTER COLUMN password SET NOT NULL;
+ALTER TABLE users ALTER COLUMN is_active SET DEFAULT FALSE;
+ALTER TABLE users ALTER COLUMN is_active SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email) WHERE deleted_at IS NULL;
+
+ALTER TABLE auth_tokens ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE auth_tokens
+    ADD CONSTRAINT fk_auth_tokens_user FOREIGN KEY (user_id) REFERENCES users (id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_auth_tokens_user_id ON auth_tokens (user_id) WHERE deleted_at IS NULL;
+
+ALTER TABLE otps ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE otps ALTER COLUMN code SET NOT NULL;
+ALTER TABLE otps ALTER COLUMN purpose SET NOT NULL;
+ALTER TABLE otps ALTER COLUMN used SET DEFAULT FALSE;
+ALTER TABLE otps ALTER COLUMN used SET NOT NULL;
+ALTER TABLE otps
+    ADD CONSTRAINT fk_otps_user FOREIGN KEY (user_id) REFERENCES users (id);
+CREATE INDEX IF NOT EXISTS idx_otps_user_purpose_used ON otps (user_id, purpose, used);
+
+ALTER TABLE groups ALTER COLUMN owner_id SET NOT NULL;
+ALTER TABLE groups ALTER COLUMN name SET NOT NULL;
+ALTER TABLE groups
+    ADD CONSTRAINT fk_groups_owner FOREIGN KEY (owner_id) REFERENCES users (id);
+CREATE INDEX IF NOT EXISTS idx_groups_owner_id ON groups (owner_id);
+
+ALTER TABLE group_user_permissions ALTER COLUMN group_id SET NOT NULL;
+ALTER TABLE group_user_permissions ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE group_user_permissions ALTER COLUMN permission_type SET NOT NULL;
+ALTER TABLE group_user_permissions ALTER COLUMN is_active SET DEFAULT TRUE;
+ALTER TABLE group_user_permissions ALTER COLUMN is_active SET NOT NULL;
+ALTER TABLE group_user_permissions
+    ADD CONSTRAINT fk_group_user_permissions_group FOREIGN KEY (group_id) REFERENCES groups (id),
+    ADD CONSTRAINT fk_group_user_permissions_user FOREIGN KEY (user_id) REFERENCES users (id),
+    ADD CONSTRAINT chk_group_user_permissions_type CHECK (permission_type IN ('View', 'Edit', 'Create', 'Delete'));
+-- soft-deleted rows are excluded so a permission can be granted again after removal
+CREATE UNIQUE INDEX IF NOT EXISTS idx_group_user_permissions_group_user_type
+    ON group_user_permissions (group_id, user_id, permission_type) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_group_user_permissions_user_id ON group_user_permissions (user_id);
+
+ALTER TABLE bills ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE bills ALTER COLUMN group_id SET NOT NULL;
+ALTER TABLE bills ALTER COLUMN paid_amount SET NOT NULL;
+ALTER TABLE bills
+    ADD CONSTRAINT fk_bills_user FOREIGN KEY (user_id) REFERENCES users (id),
+    ADD CONSTRAINT fk_bills_group FOREIGN KEY (group_id) REFERENCES groups (id),
+    ADD CONSTRAINT chk_bills_paid_amount CHECK (paid_amount > 0);
+CREATE INDEX IF NOT EXISTS idx_bills_group_created_at ON bills (group_id, created_at);
+
+ALTER TABLE bill_splits ALTER COLUMN group_id SET NOT NULL;
+ALTER TABLE bill_splits ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE bill_splits ALTER COLUMN to_pay_user_id SET NOT NULL;
+ALTER TABLE bill_splits ALTER COLUMN amount_due SET NOT NULL;
+ALTER TABLE bill_splits ALTER COLUMN is_paid SET DEFAULT FALSE;
+ALTER TABLE bill_splits ALTER COLUMN is_paid SET NOT NULL;
+ALTER TABLE bill_splits
+    ADD CONSTRAINT fk_bill_splits_group FOREIGN KEY (group_id) REFERENCES groups (id),
+    ADD CONSTRAINT fk_bill_splits_user FOREIGN KEY (user_id) REFERENCES users (id),
+    ADD CONSTRAINT fk_bill_splits_to_pay_user FOREIGN KEY (to_pay_user_id) REFERENCES users (id),
+    ADD CONSTRAINT chk_bill_splits_amount_due CHECK (amount_due >= 0),
+    ADD CONSTRAINT chk_bill_splits_distinct_users CHECK (user_id <> to_pay_user_id);
+CREATE INDEX IF NOT EXISTS idx_bill_splits_group_id ON bill_splits (group_id);
+CREATE INDEX IF NOT EXISTS idx_bill_splits_user_id ON bill_splits (user_id);
+CREATE INDEX IF NOT EXISTS idx_bill_splits_to_pay_user_id ON bill_splits (to_pay_user_id);
+
+ALTER TABLE bill_histories ALTER COLUMN bill_id SET NOT NULL;
+ALTER TABLE bill_histories
+    ADD CONSTRAINT fk_bill_histories_bill FOREIGN KEY (bill_id) REFERENCES bills (id);
+CREATE INDEX IF NOT EXISTS idx_bill_histories_bill_id ON bill_histories (bill_id);
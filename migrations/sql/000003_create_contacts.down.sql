@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS contact_invites;
+DROP TABLE IF EXISTS contacts;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS contacts (
+    id              BIGSERIAL PRIMARY KEY,
+    user_id         BIGINT      NOT NULL REFERENCES users (id),
+    contact_user_id BIGINT      NOT NULL REFERENCES users (id),
+    created_at      TIMESTAMPTZ,
+    updated_at      TIMESTAMPTZ,
+    deleted_at      TIMESTAMPTZ,
+    CONSTRAINT chk_contacts_distinct_users CHECK (user_id <> contact_user_id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contacts_user_contact
+    ON contacts (user_id, contact_user_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_contacts_deleted_at ON contacts (deleted_at);
+
+CREATE TABLE IF NOT EXISTS contact_invites (
+    id          BIGSERIAL PRIMARY KEY,
+    inviter_id  BIGINT NOT NULL REFERENCES users (id),
+    email       TEXT   NOT NULL,
+    accepted_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contact_invites_inviter_email
+    ON contact_invites (inviter_id, email) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_contact_invites_email ON contact_invites (email);
+CREATE INDEX IF NOT EXISTS idx_contact_invites_deleted_at ON contact_invites (deleted_at);
+
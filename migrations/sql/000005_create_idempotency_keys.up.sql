@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       BIGINT      NOT NULL REFERENCES users (id),
+    key           TEXT        NOT NULL,
+    method        TEXT        NOT NULL,
+    path          TEXT        NOT NULL,
+    request_hash  TEXT        NOT NULL,
+    status_code   INTEGER     NOT NULL DEFAULT 0,
+    content_type  TEXT        NOT NULL DEFAULT '',
+    response_body BYTEA,
+    completed_at  TIMESTAMPTZ,
+    expires_at    TIMESTAMPTZ NOT NULL,
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_idempotency_keys_user_key ON idempotency_keys (user_id, key);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
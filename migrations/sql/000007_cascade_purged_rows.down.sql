@@ -0,0 +1,12 @@
+ALTER TABLE bill_histories
+    DROP CONSTRAINT IF EXISTS fk_bill_histories_bill,
+    ADD CONSTRAINT fk_bill_histories_bill FOREIGN KEY (bill_id) REFERENCES bills (id);
+ALTER TABLE bill_splits
+    DROP CONSTRAINT IF EXISTS fk_bill_splits_group,
+    ADD CONSTRAINT fk_bill_splits_group FOREIGN KEY (group_id) REFERENCES groups (id);
+ALTER TABLE bills
+    DROP CONSTRAINT IF EXISTS fk_bills_group,
+    ADD CONSTRAINT fk_bills_group FOREIGN KEY (group_id) REFERENCES groups (id);
+ALTER TABLE group_user_permissions
+    DROP CONSTRAINT IF EXISTS fk_group_user_permissions_group,
+    ADD CONSTRAINT fk_group_user_permissions_group FOREIGN KEY (group_id) REFERENCES groups (id);
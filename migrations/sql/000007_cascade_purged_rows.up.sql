@@ -0,0 +1,14 @@
+-- purging a group or a bill from the trash takes the rows that belong to it along
+ALTER TABLE group_user_permissions
+    DROP CONSTRAINT IF EXISTS fk_group_user_permissions_group,
+    ADD CONSTRAINT fk_group_user_permissions_group FOREIGN KEY (group_id) REFERENCES groups (id) ON DELETE CASCADE;
+ALTER TABLE bills
+    DROP CONSTRAINT IF EXISTS fk_bills_group,
+    ADD CONSTRAINT fk_bills_group FOREIGN KEY (group_id) REFERENCES groups (id) ON DELETE CASCADE;
+ALTER TABLE bill_splits
+    DROP CONSTRAINT IF EXISTS fk_bill_splits_group,
+    ADD CONSTRAINT fk_bill_splits_group FOREIGN KEY (group_id) REFERENCES groups (id) ON DELETE CASCADE;
+ALTER TABLE bill_histories
+    DROP CONSTRAINT IF EXISTS fk_bill_histories_bill,
+    ADD CONSTRAINT fk_bill_histories_bill FOREIGN KEY (bill_id) REFERENCES bills (id) ON DELETE CASCADE;
+
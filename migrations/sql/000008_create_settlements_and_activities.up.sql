@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS settlements (
+    id           BIGSERIAL PRIMARY KEY,
+    group_id     BIGINT           NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    from_user_id BIGINT           NOT NULL REFERENCES users (id),
+    to_user_id   BIGINT           NOT NULL REFERENCES users (id),
+    amount       DOUBLE PRECISION NOT NULL,
+    currency     VARCHAR(3)       NOT NULL,
+    note         TEXT             NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ,
+    updated_at   TIMESTAMPTZ,
+    CONSTRAINT chk_settlements_amount CHECK (amount > 0),
+    CONSTRAINT chk_settlements_distinct_users CHECK (from_user_id <> to_user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_settlements_group_created_at ON settlements (group_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_settlements_from_user_id ON settlements (from_user_id);
+CREATE INDEX IF NOT EXISTS idx_settlements_to_user_id ON settlements (to_user_id);
+
+CREATE TABLE IF NOT EXISTS activities (
+    id         BIGSERIAL PRIMARY KEY,
+    group_id   BIGINT      NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    actor_id   BIGINT      NOT NULL REFERENCES users (id),
+    type       TEXT        NOT NULL,
+    data       JSONB       NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_activities_group_created_at ON activities (group_id, created_at);
@@ -0,0 +1,16 @@
+-- events outlive their group until relayed, so there is no foreign key to groups
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id         BIGSERIAL PRIMARY KEY,
+    group_id   BIGINT      NOT NULL,
+    actor_id   BIGINT      NOT NULL,
+    type       TEXT        NOT NULL,
+    data       JSONB       NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_created_at ON outbox_events (created_at);
+
+CREATE TABLE IF NOT EXISTS outbox_positions (
+    subscriber TEXT PRIMARY KEY,
+    event_id   BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ
+);
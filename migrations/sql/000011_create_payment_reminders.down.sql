@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS payment_reminders;
+DROP TABLE IF EXISTS reminder_settings;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS reminder_settings (
+    group_id      BIGINT PRIMARY KEY REFERENCES groups (id) ON DELETE CASCADE,
+    enabled       BOOLEAN     NOT NULL DEFAULT FALSE,
+    interval_days INT         NOT NULL,
+    updated_by    BIGINT      NOT NULL REFERENCES users (id),
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ,
+    CONSTRAINT chk_reminder_settings_interval_days CHECK (interval_days > 0)
+);
+
+-- one row per debtor and creditor, it outlives the splits between them when they are recalculated
+CREATE TABLE IF NOT EXISTS payment_reminders (
+    id               BIGSERIAL PRIMARY KEY,
+    group_id         BIGINT NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    user_id          BIGINT NOT NULL REFERENCES users (id),
+    to_pay_user_id   BIGINT NOT NULL REFERENCES users (id),
+    last_reminded_at TIMESTAMPTZ,
+    snoozed_until    TIMESTAMPTZ,
+    last_nudged_at   TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_reminders_pair ON payment_reminders (group_id, user_id, to_pay_user_id);
@@ -0,0 +1,9 @@
+-- one statement per group and month, kept as generated once the month is over
+CREATE TABLE IF NOT EXISTS statements (
+    id         BIGSERIAL PRIMARY KEY,
+    group_id   BIGINT NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    month      TEXT   NOT NULL,
+    data       JSONB  NOT NULL,
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_statements_group_month ON statements (group_id, month);
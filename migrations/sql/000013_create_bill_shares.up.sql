@@ -0,0 +1,11 @@
+-- what each member owes of a bill, bills without shares are split equally
+CREATE TABLE IF NOT EXISTS bill_shares (
+    id         BIGSERIAL PRIMARY KEY,
+    bill_id    BIGINT           NOT NULL REFERENCES bills (id) ON DELETE CASCADE,
+    user_id    BIGINT           NOT NULL REFERENCES users (id),
+    amount     DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMPTZ,
+    CONSTRAINT chk_bill_shares_amount CHECK (amount > 0),
+    CONSTRAINT uq_bill_shares_bill_user UNIQUE (bill_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_bill_shares_user_id ON bill_shares (user_id);
@@ -0,0 +1 @@
+ALTER TABLE bill_splits DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,14 @@
+-- splits are settled in the currency the bills were paid in, a group with bills in several currencies gets splits
+-- per currency
+ALTER TABLE bill_splits ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT '';
+
+-- existing splits of groups that only use one currency are in that currency, the others stay empty until the group
+-- recalculates its splits
+UPDATE bill_splits
+SET currency = single.currency
+FROM (SELECT group_id, MIN(currency) AS currency
+      FROM bills
+      WHERE deleted_at IS NULL
+      GROUP BY group_id
+      HAVING COUNT(DISTINCT currency) = 1) AS single
+WHERE bill_splits.group_id = single.group_id;
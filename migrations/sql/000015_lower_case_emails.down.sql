@@ -0,0 +1,2 @@
+-- invite emails stay in lower case, the original spelling is not kept
+DROP INDEX IF EXISTS idx_users_email_lower;
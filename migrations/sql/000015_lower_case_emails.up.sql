@@ -0,0 +1,10 @@
+-- contacts are found by email whatever its case: invites are kept in lower case and users are looked up on
+-- LOWER(email). Pending invites that only differ in case are duplicates, the oldest one is kept.
+UPDATE contact_invites i SET deleted_at = NOW()
+WHERE i.deleted_at IS NULL
+  AND EXISTS (SELECT 1 FROM contact_invites o
+              WHERE o.inviter_id = i.inviter_id AND LOWER(o.email) = LOWER(i.email)
+                AND o.deleted_at IS NULL AND o.id < i.id);
+UPDATE contact_invites SET email = LOWER(email) WHERE email <> LOWER(email) AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_email_lower ON users (LOWER(email)) WHERE deleted_at IS NULL;
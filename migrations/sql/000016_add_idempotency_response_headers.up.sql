@@ -0,0 +1,2 @@
+-- headers replayed with a stored response besides its content type, such as ETag and Location
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS response_headers JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,15 @@
+-- each event a subscriber received, an event committed after later ids were relayed is still picked up
+CREATE TABLE IF NOT EXISTS outbox_deliveries (
+    subscriber TEXT   NOT NULL,
+    event_id   BIGINT NOT NULL REFERENCES outbox_events (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ,
+    PRIMARY KEY (subscriber, event_id)
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_deliveries_event_id ON outbox_deliveries (event_id);
+
+-- everything up to a position was received
+INSERT INTO outbox_deliveries (subscriber, event_id, created_at)
+SELECT p.subscriber, e.id, NOW()
+FROM outbox_positions p
+JOIN outbox_events e ON e.id <= p.event_id
+ON CONFLICT DO NOTHING;
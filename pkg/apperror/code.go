@@ -0,0 +1,170 @@
+package apperror
+
+import (
+	"net/http"
+)
+
+// Code is a stable, machine readable error identifier. Clients switch on codes, so an existing code is never
+// renamed or given another status. A Code is an error itself, which lets callers test errors.Is(err, GroupNotFound).
+type Code string
+
+// generic codes, one per status, for failures nothing more specific describes
+const (
+	BadRequest           Code = "BAD_REQUEST"
+	ValidationFailed     Code = "VALIDATION_FAILED"
+	Unauthenticated      Code = "UNAUTHENTICATED"
+	PermissionDenied     Code = "PERMISSION_DENIED"
+	NotFound             Code = "NOT_FOUND"
+	Conflict             Code = "CONFLICT"
+	PreconditionFailed   Code = "PRECONDITION_FAILED"
+	PayloadTooLarge      Code = "PAYLOAD_TOO_LARGE"
+	UnprocessableEntity  Code = "UNPROCESSABLE_ENTITY"
+	PreconditionRequired Code = "PRECONDITION_REQUIRED"
+	RateLimited          Code = "RATE_LIMITED"
+	Internal             Code = "INTERNAL_ERROR"
+	Unavailable          Code = "SERVICE_UNAVAILABLE"
+)
+
+// domain codes
+const (
+	UserNotFound             Code = "USER_NOT_FOUND"
+	UserAlreadyExists        Code = "USER_ALREADY_EXISTS"
+	AccountInactive          Code = "ACCOUNT_INACTIVE"
+	AccountAlreadyActive     Code = "ACCOUNT_ALREADY_ACTIVE"
+	AccountLocked            Code = "ACCOUNT_LOCKED"
+	InvalidCredentials       Code = "INVALID_CREDENTIALS"
+	InvalidToken             Code = "INVALID_TOKEN"
+	InvalidOTP               Code = "INVALID_OTP"
+	OTPExpired               Code = "OTP_EXPIRED"
+	GroupNotFound            Code = "GROUP_NOT_FOUND"
+	AlreadyGroupMember       Code = "ALREADY_GROUP_MEMBER"
+	BillNotFound             Code = "BILL_NOT_FOUND"
+	InvalidAmount            Code = "INVALID_AMOUNT"
+	InvalidReference         Code = "INVALID_REFERENCE"
+	NoBillsToSplit           Code = "NO_BILLS_TO_SPLIT"
+	GroupHasNoMembers        Code = "GROUP_HAS_NO_MEMBERS"
+	SplitAlreadyExists       Code = "SPLIT_ALREADY_EXISTS"
+	ContactNotFound          Code = "CONTACT_NOT_FOUND"
+	ContactAlreadyExists     Code = "CONTACT_ALREADY_EXISTS"
+	InviteAlreadySent        Code = "INVITE_ALREADY_SENT"
+	InviteNotFound           Code = "INVITE_NOT_FOUND"
+	InvalidCSV               Code = "INVALID_CSV"
+	InvalidImportRows        Code = "INVALID_IMPORT_ROWS"
+	VersionConflict          Code = "VERSION_CONFLICT"
+	IdempotencyKeyReused     Code = "IDEMPOTENCY_KEY_REUSED"
+	IdempotencyKeyInProgress Code = "IDEMPOTENCY_KEY_IN_PROGRESS"
+	NothingToSettle          Code = "NOTHING_TO_SETTLE"
+	WebhookNotFound          Code = "WEBHOOK_NOT_FOUND"
+	WebhookDeliveryNotFound  Code = "WEBHOOK_DELIVERY_NOT_FOUND"
+	SplitNotFound            Code = "SPLIT_NOT_FOUND"
+	NudgeCooldown            Code = "NUDGE_COOLDOWN"
+	StatementNotFound        Code = "STATEMENT_NOT_FOUND"
+	StatementNotReady        Code = "STATEMENT_NOT_READY"
+)
+
+type definition struct {
+	status int
+	title  string
+}
+
+var catalogue = map[Code]definition{
+	BadRequest:           {http.StatusBadRequest, "Bad request"},
+	ValidationFailed:     {http.StatusBadRequest, "Request validation failed"},
+	Unauthenticated:      {http.StatusUnauthorized, "Authentication required"},
+	PermissionDenied:     {http.StatusForbidden, "Permission denied"},
+	NotFound:             {http.StatusNotFound, "Resource not found"},
+	Conflict:             {http.StatusConflict, "Conflict"},
+	PreconditionFailed:   {http.StatusPreconditionFailed, "Precondition failed"},
+	PayloadTooLarge:      {http.StatusRequestEntityTooLarge, "Payload too large"},
+	UnprocessableEntity:  {http.StatusUnprocessableEntity, "Unprocessable entity"},
+	PreconditionRequired: {http.StatusPreconditionRequired, "Precondition required"},
+	RateLimited:          {http.StatusTooManyRequests, "Too many requests"},
+	Internal:             {http.StatusInternalServerError, "Internal server error"},
+	Unavailable:          {http.StatusServiceUnavailable, "Service unavailable"},
+
+	UserNotFound:             {http.StatusNotFound, "User not found"},
+	UserAlreadyExists:        {http.StatusConflict, "User already exists"},
+	AccountInactive:          {http.StatusForbidden, "Account is not active"},
+	AccountAlreadyActive:     {http.StatusConflict, "Account is already active"},
+	AccountLocked:            {http.StatusTooManyRequests, "Account is temporarily locked"},
+	InvalidCredentials:       {http.StatusUnauthorized, "Invalid credentials"},
+	InvalidToken:             {http.StatusUnauthorized, "Invalid or expired token"},
+	InvalidOTP:               {http.StatusBadRequest, "Invalid OTP"},
+	OTPExpired:               {http.StatusBadRequest, "OTP has expired"},
+	GroupNotFound:            {http.StatusNotFound, "Group not found"},
+	AlreadyGroupMember:       {http.StatusConflict, "User is already a group member"},
+	BillNotFound:             {http.StatusNotFound, "Bill not found"},
+	InvalidAmount:            {http.StatusUnprocessableEntity, "Invalid amount"},
+	InvalidReference:         {http.StatusUnprocessableEntity, "Referenced resource does not exist"},
+	NoBillsToSplit:           {http.StatusUnprocessableEntity, "No bills to split"},
+	GroupHasNoMembers:        {http.StatusUnprocessableEntity, "Group has no members"},
+	SplitAlreadyExists:       {http.StatusConflict, "Bills are already split"},
+	ContactNotFound:          {http.StatusNotFound, "Contact not found"},
+	ContactAlreadyExists:     {http.StatusConflict, "Contact already exists"},
+	InviteAlreadySent:        {http.StatusConflict, "Invite already sent"},
+	InviteNotFound:           {http.StatusNotFound, "Contact invite not found"},
+	InvalidCSV:               {http.StatusBadRequest, "Invalid CSV file"},
+	InvalidImportRows:        {http.StatusUnprocessableEntity, "Import contains invalid rows"},
+	VersionConflict:          {http.StatusPreconditionFailed, "Resource was modified"},
+	IdempotencyKeyReused:     {http.StatusUnprocessableEntity, "Idempotency-Key reused with a different request"},
+	IdempotencyKeyInProgress: {http.StatusConflict, "Request with this Idempotency-Key is in progress"},
+	NothingToSettle:          {http.StatusUnprocessableEntity, "No outstanding balance to settle"},
+	WebhookNotFound:          {http.StatusNotFound, "Webhook not found"},
+	WebhookDeliveryNotFound:  {http.StatusNotFound, "Webhook delivery not found"},
+	SplitNotFound:            {http.StatusNotFound, "Bill split not found"},
+	NudgeCooldown:            {http.StatusTooManyRequests, "Member was nudged recently"},
+	StatementNotFound:        {http.StatusNotFound, "Statement not found"},
+	StatementNotReady:        {http.StatusUnprocessableEntity, "Month is not over yet"},
+}
+
+func (c Code) Error() string {
+	return string(c)
+}
+
+// Status is the HTTP status the code is rendered with
+func (c Code) Status() int {
+	if def, ok := catalogue[c]; ok {
+		return def.status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Title is the short, human readable summary of the code
+func (c Code) Title() string {
+	if def, ok := catalogue[c]; ok {
+		return def.title
+	}
+
+	return http.StatusText(c.Status())
+}
+
+// codeForStatus picks the generic code of a status, for errors created from a bare status
+func codeForStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return BadRequest
+	case http.StatusUnauthorized:
+		return Unauthenticated
+	case http.StatusForbidden:
+		return PermissionDenied
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusConflict:
+		return Conflict
+	case http.StatusPreconditionFailed:
+		return PreconditionFailed
+	case http.StatusRequestEntityTooLarge:
+		return PayloadTooLarge
+	case http.StatusUnprocessableEntity:
+		return UnprocessableEntity
+	case http.StatusPreconditionRequired:
+		return PreconditionRequired
+	case http.StatusTooManyRequests:
+		return RateLimited
+	case http.StatusServiceUnavailable:
+		return Unavailable
+	default:
+		return Internal
+	}
+}
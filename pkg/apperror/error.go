@@ -7,10 +7,16 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Error is the error returned across the service layer. It carries a catalogue code, a message safe to show to
+// clients, and optionally the underlying cause, which stays reachable through errors.Is and errors.As.
 type Error struct {
 	err        error
+	message    string
 	isError    bool
 	statusCode int
+	code       Code
+	fields     []FieldError
+	extensions map[string]any
 }
 
 type Interface interface {
@@ -20,37 +26,107 @@ type Interface interface {
 	AbortWithError(ctx *gin.Context)
 }
 
+// New wraps err with the generic code of errCode, the cause is never shown to clients
 func New(err error, errCode int) Error {
-	return Error{err: err, isError: true, statusCode: errCode}
+	return Error{err: err, isError: true, statusCode: errCode, code: codeForStatus(errCode)}
 }
 
+// NewWithMessage creates an error with the generic code of errCode
 func NewWithMessage(errMsg string, errCode int) Error {
-	return Error{err: errors.New(errMsg), isError: true, statusCode: errCode}
+	return Error{message: errMsg, isError: true, statusCode: errCode, code: codeForStatus(errCode)}
+}
+
+// NewCode creates an error with a catalogue code, an empty message falls back to the code's title
+func NewCode(code Code, message string) Error {
+	return Error{message: message, isError: true, statusCode: code.Status(), code: code}
+}
+
+// Wrap creates an error with a catalogue code around err, which remains reachable through errors.Is and errors.As
+func Wrap(err error, code Code, message string) Error {
+	return Error{err: err, message: message, isError: true, statusCode: code.Status(), code: code}
 }
 
 func (e Error) Exists() bool {
 	return e.isError
 }
 
+// Error describes the error for logs, including the cause
 func (e Error) Error() string {
-	if e.err != nil {
+	switch {
+	case e.message != "" && e.err != nil:
+		return e.message + ": " + e.err.Error()
+	case e.err != nil:
 		return e.err.Error()
+	case e.message != "":
+		return e.message
+	default:
+		return string(e.code)
 	}
-
-	return ""
 }
 
 func (e Error) Unwrap() error {
 	return e.err
 }
 
+// Is matches the error's code, so errors.Is(err, apperror.GroupNotFound) holds for wrapped errors as well
+func (e Error) Is(target error) bool {
+	code, ok := target.(Code)
+	return ok && e.isError && e.code == code
+}
+
+func (e Error) Code() Code {
+	return e.code
+}
+
+func (e Error) StatusCode() int {
+	if e.statusCode < 100 || e.statusCode >= 600 {
+		return http.StatusInternalServerError
+	}
+
+	return e.statusCode
+}
+
+// Message is the client facing description, it never includes the cause
+func (e Error) Message() string {
+	if e.message != "" {
+		return e.message
+	}
+
+	return e.code.Title()
+}
+
+func (e Error) Fields() []FieldError {
+	return e.fields
+}
+
+// With adds an extension member to the rendered problem, for instance the rows of a rejected import
+func (e Error) With(key string, value any) Error {
+	extensions := make(map[string]any, len(e.extensions)+1)
+	for k, v := range e.extensions {
+		extensions[k] = v
+	}
+	extensions[key] = value
+	e.extensions = extensions
+
+	return e
+}
+
+// AbortWithError stops the handler chain and hands the error to the error middleware, which renders it
 func (e Error) AbortWithError(ctx *gin.Context) {
-	status := e.statusCode
-	if status < 100 || status >= 600 {
-		status = http.StatusInternalServerError
+	_ = ctx.Error(e)
+	ctx.Abort()
+}
+
+// From converts any error into an Error, errors that are not one become internal errors
+func From(err error) Error {
+	if code, ok := err.(Code); ok {
+		return NewCode(code, "")
+	}
+
+	var appErr Error
+	if errors.As(err, &appErr) && appErr.Exists() {
+		return appErr
 	}
 
-	ctx.AbortWithStatusJSON(status, gin.H{
-		"error": e.Error(),
-	})
+	return Wrap(err, Internal, "")
 }
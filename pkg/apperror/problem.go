@@ -0,0 +1,64 @@
+package apperror
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const (
+	// ProblemContentType is the media type of RFC 7807 problem details
+	ProblemContentType = "application/problem+json"
+	problemTypePrefix  = "urn:split-ease:problem:"
+)
+
+// Problem is the RFC 7807 body every error response is rendered as. Code repeats the catalogue code, Errors holds
+// per-field details of validation failures and Extensions become additional top-level members.
+type Problem struct {
+	Type       string         `json:"type"`
+	Title      string         `json:"title"`
+	Status     int            `json:"status"`
+	Detail     string         `json:"detail,omitempty"`
+	Instance   string         `json:"instance,omitempty"`
+	Code       Code           `json:"code"`
+	Errors     []FieldError   `json:"errors,omitempty"`
+	Extensions map[string]any `json:"-"`
+}
+
+// Problem renders the error for the request at instance
+func (e Error) Problem(instance string) Problem {
+	return Problem{
+		Type:       problemTypePrefix + strings.ToLower(strings.ReplaceAll(string(e.code), "_", "-")),
+		Title:      e.code.Title(),
+		Status:     e.StatusCode(),
+		Detail:     e.Message(),
+		Instance:   instance,
+		Code:       e.code,
+		Errors:     e.fields,
+		Extensions: e.extensions,
+	}
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type members Problem
+
+	body, err := json.Marshal(members(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return body, err
+	}
+
+	merged := make(map[string]any, len(p.Extensions)+8)
+	for key, value := range p.Extensions {
+		merged[key] = value
+	}
+
+	// the standard members win over extensions of the same name
+	var standard map[string]any
+	if err = json.Unmarshal(body, &standard); err != nil {
+		return nil, err
+	}
+	for key, value := range standard {
+		merged[key] = value
+	}
+
+	return json.Marshal(merged)
+}
@@ -0,0 +1,142 @@
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes why a single request field was rejected. Field is the JSON name, Rule the failed rule.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ruleMessages holds the messages of custom validation rules, filled by RegisterRuleMessage at startup
+var ruleMessages = map[string]string{}
+
+// RegisterRuleMessage sets the message reported for fields failing a custom rule, call it before serving requests
+func RegisterRuleMessage(rule, message string) {
+	ruleMessages[rule] = message
+}
+
+// NewValidation creates a VALIDATION_FAILED error listing the rejected fields
+func NewValidation(fields ...FieldError) Error {
+	return Error{
+		message:    "One or more fields are invalid",
+		isError:    true,
+		statusCode: ValidationFailed.Status(),
+		code:       ValidationFailed,
+		fields:     fields,
+	}
+}
+
+// Validation converts the error of a gin bind into a VALIDATION_FAILED error with per-field details
+func Validation(err error) Error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			fields = append(fields, FieldError{
+				Field:   fieldErr.Field(),
+				Rule:    fieldErr.Tag(),
+				Message: ruleMessage(fieldErr),
+			})
+		}
+
+		validationErr := NewValidation(fields...)
+		validationErr.err = err
+
+		return validationErr
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return NewValidation(FieldError{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: "must be " + jsonType(typeErr.Type.Kind()),
+		})
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return Wrap(err, ValidationFailed, "Request body must be valid JSON")
+	}
+
+	return Wrap(err, ValidationFailed, "Invalid request")
+}
+
+func ruleMessage(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+
+	switch fieldErr.Tag() {
+	case "required", "required_with", "required_without":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		return "must be at least " + param + lengthUnit(fieldErr)
+	case "max":
+		return "must be at most " + param + lengthUnit(fieldErr)
+	case "len":
+		return "must be exactly " + param + lengthUnit(fieldErr)
+	case "gt":
+		return "must be greater than " + param
+	case "gte":
+		return "must be at least " + param
+	case "lt":
+		return "must be less than " + param
+	case "lte":
+		return "must be at most " + param
+	case "oneof":
+		return "must be one of " + param
+	case "numeric":
+		return "must be numeric"
+	case "http_url":
+		return "must be an http or https URL"
+	case "unique":
+		return "must not contain duplicates"
+	}
+
+	if message, ok := ruleMessages[fieldErr.Tag()]; ok {
+		return message
+	}
+
+	return "is invalid"
+}
+
+// lengthUnit qualifies min, max and len rules, which count characters on strings and elements on slices
+func lengthUnit(fieldErr validator.FieldError) string {
+	switch fieldErr.Kind() {
+	case reflect.String:
+		return " characters long"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
+	default:
+		return ""
+	}
+}
+
+// jsonType names a Go kind the way it appears in JSON
+func jsonType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
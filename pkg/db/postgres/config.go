@@ -14,6 +14,8 @@ type DbCluster struct {
 type Connection struct {
 	config DBConfig
 	db     *gorm.DB
+	// health is only tracked for slaves
+	health replicaHealth
 }
 
 type DBConfig struct {
@@ -2,8 +2,11 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"gorm.io/gorm"
 	"main/constants"
+	"sync"
 	"sync/atomic"
 )
 
@@ -21,36 +24,140 @@ func SetCluster(cluster *DbCluster) {
 	dbInstance = &Db{cluster}
 }
 
+// Consistency tracks whether a request has written to the master, after which its reads go to the master too
 type Consistency struct {
+	mu          sync.Mutex
 	consistency string
+	written     bool
+}
+
+// NewConsistency starts a request with reads on the slaves, or on the master when strong is set
+func NewConsistency(strong bool) *Consistency {
+	if strong {
+		return &Consistency{consistency: constants.StrongConsistency}
+	}
+
+	return &Consistency{consistency: constants.EventualConsistency}
+}
+
+func (c *Consistency) IsStrong() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.consistency == constants.StrongConsistency
+}
+
+// HasWritten reports whether the master was used for writes, as opposed to a request that started out strong
+func (c *Consistency) HasWritten() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.written
+}
+
+func (c *Consistency) markWritten() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.consistency = constants.StrongConsistency
+	c.written = true
+}
+
+// WithConsistency attaches a tracker to ctx, see middleware.ReadYourWrites
+func WithConsistency(ctx context.Context, consistency *Consistency) context.Context {
+	return context.WithValue(ctx, constants.Consistency, consistency)
+}
+
+// Transaction runs fn inside a master transaction carried by the returned context.
+// Every GetMasterDB/GetSlaveDB call made with that context joins the same transaction,
+// and a Transaction call on a context that already carries one simply reuses it.
+func (db *DbCluster) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := getTransaction(ctx); ok {
+		return fn(ctx)
+	}
+
+	markWrite(ctx)
+
+	return db.getMaster(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, constants.Transaction, tx))
+	})
 }
 
 func (db *DbCluster) GetMasterDB(ctx context.Context) *gorm.DB {
-	if val, ok := ctx.Value(constants.Consistency).(*Consistency); ok && val.consistency == constants.EventualConsistency {
-		val.consistency = constants.StrongConsistency
+	if tx, ok := getTransaction(ctx); ok {
+		return tx
 	}
 
+	markWrite(ctx)
+
 	return db.getMaster(ctx)
 }
 
 func (db *DbCluster) GetSlaveDB(ctx context.Context) *gorm.DB {
-	if val, ok := ctx.Value(constants.Consistency).(*Consistency); ok && val.consistency == constants.StrongConsistency {
+	// reads inside a transaction must see its uncommitted writes
+	if tx, ok := getTransaction(ctx); ok {
+		return tx
+	}
+
+	if val, ok := ctx.Value(constants.Consistency).(*Consistency); ok && val.IsStrong() {
 		return db.getMaster(ctx)
 	}
 
 	return db.getSlave(ctx)
 }
 
+// GetMasterSqlDB exposes the raw master pool for work that must bypass gorm, such as migrations
+func (db *DbCluster) GetMasterSqlDB() (*sql.DB, error) {
+	return db.master.db.DB()
+}
+
+// MasterNode is the node name of the master, slaves are named slave-0, slave-1, ... in configuration order
+const MasterNode = "master"
+
+// Nodes returns every connection of the cluster keyed by node name, for instrumentation and health checks
+func (db *DbCluster) Nodes() map[string]*gorm.DB {
+	nodes := make(map[string]*gorm.DB, len(db.slaves)+1)
+	nodes[MasterNode] = db.master.db
+	for i, slave := range db.slaves {
+		nodes[slaveNode(i)] = slave.db
+	}
+
+	return nodes
+}
+
+func slaveNode(i int) string {
+	return fmt.Sprintf("slave-%d", i)
+}
+
+// getSlave round-robins over the healthy slaves and falls back to the master when none is left
 func (db *DbCluster) getSlave(ctx context.Context) *gorm.DB {
 	slavesCount := len(db.slaves)
 	if slavesCount == 0 {
 		return db.getMaster(ctx)
 	}
 
-	slaveNumber := int(atomic.AddUint64(&db.counter, 1) % uint64(slavesCount))
-	return db.slaves[slaveNumber].db.WithContext(ctx)
+	start := atomic.AddUint64(&db.counter, 1)
+	for i := 0; i < slavesCount; i++ {
+		slave := db.slaves[int((start+uint64(i))%uint64(slavesCount))]
+		if slave.health.healthy.Load() {
+			return slave.db.WithContext(ctx)
+		}
+	}
+
+	return db.getMaster(ctx)
 }
 
 func (db *DbCluster) getMaster(ctx context.Context) *gorm.DB {
 	return db.master.db.WithContext(ctx)
 }
+
+func markWrite(ctx context.Context) {
+	if val, ok := ctx.Value(constants.Consistency).(*Consistency); ok {
+		val.markWritten()
+	}
+}
+
+func getTransaction(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(constants.Transaction).(*gorm.DB)
+	return tx, ok && tx != nil
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+)
+
+// dialector translates errors like postgres.Dialector but keeps the driver error wrapped next to the gorm one, so
+// callers can tell which constraint was violated with ConstraintName
+type dialector struct {
+	postgres.Dialector
+}
+
+func (d dialector) Translate(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return d.Dialector.Translate(err)
+	}
+
+	translated := d.Dialector.Translate(pgErr)
+	if translated == error(pgErr) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", translated, err)
+}
+
+// ConstraintName returns the name of the constraint err violated, empty when it is not a constraint violation
+func ConstraintName(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName
+	}
+
+	return ""
+}
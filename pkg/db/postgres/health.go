@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"sync"
+	"time"
+)
+
+type NodeStatus struct {
+	Node    string  `json:"node"`
+	Healthy bool    `json:"healthy"`
+	Latency float64 `json:"latency_ms"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// Ping checks every node of the cluster concurrently, statuses come back master first then slaves in order
+func (db *DbCluster) Ping(ctx context.Context) []NodeStatus {
+	names := []string{MasterNode}
+	for i := range db.slaves {
+		names = append(names, slaveNode(i))
+	}
+
+	nodes := db.Nodes()
+	statuses := make([]NodeStatus, len(names))
+
+	var wg sync.WaitGroup
+	for i, node := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i] = ping(ctx, node, nodes[node])
+		}()
+	}
+	wg.Wait()
+
+	return statuses
+}
+
+func ping(ctx context.Context, node string, conn *gorm.DB) NodeStatus {
+	start := time.Now()
+
+	sqlDB, err := conn.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+
+	status := NodeStatus{
+		Node:    node,
+		Healthy: err == nil,
+		Latency: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+
+	return status
+}
+
+// Close closes the pools of every node, once no request is using the cluster anymore
+func (db *DbCluster) Close() error {
+	errs := make([]error, 0)
+	for _, conn := range append([]*Connection{db.master}, db.slaves...) {
+		if conn == nil || conn.db == nil {
+			continue
+		}
+
+		sqlDB, err := conn.db.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -1,35 +1,68 @@
 package postgres
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
-	"log"
+	"log/slog"
+	"math/rand/v2"
 	"time"
 )
 
-func InitializeDBInstance(master DBConfig, slaves *[]DBConfig) *DbCluster {
-	db := getDbInstance(master, slaves)
-	return db
+// Backoff bounds the exponential retry used while a database node is unreachable at startup
+type Backoff struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	// MaxElapsedTime gives up on a node once this much time has passed since the first attempt
+	MaxElapsedTime time.Duration
 }
 
-func getDbInstance(master DBConfig, slaves *[]DBConfig) (instance *DbCluster) {
+func (b Backoff) withDefaults() Backoff {
+	if b.InitialInterval <= 0 {
+		b.InitialInterval = 500 * time.Millisecond
+	}
+	if b.MaxInterval < b.InitialInterval {
+		b.MaxInterval = b.InitialInterval
+	}
+	if b.MaxElapsedTime <= 0 {
+		b.MaxElapsedTime = time.Minute
+	}
+
+	return b
+}
+
+func InitializeDBInstance(ctx context.Context, master DBConfig, slaves *[]DBConfig, backoff Backoff) (*DbCluster, error) {
+	return getDbInstance(ctx, master, slaves, backoff.withDefaults())
+}
+
+func getDbInstance(ctx context.Context, master DBConfig, slaves *[]DBConfig, backoff Backoff) (*DbCluster, error) {
 	slavesCount := len(*slaves)
-	instance = &DbCluster{
+	instance := &DbCluster{
 		master: new(Connection),
 		slaves: make([]*Connection, slavesCount),
 	}
 
-	instance.master = initDbConnection(master)
+	var err error
+	if instance.master, err = initDbConnection(ctx, master, backoff); err != nil {
+		return nil, fmt.Errorf("master %s: %w", master.Host, err)
+	}
 
 	for i := 0; i < slavesCount; i++ {
-		instance.slaves[i] = initDbConnection((*slaves)[i])
+		if instance.slaves[i], err = initDbConnection(ctx, (*slaves)[i], backoff); err != nil {
+			// pools opened so far would leak otherwise
+			_ = instance.Close()
+			return nil, fmt.Errorf("slave %s: %w", (*slaves)[i].Host, err)
+		}
+		instance.slaves[i].markHealthy(slaveNode(i))
 	}
-	return
+
+	return instance, nil
 }
 
-func initDbConnection(config DBConfig) *Connection {
+func initDbConnection(ctx context.Context, config DBConfig, backoff Backoff) (*Connection, error) {
 	gormLogger := logger.Default
 	if config.DebugMode {
 		gormLogger = gormLogger.LogMode(logger.Info)
@@ -39,45 +72,71 @@ func initDbConnection(config DBConfig) *Connection {
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", config.Host, config.Port, config.Username, config.Password, config.Dbname,
 	)
 
-	gormDB, err := gorm.Open(postgres.Dialector{
+	gormDB, err := gorm.Open(dialector{postgres.Dialector{
 		Config: &postgres.Config{
 			DSN: dsn,
 		},
-	}, &gorm.Config{
+	}}, &gorm.Config{
 		Logger:                 gormLogger,
 		SkipDefaultTransaction: config.SkipDefaultTransaction,
 		PrepareStmt:            config.PrepareStmt,
+		// surface constraint violations as gorm.ErrDuplicatedKey, gorm.ErrForeignKeyViolated, ... see dialector
+		TranslateError: true,
+		// the server may still be starting, pingWithBackoff below waits for it instead
+		DisableAutomaticPing: true,
 	})
 	if err != nil {
-		panic("Unable to make gorm connection")
+		return nil, fmt.Errorf("unable to make gorm connection: %w", err)
 	}
 
 	sqlDB, err := gormDB.DB()
 	if err != nil {
-		panic("Unable to get sqlDB from gormDB")
+		return nil, fmt.Errorf("unable to get sqlDB from gormDB: %w", err)
 	}
 
 	sqlDB.SetMaxOpenConns(config.MaxOpenConnections)
 	sqlDB.SetMaxIdleConns(config.MaxIdleConnections)
 	sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
 
-	// Refactor this logic using exponential backoff
-	retries := 3
-	for retries > 0 {
-		err = sqlDB.Ping()
-		if err != nil {
-			log.Printf("Unable to ping database server: %s, waiting 2 seconds before trying %d more times\n", err.Error(), retries)
-			time.Sleep(time.Second * 2)
-			retries--
-		} else {
-			err = nil
-			break
-		}
-	}
-	if err != nil {
-		panic("Db not initialised")
+	if err = pingWithBackoff(ctx, config.Host, sqlDB.PingContext, backoff); err != nil {
+		_ = sqlDB.Close()
+		return nil, err
 	}
 
 	conn := Connection{db: gormDB, config: config}
-	return &conn
+	return &conn, nil
+}
+
+// pingWithBackoff doubles the wait after every failed ping, with jitter, until MaxElapsedTime or ctx is done
+func pingWithBackoff(ctx context.Context, host string, ping func(ctx context.Context) error, backoff Backoff) error {
+	start := time.Now()
+	interval := backoff.InitialInterval
+
+	for attempt := 1; ; attempt++ {
+		err := ping(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if time.Since(start)+interval > backoff.MaxElapsedTime {
+			return fmt.Errorf("database not reachable after %d attempts: %w", attempt, err)
+		}
+
+		// up to 20% jitter keeps replicas restarted together from retrying in lockstep
+		wait := interval + time.Duration(rand.Int64N(int64(interval)/5+1))
+		slog.Warn("unable to ping database server, retrying",
+			slog.String("host", host),
+			slog.Int("attempt", attempt),
+			slog.Duration("retry_in", wait),
+			slog.Any("error", err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(ctx.Err(), err)
+		case <-time.After(wait):
+		}
+
+		interval = min(interval*2, backoff.MaxInterval)
+	}
 }
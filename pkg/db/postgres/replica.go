@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"main/pkg/metrics"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// lag is zero on a caught up replica even when the primary has been idle, and on a node that is not a replica at all.
+// A replica that lost its primary has replayed everything it received too, so a zero lag only counts while its WAL
+// receiver is streaming. Reading the receiver status takes pg_read_all_stats, which pg_monitor includes.
+const replicationLagQuery = `SELECT
+	CASE
+		WHEN NOT pg_is_in_recovery() THEN 0
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END AS lag,
+	NOT pg_is_in_recovery() OR EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE status = 'streaming') AS streaming`
+
+// replicationStatus is a row of replicationLagQuery
+type replicationStatus struct {
+	Lag       float64
+	Streaming bool
+}
+
+type ReplicaCheckConfig struct {
+	Interval time.Duration
+	Timeout  time.Duration
+	// MaxLag skips replicas further behind the master than this, zero disables the lag check
+	MaxLag time.Duration
+	// FailureThreshold consecutive failed checks eject a replica, RecoveryThreshold passed checks re-admit it
+	FailureThreshold  int
+	RecoveryThreshold int
+}
+
+type ReplicaState struct {
+	Node                 string     `json:"node"`
+	Host                 string     `json:"host"`
+	Healthy              bool       `json:"healthy"`
+	LagSeconds           float64    `json:"lag_seconds"`
+	ConsecutiveFailures  int        `json:"consecutive_failures"`
+	ConsecutiveSuccesses int        `json:"consecutive_successes"`
+	LastError            string     `json:"last_error,omitempty"`
+	LastCheckedAt        *time.Time `json:"last_checked_at,omitempty"`
+	EjectedAt            *time.Time `json:"ejected_at,omitempty"`
+}
+
+type ClusterState struct {
+	Master string `json:"master"`
+	// FallbackToMaster is set while no replica is healthy and reads are served by the master
+	FallbackToMaster bool           `json:"fallback_to_master"`
+	Replicas         []ReplicaState `json:"replicas"`
+}
+
+// replicaHealth is written by the health check loop and read on every slave pick
+type replicaHealth struct {
+	healthy atomic.Bool
+
+	mu    sync.Mutex
+	state ReplicaState
+}
+
+// State snapshots the master and the health of every replica
+func (db *DbCluster) State() ClusterState {
+	state := ClusterState{
+		Master:           db.master.config.Host,
+		FallbackToMaster: len(db.slaves) > 0 && db.healthySlaves() == 0,
+		Replicas:         make([]ReplicaState, 0, len(db.slaves)),
+	}
+
+	for _, slave := range db.slaves {
+		slave.health.mu.Lock()
+		replica := slave.health.state
+		slave.health.mu.Unlock()
+
+		replica.Healthy = slave.health.healthy.Load()
+		state.Replicas = append(state.Replicas, replica)
+	}
+
+	return state
+}
+
+// MonitorReplicas checks every replica each Interval until ctx is done, run it as a background worker
+func (db *DbCluster) MonitorReplicas(cfg ReplicaCheckConfig) func(ctx context.Context) {
+	cfg = cfg.withDefaults()
+
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			db.checkReplicas(ctx, cfg)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+func (cfg ReplicaCheckConfig) withDefaults() ReplicaCheckConfig {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = time.Second
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 1
+	}
+	if cfg.RecoveryThreshold <= 0 {
+		cfg.RecoveryThreshold = 1
+	}
+
+	return cfg
+}
+
+func (db *DbCluster) checkReplicas(ctx context.Context, cfg ReplicaCheckConfig) {
+	var wg sync.WaitGroup
+	for _, slave := range db.slaves {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lag, err := slave.checkReplica(ctx, cfg)
+			slave.recordCheck(cfg, lag, err)
+		}()
+	}
+	wg.Wait()
+
+	metrics.DBReplicaFallback.Set(boolGauge(len(db.slaves) > 0 && db.healthySlaves() == 0))
+}
+
+// checkReplica returns the replication lag in seconds, or why the replica cannot serve reads
+func (c *Connection) checkReplica(ctx context.Context, cfg ReplicaCheckConfig) (float64, error) {
+	checkCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	var status replicationStatus
+	if err := c.db.WithContext(checkCtx).Raw(replicationLagQuery).Scan(&status).Error; err != nil {
+		return 0, err
+	}
+
+	lag := status.Lag
+	if !status.Streaming {
+		return lag, errors.New("replica is not streaming from the master")
+	}
+
+	if cfg.MaxLag > 0 && lag > cfg.MaxLag.Seconds() {
+		return lag, fmt.Errorf("replication lag %.1fs exceeds %s", lag, cfg.MaxLag)
+	}
+
+	return lag, nil
+}
+
+func (c *Connection) recordCheck(cfg ReplicaCheckConfig, lag float64, err error) {
+	h := &c.health
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	h.state.LastCheckedAt = &now
+	h.state.LagSeconds = lag
+	metrics.DBReplicaLag.WithLabelValues(h.state.Node).Set(lag)
+
+	if err != nil {
+		h.state.LastError = err.Error()
+		h.state.ConsecutiveSuccesses = 0
+		h.state.ConsecutiveFailures++
+
+		if h.healthy.Load() && h.state.ConsecutiveFailures >= cfg.FailureThreshold {
+			h.healthy.Store(false)
+			h.state.EjectedAt = &now
+			metrics.DBReplicaHealthy.WithLabelValues(h.state.Node).Set(0)
+			slog.Warn("replica ejected", slog.String("node", h.state.Node), slog.String("host", h.state.Host), slog.Any("error", err))
+		}
+
+		return
+	}
+
+	h.state.LastError = ""
+	h.state.ConsecutiveFailures = 0
+	h.state.ConsecutiveSuccesses++
+
+	if !h.healthy.Load() && h.state.ConsecutiveSuccesses >= cfg.RecoveryThreshold {
+		h.healthy.Store(true)
+		h.state.EjectedAt = nil
+		metrics.DBReplicaHealthy.WithLabelValues(h.state.Node).Set(1)
+		slog.Info("replica re-admitted", slog.String("node", h.state.Node), slog.String("host", h.state.Host))
+	}
+}
+
+func (c *Connection) markHealthy(node string) {
+	c.health.state = ReplicaState{Node: node, Host: c.config.Host}
+	c.health.healthy.Store(true)
+	metrics.DBReplicaHealthy.WithLabelValues(node).Set(1)
+}
+
+func (db *DbCluster) healthySlaves() int {
+	healthy := 0
+	for _, slave := range db.slaves {
+		if slave.health.healthy.Load() {
+			healthy++
+		}
+	}
+
+	return healthy
+}
+
+func boolGauge(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,29 @@
+package document
+
+// Document is a printable report made of a few summary fields followed by tables. It is rendered to HTML or PDF,
+// so what a report shows is built once and reads the same in both.
+type Document struct {
+	Title    string
+	Subtitle string
+	Summary  []Field
+	Tables   []Table
+}
+
+type Field struct {
+	Label string
+	Value string
+}
+
+type Table struct {
+	Title   string
+	Columns []Column
+	Rows    [][]string
+	// Empty is shown instead of the table when it has no rows
+	Empty string
+}
+
+type Column struct {
+	Title string
+	// Numeric columns are right aligned
+	Numeric bool
+}
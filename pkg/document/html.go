@@ -0,0 +1,17 @@
+package document
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+)
+
+//go:embed document.html.tmpl
+var htmlSource string
+
+var htmlTemplate = template.Must(template.New("document").Parse(htmlSource))
+
+// HTML writes doc as a standalone HTML page
+func HTML(w io.Writer, doc Document) error {
+	return htmlTemplate.Execute(w, doc)
+}
@@ -0,0 +1,106 @@
+package document
+
+import (
+	"github.com/go-fonts/dejavu/dejavusans"
+	"github.com/go-fonts/dejavu/dejavusansbold"
+	"github.com/go-fonts/dejavu/dejavusansoblique"
+	"github.com/go-pdf/fpdf"
+	"io"
+)
+
+// page layout of the PDF, in millimetres on A4
+const (
+	margin     = 15.0
+	lineHeight = 6.0
+	rowHeight  = 7.0
+)
+
+// font is embedded as UTF-8 so names and currency signs such as ₹ print as they are. DejaVu Sans covers Latin,
+// Greek, Cyrillic and most symbols, scripts it lacks such as CJK come out as empty boxes.
+const font = "DejaVu"
+
+// PDF writes doc as an A4 PDF
+func PDF(w io.Writer, doc Document) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(font, "", dejavusans.TTF)
+	pdf.AddUTF8FontFromBytes(font, "B", dejavusansbold.TTF)
+	pdf.AddUTF8FontFromBytes(font, "I", dejavusansoblique.TTF)
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.SetTitle(doc.Title, true)
+	pdf.AddPage()
+
+	pageWidth, _ := pdf.GetPageSize()
+	width := pageWidth - 2*margin
+
+	pdf.SetFont(font, "B", 16)
+	pdf.CellFormat(width, 9, doc.Title, "", 1, "L", false, 0, "")
+	if doc.Subtitle != "" {
+		pdf.SetFont(font, "", 10)
+		pdf.SetTextColor(102, 102, 102)
+		pdf.MultiCell(width, 5, doc.Subtitle, "", "L", false)
+		pdf.SetTextColor(34, 34, 34)
+	}
+	pdf.Ln(4)
+
+	for _, field := range doc.Summary {
+		pdf.SetFont(font, "", 10)
+		pdf.CellFormat(width/3, lineHeight, field.Label, "", 0, "L", false, 0, "")
+		pdf.SetFont(font, "B", 10)
+		pdf.CellFormat(2*width/3, lineHeight, field.Value, "", 1, "L", false, 0, "")
+	}
+
+	for _, table := range doc.Tables {
+		pdf.Ln(6)
+		pdf.SetFont(font, "B", 12)
+		pdf.CellFormat(width, 8, table.Title, "", 1, "L", false, 0, "")
+
+		if len(table.Rows) == 0 {
+			pdf.SetFont(font, "I", 10)
+			pdf.CellFormat(width, lineHeight, table.Empty, "", 1, "L", false, 0, "")
+			continue
+		}
+
+		cellWidth := width / float64(len(table.Columns))
+		pdf.SetFont(font, "B", 9)
+		pdf.SetFillColor(243, 243, 243)
+		for _, column := range table.Columns {
+			pdf.CellFormat(cellWidth, rowHeight, column.Title, "B", 0, align(column), true, 0, "")
+		}
+		pdf.Ln(-1)
+
+		pdf.SetFont(font, "", 9)
+		for _, row := range table.Rows {
+			for i, cell := range row {
+				pdf.CellFormat(cellWidth, rowHeight, fit(pdf, cell, cellWidth), "B", 0, align(table.Columns[i]), false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+	}
+
+	return pdf.Output(w)
+}
+
+func align(column Column) string {
+	if column.Numeric {
+		return "R"
+	}
+
+	return "L"
+}
+
+// fit shortens text that does not fit in a cell of the given width, cutting whole characters
+func fit(pdf *fpdf.Fpdf, text string, width float64) string {
+	const ellipsis = "…"
+
+	if pdf.GetStringWidth(text) <= width-2 {
+		return text
+	}
+
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+ellipsis) > width-2 {
+		runes = runes[:len(runes)-1]
+	}
+
+	return string(runes) + ellipsis
+}
@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"log/slog"
+	"main/constants"
+	"main/pkg/env"
+	"os"
+	"strings"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+
+	// Redacted replaces the value of every redacted field
+	Redacted = "[REDACTED]"
+)
+
+type Config struct {
+	Level string
+	// Format is either json or text, json is used when empty
+	Format string
+	// RedactFields are matched case-insensitively against attribute and JSON body keys
+	RedactFields []string
+	// MaskEmails masks email addresses in messages and string values, e.g. j***@example.com
+	MaskEmails bool
+}
+
+// Logger formats messages printf style and tags every record with the request ID of the context it was built from.
+// Errors are also recorded on the span of that context.
+type Logger struct {
+	*slog.Logger
+	span trace.Span
+}
+
+// Init installs the handler as the slog default, which also routes the standard log package through it
+func Init(cfg Config) {
+	Setup(os.Stdout, cfg)
+}
+
+func Setup(w io.Writer, cfg Config) {
+	setRedactor(newRedactor(cfg.RedactFields, cfg.MaskEmails))
+
+	opts := &slog.HandlerOptions{
+		Level:       ParseLevel(cfg.Level),
+		ReplaceAttr: replaceAttr,
+	}
+
+	var handler slog.Handler
+	if strings.EqualFold(cfg.Format, FormatText) {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+
+	slog.SetDefault(slog.New(handler))
+}
+
+func ParseLevel(level string) slog.Level {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return slog.LevelInfo
+	}
+
+	return lvl
+}
+
+// With returns a logger for funcName carrying the request ID and the trace stored in ctx
+func With(ctx context.Context, funcName string) Logger {
+	attrs := []any{
+		slog.String(constants.RequestID, env.GetRequestID(ctx)),
+		slog.String("func", funcName),
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if spanContext := span.SpanContext(); spanContext.IsValid() {
+		attrs = append(attrs,
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+
+	return Logger{Logger: slog.Default().With(attrs...), span: span}
+}
+
+func (l Logger) Debugf(format string, args ...any) {
+	l.Debug(fmt.Sprintf(format, args...))
+}
+
+func (l Logger) Infof(format string, args ...any) {
+	l.Info(fmt.Sprintf(format, args...))
+}
+
+func (l Logger) Warnf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	l.Warn(msg)
+	// spans skip the handler, so they get the same email masking here
+	l.span.AddEvent(getRedactor().maskString(msg))
+}
+
+func (l Logger) Errorf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	l.Error(msg)
+
+	msg = getRedactor().maskString(msg)
+	l.span.RecordError(errors.New(msg))
+	l.span.SetStatus(codes.Error, msg)
+}
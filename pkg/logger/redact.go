@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`([A-Za-z0-9._%+\-])[A-Za-z0-9._%+\-]*(@[A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+
+	activeRedactor atomic.Pointer[redactor]
+)
+
+type redactor struct {
+	fields     map[string]struct{}
+	maskEmails bool
+}
+
+func newRedactor(fields []string, maskEmails bool) *redactor {
+	r := &redactor{fields: make(map[string]struct{}, len(fields)), maskEmails: maskEmails}
+	for _, field := range fields {
+		r.fields[strings.ToLower(strings.TrimSpace(field))] = struct{}{}
+	}
+
+	return r
+}
+
+func setRedactor(r *redactor) {
+	activeRedactor.Store(r)
+}
+
+func getRedactor() *redactor {
+	if r := activeRedactor.Load(); r != nil {
+		return r
+	}
+
+	return &redactor{}
+}
+
+func (r *redactor) isRedacted(key string) bool {
+	_, ok := r.fields[strings.ToLower(key)]
+	return ok
+}
+
+func (r *redactor) maskString(value string) string {
+	if !r.maskEmails {
+		return value
+	}
+
+	return emailPattern.ReplaceAllString(value, "$1***$2")
+}
+
+func replaceAttr(_ []string, attr slog.Attr) slog.Attr {
+	r := getRedactor()
+	if r.isRedacted(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	if attr.Value.Kind() == slog.KindString {
+		return slog.String(attr.Key, r.maskString(attr.Value.String()))
+	}
+
+	return attr
+}
+
+// RedactJSON blanks redacted fields at any depth of a JSON document, bodies that are not JSON come back as nil
+func RedactJSON(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil
+	}
+
+	redacted, err := json.Marshal(getRedactor().redactValue(doc))
+	if err != nil {
+		return nil
+	}
+
+	return redacted
+}
+
+func (r *redactor) redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, nested := range v {
+			if r.isRedacted(key) {
+				v[key] = Redacted
+				continue
+			}
+
+			v[key] = r.redactValue(nested)
+		}
+
+		return v
+	case []any:
+		for i, nested := range v {
+			v[i] = r.redactValue(nested)
+		}
+
+		return v
+	case string:
+		return r.maskString(v)
+	default:
+		return v
+	}
+}
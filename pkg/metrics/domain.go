@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// reasons reported by OTPValidationFailures
+const (
+	OTPNotFound = "not_found"
+	OTPExpired  = "expired"
+	OTPMismatch = "mismatch"
+)
+
+var (
+	BillsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bills_created_total",
+		Help:      "Number of bills created, including imported bills.",
+	})
+
+	SplitsCalculated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "splits_calculated_total",
+		Help:      "Number of completed bill split calculations for a group.",
+	})
+
+	SettlementsRecorded = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "settlements_recorded_total",
+		Help:      "Number of settlements recorded between group members.",
+	})
+
+	PaymentRemindersSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "payment_reminders_sent_total",
+		Help:      "Number of notifications sent to debtors about unpaid splits, by kind (scheduled reminder or nudge).",
+	}, []string{"kind"})
+
+	GroupStreamsOpen = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "group_streams_open",
+		Help:      "Number of clients currently streaming live group updates from this instance.",
+	})
+
+	OTPsIssued = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "otps_issued_total",
+		Help:      "Number of OTPs issued by purpose.",
+	}, []string{"purpose"})
+
+	OTPValidationFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "otp_validation_failures_total",
+		Help:      "Number of failed OTP validations by purpose and reason.",
+	}, []string{"purpose", "reason"})
+)
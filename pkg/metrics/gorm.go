@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+	"time"
+)
+
+const startedAtKey = "metrics:started_at"
+
+// InstrumentDB records statement durations of db under the node label and exports its pool stats
+func InstrumentDB(node string, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	if err = prometheus.Register(collectors.NewDBStatsCollector(sqlDB, node)); err != nil {
+		return err
+	}
+
+	callbacks := db.Callback()
+
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("metrics:create:before", startTimer),
+		callbacks.Create().After("gorm:create").Register("metrics:create:after", observe(node, "create")),
+		callbacks.Query().Before("gorm:query").Register("metrics:query:before", startTimer),
+		callbacks.Query().After("gorm:query").Register("metrics:query:after", observe(node, "query")),
+		callbacks.Update().Before("gorm:update").Register("metrics:update:before", startTimer),
+		callbacks.Update().After("gorm:update").Register("metrics:update:after", observe(node, "update")),
+		callbacks.Delete().Before("gorm:delete").Register("metrics:delete:before", startTimer),
+		callbacks.Delete().After("gorm:delete").Register("metrics:delete:after", observe(node, "delete")),
+		callbacks.Row().Before("gorm:row").Register("metrics:row:before", startTimer),
+		callbacks.Row().After("gorm:row").Register("metrics:row:after", observe(node, "row")),
+		callbacks.Raw().Before("gorm:raw").Register("metrics:raw:before", startTimer),
+		callbacks.Raw().After("gorm:raw").Register("metrics:raw:after", observe(node, "raw")),
+	)
+}
+
+func startTimer(db *gorm.DB) {
+	db.InstanceSet(startedAtKey, time.Now())
+}
+
+func observe(node, operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		startedAt, ok := db.InstanceGet(startedAtKey)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+
+		DBQueryDuration.WithLabelValues(node, operation, table).Observe(time.Since(startedAt.(time.Time)).Seconds())
+	}
+}
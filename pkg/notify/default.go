@@ -0,0 +1,28 @@
+package notify
+
+import "sync"
+
+var (
+	defaultMu       sync.Mutex
+	defaultNotifier Notifier
+)
+
+// SetDefault makes notifier the one services send notifications through
+func SetDefault(notifier Notifier) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	defaultNotifier = notifier
+}
+
+// Default returns the notifier of the process, one that only logs unless SetDefault installed another
+func Default() Notifier {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	if defaultNotifier == nil {
+		defaultNotifier = NewLogNotifier()
+	}
+
+	return defaultNotifier
+}
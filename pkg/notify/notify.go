@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Message is one notification to one user. Kind names what it is about so channels can template or route it.
+type Message struct {
+	Kind    string
+	UserID  uint64
+	Email   string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages to users over a channel such as email or push. Notify returns once the channel took
+// the message, an error means it was not sent and the caller may try again.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// LogNotifier writes messages to the log instead of delivering them, it stands in until a real channel is set up
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, msg Message) error {
+	slog.InfoContext(ctx, "notification",
+		slog.String("kind", msg.Kind),
+		slog.Uint64("user_id", msg.UserID),
+		slog.String("subject", msg.Subject),
+		slog.String("body", msg.Body),
+	)
+
+	return nil
+}
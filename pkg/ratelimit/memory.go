@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+	rate     Rate
+}
+
+// MemoryStore is the in-process Store, buckets live as long as they are not full
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	calls   int
+}
+
+// pruneEvery bounds how often idle buckets are swept out of memory
+const pruneEvery = 1024
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket)}
+}
+
+func (m *MemoryStore) Take(_ context.Context, key string, rate Rate) (bool, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.calls++
+	if m.calls%pruneEvery == 0 {
+		m.prune(now)
+	}
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rate.Limit), lastSeen: now, rate: rate}
+		m.buckets[key] = b
+	}
+
+	perSecond := refillRate(rate)
+	b.tokens = math.Min(float64(rate.Limit), b.tokens+now.Sub(b.lastSeen).Seconds()*perSecond)
+	b.lastSeen = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+
+	wait := time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+	return false, wait, nil
+}
+
+// prune drops buckets that have refilled completely, they behave exactly like new ones
+func (m *MemoryStore) prune(now time.Time) {
+	for key, b := range m.buckets {
+		if b.tokens+now.Sub(b.lastSeen).Seconds()*refillRate(b.rate) >= float64(b.rate.Limit) {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+func refillRate(rate Rate) float64 {
+	return float64(rate.Limit) / rate.Period.Seconds()
+}
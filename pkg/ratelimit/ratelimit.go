@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+)
+
+const RetryAfterHeader = "Retry-After"
+
+// Rate allows Limit requests per Period, refilled continuously, with bursts of up to Limit
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// Store keeps the token buckets. MemoryStore only limits a single instance, a shared implementation
+// (e.g. backed by Redis) makes every instance draw from the same buckets.
+type Store interface {
+	// Take removes a token from the bucket of key, when none is left it reports how long until the next one
+	Take(ctx context.Context, key string, rate Rate) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// Limiter applies one rate to one family of keys, e.g. logins per IP
+type Limiter struct {
+	store Store
+	name  string
+	rate  Rate
+}
+
+// New returns a limiter whose buckets are namespaced by name, so limiters can share a store
+func New(store Store, name string, rate Rate) *Limiter {
+	return &Limiter{store: store, name: name, rate: rate}
+}
+
+func (l *Limiter) Name() string {
+	return l.name
+}
+
+func (l *Limiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	if l.rate.Limit <= 0 || l.rate.Period <= 0 {
+		return true, 0, nil
+	}
+
+	return l.store.Take(ctx, l.name+":"+key, l.rate)
+}
+
+// RetryAfter formats a wait as a Retry-After value in whole seconds, rounded up so clients never retry too early
+func RetryAfter(wait time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(wait.Seconds())))
+}
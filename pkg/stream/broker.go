@@ -0,0 +1,64 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// Event is one change of a group pushed to the clients streaming it
+type Event struct {
+	GroupID uint64          `json:"group_id"`
+	Type    string          `json:"type"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Broker carries events between instances. MemoryBroker only reaches the hub of its own process, a shared
+// implementation (e.g. backed by Redis pub/sub or Postgres LISTEN/NOTIFY) hands every event to the hubs of all
+// instances, so a client sees the changes made through any of them.
+type Broker interface {
+	// Publish sends an event to every subscriber of every instance
+	Publish(ctx context.Context, event Event) error
+	// Subscribe calls handle with each published event until ctx is done, handle must not block
+	Subscribe(ctx context.Context, handle func(Event)) error
+}
+
+// MemoryBroker is the in-process Broker
+type MemoryBroker struct {
+	mu       sync.RWMutex
+	handlers map[int]func(Event)
+	nextID   int
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{handlers: make(map[int]func(Event))}
+}
+
+func (b *MemoryBroker) Publish(_ context.Context, event Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, handle := range b.handlers {
+		handle(event)
+	}
+
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(ctx context.Context, handle func(Event)) error {
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = handle
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		delete(b.handlers, id)
+		b.mu.Unlock()
+	}()
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package stream
+
+import "sync"
+
+// defaultBuffer is the per subscription buffer of the hub used when none was configured
+const defaultBuffer = 32
+
+var (
+	defaultMu  sync.Mutex
+	defaultHub *Hub
+)
+
+// SetDefault makes hub the one services publish to and the stream endpoint subscribes to
+func SetDefault(hub *Hub) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	defaultHub = hub
+}
+
+// Default returns the hub of the process, an in-memory one unless SetDefault installed another
+func Default() *Hub {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	if defaultHub == nil {
+		defaultHub = NewHub(NewMemoryBroker(), defaultBuffer)
+	}
+
+	return defaultHub
+}
@@ -0,0 +1,121 @@
+package stream
+
+import (
+	"context"
+	"sync"
+)
+
+// Hub fans the events of the broker out to the subscriptions of this process, by group
+type Hub struct {
+	broker Broker
+	buffer int
+
+	mu            sync.Mutex
+	subscriptions map[uint64]map[*Subscription]struct{}
+	closed        bool
+}
+
+// NewHub buffers up to buffer events per subscription, a subscriber falling further behind is dropped
+func NewHub(broker Broker, buffer int) *Hub {
+	return &Hub{
+		broker:        broker,
+		buffer:        buffer,
+		subscriptions: make(map[uint64]map[*Subscription]struct{}),
+	}
+}
+
+// Run receives the events of the broker until ctx is done, then ends every subscription so that open streams
+// return and the server can shut down
+func (h *Hub) Run(ctx context.Context) error {
+	if err := h.broker.Subscribe(ctx, h.fanOut); err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for groupID, subscriptions := range h.subscriptions {
+		for subscription := range subscriptions {
+			close(subscription.events)
+		}
+		delete(h.subscriptions, groupID)
+	}
+
+	return nil
+}
+
+// Publish hands an event to the broker, which brings it to the hubs of every instance
+func (h *Hub) Publish(ctx context.Context, event Event) error {
+	return h.broker.Publish(ctx, event)
+}
+
+// Subscribe starts receiving the events of a group. The channel of the subscription is closed when the hub stops
+// or the subscriber fell behind, the client is then expected to reconnect and reload what it shows.
+func (h *Hub) Subscribe(groupID uint64) *Subscription {
+	subscription := &Subscription{hub: h, groupID: groupID, events: make(chan Event, h.buffer)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(subscription.events)
+		return subscription
+	}
+
+	if h.subscriptions[groupID] == nil {
+		h.subscriptions[groupID] = make(map[*Subscription]struct{})
+	}
+	h.subscriptions[groupID][subscription] = struct{}{}
+
+	return subscription
+}
+
+func (h *Hub) fanOut(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for subscription := range h.subscriptions[event.GroupID] {
+		select {
+		case subscription.events <- event:
+		default:
+			// never block the broker on one slow client, it misses this event so it has to start over
+			h.remove(subscription)
+		}
+	}
+}
+
+// remove ends a subscription, the caller holds h.mu
+func (h *Hub) remove(subscription *Subscription) {
+	subscriptions := h.subscriptions[subscription.groupID]
+	if _, ok := subscriptions[subscription]; !ok {
+		return
+	}
+
+	delete(subscriptions, subscription)
+	if len(subscriptions) == 0 {
+		delete(h.subscriptions, subscription.groupID)
+	}
+	close(subscription.events)
+}
+
+type Subscription struct {
+	hub     *Hub
+	groupID uint64
+	events  chan Event
+}
+
+// Events delivers the events of the group in the order the hub received them
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close ends the subscription, it is safe to call more than once and after the hub dropped it
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s)
+}
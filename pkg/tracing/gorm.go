@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	spanKey = "tracing:span"
+
+	// DBNodeKey names the cluster node a statement ran on, DBRoleKey whether it is the master or a slave
+	DBNodeKey = attribute.Key("db.node")
+	DBRoleKey = attribute.Key("db.role")
+)
+
+// InstrumentDB opens a client span for every statement run through db, tagged with its node and role
+func InstrumentDB(node, role string, db *gorm.DB) error {
+	callbacks := db.Callback()
+	attrs := []attribute.KeyValue{semconv.DBSystemPostgreSQL, DBNodeKey.String(node), DBRoleKey.String(role)}
+
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("tracing:create:before", startSpan("create", attrs)),
+		callbacks.Create().After("gorm:create").Register("tracing:create:after", endSpan),
+		callbacks.Query().Before("gorm:query").Register("tracing:query:before", startSpan("query", attrs)),
+		callbacks.Query().After("gorm:query").Register("tracing:query:after", endSpan),
+		callbacks.Update().Before("gorm:update").Register("tracing:update:before", startSpan("update", attrs)),
+		callbacks.Update().After("gorm:update").Register("tracing:update:after", endSpan),
+		callbacks.Delete().Before("gorm:delete").Register("tracing:delete:before", startSpan("delete", attrs)),
+		callbacks.Delete().After("gorm:delete").Register("tracing:delete:after", endSpan),
+		callbacks.Row().Before("gorm:row").Register("tracing:row:before", startSpan("row", attrs)),
+		callbacks.Row().After("gorm:row").Register("tracing:row:after", endSpan),
+		callbacks.Raw().Before("gorm:raw").Register("tracing:raw:before", startSpan("raw", attrs)),
+		callbacks.Raw().After("gorm:raw").Register("tracing:raw:after", endSpan),
+	)
+}
+
+func startSpan(operation string, attrs []attribute.KeyValue) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil || !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			// statements outside of a traced request would only produce orphan root spans
+			return
+		}
+
+		_, span := Tracer().Start(ctx, "db."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attrs...),
+			trace.WithAttributes(semconv.DBOperationName(operation)),
+		)
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func endSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+
+	span := value.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBCollectionName(db.Statement.Table),
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
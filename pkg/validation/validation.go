@@ -0,0 +1,140 @@
+package validation
+
+import (
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/currency"
+	"main/pkg/apperror"
+	"main/util"
+	"math"
+	"net/url"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// custom rules for `binding` tags, next to the built-in ones such as required, max or len
+const (
+	EmailAddress   = "email_address"
+	StrongPassword = "strong_password"
+	CurrencyCode   = "currency_code"
+	Money          = "money"
+	HTTPSURL       = "https_url"
+)
+
+const (
+	maxEmailLength    = 254
+	minPasswordLength = 8
+	// bcrypt ignores everything after 72 bytes
+	maxPasswordLength = 72
+	// MaxMoneyAmount caps a single amount, anything larger is a typo rather than a shared expense
+	MaxMoneyAmount = 1_000_000_000
+)
+
+var rules = []struct {
+	tag     string
+	fn      validator.Func
+	message string
+}{
+	{EmailAddress, isEmailAddress, "must be a valid email address"},
+	{StrongPassword, isStrongPassword, "must be 8 to 72 characters with an upper case letter, a lower case letter and a digit"},
+	{CurrencyCode, isCurrencyCode, "must be an upper case ISO 4217 currency code such as EUR"},
+	{Money, isMoney, "must be a positive amount with at most 2 decimals"},
+	{HTTPSURL, isHTTPSURL, "must be an https URL"},
+}
+
+// Register adds the custom rules to validate and makes field errors use the JSON name of the field
+func Register(validate *validator.Validate) error {
+	validate.RegisterTagNameFunc(fieldName)
+
+	for _, rule := range rules {
+		if err := validate.RegisterValidation(rule.tag, rule.fn); err != nil {
+			return errors.Join(errors.New("failed to register rule "+rule.tag), err)
+		}
+		apperror.RegisterRuleMessage(rule.tag, rule.message)
+	}
+
+	return nil
+}
+
+// fieldName names a field as clients send it, by its json or form tag
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
+func isEmailAddress(fl validator.FieldLevel) bool {
+	email := fl.Field().String()
+	return len(email) <= maxEmailLength && util.IsValidEmail(email)
+}
+
+func isStrongPassword(fl validator.FieldLevel) bool {
+	password := fl.Field().String()
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return false
+	}
+
+	var upper, lower, digit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+
+	return upper && lower && digit
+}
+
+func isCurrencyCode(fl validator.FieldLevel) bool {
+	return IsCurrencyCode(fl.Field().String())
+}
+
+// IsCurrencyCode reports whether code is an upper case ISO 4217 currency code
+func IsCurrencyCode(code string) bool {
+	if len(code) != 3 || code != strings.ToUpper(code) || code == "XXX" {
+		return false
+	}
+
+	_, err := currency.ParseISO(code)
+	return err == nil
+}
+
+func isMoney(fl validator.FieldLevel) bool {
+	var amount float64
+	switch fl.Field().Kind() {
+	case reflect.Float32, reflect.Float64:
+		amount = fl.Field().Float()
+	default:
+		return false
+	}
+
+	return IsMoney(amount)
+}
+
+// IsMoney reports whether amount is positive, at most MaxMoneyAmount and has no more than 2 decimals
+func IsMoney(amount float64) bool {
+	if math.IsNaN(amount) || amount <= 0 || amount > MaxMoneyAmount {
+		return false
+	}
+
+	cents := amount * 100
+	return math.Abs(cents-math.Round(cents)) < 1e-6
+}
+
+func isHTTPSURL(fl validator.FieldLevel) bool {
+	parsed, err := url.Parse(fl.Field().String())
+	return err == nil && parsed.Scheme == "https" && parsed.Hostname() != "" && parsed.User == nil
+}
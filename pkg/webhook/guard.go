@@ -0,0 +1,104 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned for receivers that are not public, deliveries must not reach into our own network
+var ErrForbiddenAddress = errors.New("webhook receiver is not a public address")
+
+// ranges that IsGlobalUnicast and IsPrivate let through but are not reachable public hosts either
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// IsPublicIP reports whether ip may receive deliveries. Loopback, private, link-local, which holds the cloud metadata
+// endpoint 169.254.169.254, unspecified, multicast and reserved addresses are not.
+func IsPublicIP(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckURL accepts https URLs whose host resolves to public addresses only. It catches bad receivers at
+// registration, the dialer of NewClient checks again on every delivery since the host may resolve elsewhere by then.
+func CheckURL(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return fmt.Errorf("%w: only https URLs are accepted", ErrForbiddenAddress)
+	}
+
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", parsed.Hostname())
+	if err != nil {
+		return err
+	}
+
+	for _, ip := range ips {
+		if !IsPublicIP(ip) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenAddress, parsed.Hostname(), ip)
+		}
+	}
+
+	return nil
+}
+
+// NewClient is the client deliveries are sent with. Its dialer refuses non-public addresses after resolution, which
+// also defeats DNS rebinding, and it ignores proxy settings so that check sees the receiver itself.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: dialControl,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		// a receiver that moved has to be registered again, redirects count as failed deliveries
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// dialControl runs right before each connection is made, with the address the host resolved to
+func dialControl(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !IsPublicIP(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// headers of a delivery, receivers verify SignatureHeader against TimestampHeader and the raw body
+const (
+	EventHeader     = "X-SplitEase-Event"
+	DeliveryHeader  = "X-SplitEase-Delivery"
+	TimestampHeader = "X-SplitEase-Timestamp"
+	SignatureHeader = "X-SplitEase-Signature"
+)
+
+const (
+	signaturePrefix = "sha256="
+	secretPrefix    = "whsec_"
+	// only the start of a response is kept for the delivery log
+	maxResponseBody = 1024
+)
+
+// NewSecret generates the signing secret of a webhook
+func NewSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return secretPrefix + hex.EncodeToString(buf), nil
+}
+
+// Sign computes the signature of a delivery, an HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret.
+// Signing the timestamp along with the body lets receivers reject replayed deliveries.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature received in SignatureHeader in constant time
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Backoff is the wait before the retry following the given attempt, doubling from initial up to max
+func Backoff(attempt int, initial, max time.Duration) time.Duration {
+	wait := initial
+	for i := 1; i < attempt && wait < max; i++ {
+		wait *= 2
+	}
+
+	return min(wait, max)
+}
+
+// Request is one event to deliver to one webhook
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID uint64
+	Payload    []byte
+}
+
+// Result is what the receiver answered, StatusCode is 0 when no response came back
+type Result struct {
+	StatusCode int
+	Body       string
+}
+
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender delivers with client, its Timeout bounds how long a receiver may take to answer
+func NewSender(client *http.Client) *Sender {
+	return &Sender{client: client}
+}
+
+// Send posts a signed delivery. Anything but a 2xx answer is an error, the result still carries the response.
+func (s *Sender) Send(ctx context.Context, req Request) (Result, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Payload))
+	if err != nil {
+		return Result{}, err
+	}
+
+	timestamp := time.Now().Unix()
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "SplitEase-Webhook/1.0")
+	httpReq.Header.Set(EventHeader, req.Event)
+	httpReq.Header.Set(DeliveryHeader, strconv.FormatUint(req.DeliveryID, 10))
+	httpReq.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(SignatureHeader, Sign(req.Secret, timestamp, req.Payload))
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	result := Result{StatusCode: resp.StatusCode, Body: string(body)}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return result, fmt.Errorf("receiver answered %d", resp.StatusCode)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,184 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"event":"bill.created"}`)
+	signature := Sign("whsec_test", 1700000000, body)
+
+	if signature[:len(signaturePrefix)] != signaturePrefix {
+		t.Fatalf("signature %q lacks the %q prefix", signature, signaturePrefix)
+	}
+	if !Verify("whsec_test", 1700000000, body, signature) {
+		t.Fatal("a signature does not verify against its own payload")
+	}
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+	}{
+		{"other secret", "whsec_other", 1700000000, body},
+		{"other timestamp", "whsec_test", 1700000001, body},
+		{"other body", "whsec_test", 1700000000, []byte(`{"event":"bill.deleted"}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if Verify(tt.secret, tt.timestamp, tt.body, signature) {
+				t.Fatal("signature verified against a different delivery")
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{50, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempt, 30*time.Second, time.Hour); got != tt.want {
+			t.Errorf("Backoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	payload := []byte(`{"event":"bill.created","bill_id":7}`)
+
+	var received *http.Request
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	result, err := NewSender(server.Client()).Send(context.Background(), Request{
+		URL:        server.URL,
+		Secret:     "whsec_test",
+		Event:      "bill.created",
+		DeliveryID: 42,
+		Payload:    payload,
+	})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if result.StatusCode != http.StatusNoContent {
+		t.Fatalf("status code = %d, want %d", result.StatusCode, http.StatusNoContent)
+	}
+
+	if got := received.Header.Get(EventHeader); got != "bill.created" {
+		t.Errorf("event header = %q", got)
+	}
+	if got := received.Header.Get(DeliveryHeader); got != "42" {
+		t.Errorf("delivery header = %q", got)
+	}
+	if string(receivedBody) != string(payload) {
+		t.Errorf("body = %s, want %s", receivedBody, payload)
+	}
+
+	timestamp, parseErr := strconv.ParseInt(received.Header.Get(TimestampHeader), 10, 64)
+	if parseErr != nil {
+		t.Fatalf("timestamp header: %v", parseErr)
+	}
+	if !Verify("whsec_test", timestamp, receivedBody, received.Header.Get(SignatureHeader)) {
+		t.Error("the receiver cannot verify the signature")
+	}
+}
+
+func TestSendFailsOnNon2xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/moved", http.StatusFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(time.Second)
+	client.Transport = server.Client().Transport
+
+	result, err := NewSender(client).Send(context.Background(), Request{URL: server.URL, Secret: "whsec_test"})
+	if err == nil {
+		t.Fatal("a redirect counted as delivered")
+	}
+	if result.StatusCode != http.StatusFound {
+		t.Fatalf("status code = %d, want %d", result.StatusCode, http.StatusFound)
+	}
+}
+
+func TestNewClientRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("a delivery reached a loopback receiver")
+	}))
+	defer server.Close()
+
+	_, err := NewSender(NewClient(time.Second)).Send(context.Background(), Request{URL: server.URL, Secret: "whsec_test"})
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Fatalf("err = %v, want ErrForbiddenAddress", err)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00:ec2::254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"::ffff:127.0.0.1", false},
+		{"224.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublicIP(netip.MustParseAddr(tt.ip)); got != tt.want {
+			t.Errorf("IsPublicIP(%s) = %t, want %t", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	tests := []struct {
+		url       string
+		forbidden bool
+	}{
+		{"https://93.184.216.34/hooks", false},
+		{"http://93.184.216.34/hooks", true},
+		{"https://127.0.0.1/hooks", true},
+		{"https://169.254.169.254/latest/meta-data", true},
+		{"https://[::1]:8443/hooks", true},
+		{"https://10.0.0.5/hooks", true},
+	}
+	for _, tt := range tests {
+		err := CheckURL(context.Background(), tt.url)
+		if got := errors.Is(err, ErrForbiddenAddress); got != tt.forbidden {
+			t.Errorf("CheckURL(%s) = %v, want forbidden %t", tt.url, err, tt.forbidden)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package worker
+
+import (
+	"context"
+	"time"
+)
+
+// Every wraps fn into a worker that runs it once per interval until ctx is done
+func Every(interval time.Duration, fn func(ctx context.Context)) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				fn(ctx)
+			}
+		}
+	}
+}
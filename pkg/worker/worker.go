@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Group runs background workers until their context is cancelled and lets shutdown wait for them to return
+type Group struct {
+	wg sync.WaitGroup
+}
+
+func NewGroup() *Group {
+	return &Group{}
+}
+
+// Go starts fn in its own goroutine, fn must return once ctx is done
+func (g *Group) Go(ctx context.Context, name string, fn func(ctx context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("background worker panicked", slog.String("worker", name), slog.Any("panic", r))
+			}
+		}()
+
+		slog.Info("background worker started", slog.String("worker", name))
+		fn(ctx)
+		slog.Info("background worker stopped", slog.String("worker", name))
+	}()
+}
+
+// Wait blocks until every worker returned or ctx is done, whichever comes first
+func (g *Group) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"gorm.io/gorm"
 	"main/pkg/apperror"
+	"main/repository/query"
 )
 
 type Interface[T any] interface {
@@ -17,6 +18,19 @@ type Interface[T any] interface {
 		updates any,
 	) apperror.Error
 
+	UpdateReturning(
+		ctx context.Context,
+		filter map[string]interface{},
+		updates map[string]interface{},
+	) (results []T, err apperror.Error)
+
+	UpdateWithVersion(
+		ctx context.Context,
+		filter map[string]interface{},
+		version uint64,
+		updates map[string]interface{},
+	) apperror.Error
+
 	UpdateMany(ctx context.Context, data []T) apperror.Error
 
 	GetAll(
@@ -25,11 +39,14 @@ type Interface[T any] interface {
 		scopes ...func(db *gorm.DB) *gorm.DB,
 	) (results []T, err apperror.Error)
 
+	Find(ctx context.Context, q *query.Query) (results []T, err apperror.Error)
+
 	GetAllWithPagination(
 		ctx context.Context,
 		filter map[string]interface{},
+		q *query.Query,
 		scopes ...func(db *gorm.DB) *gorm.DB,
-	) (results []T, count int64, err apperror.Error)
+	) (results []T, page query.Page, err apperror.Error)
 
 	Get(
 		ctx context.Context,
@@ -42,4 +59,21 @@ type Interface[T any] interface {
 		filter map[string]interface{},
 		scopes ...func(db *gorm.DB) *gorm.DB,
 	) apperror.Error
+
+	Restore(
+		ctx context.Context,
+		filter map[string]interface{},
+		scopes ...func(db *gorm.DB) *gorm.DB,
+	) apperror.Error
+
+	Purge(
+		ctx context.Context,
+		filter map[string]interface{},
+		scopes ...func(db *gorm.DB) *gorm.DB,
+	) (int64, apperror.Error)
+
+	Transaction(
+		ctx context.Context,
+		fn func(ctx context.Context) apperror.Error,
+	) apperror.Error
 }
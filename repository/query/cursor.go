@@ -0,0 +1,45 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// Cursor points at the last row of a page: its sort column value and primary key. It also records the order the
+// page was listed in, a cursor only makes sense for the next page of the same order.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d,omitempty"`
+	Value any    `json:"v"`
+	ID    uint64 `json:"id"`
+}
+
+func EncodeCursor(cursor Cursor) string {
+	raw, err := json.Marshal(cursor)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	cursor := new(Cursor)
+	if err = json.Unmarshal(raw, cursor); err != nil || cursor.ID == 0 || cursor.Sort == "" {
+		return nil, errors.New("invalid cursor")
+	}
+
+	return cursor, nil
+}
+
+// Page describes where a listing stopped: the cursor for the following page and the total match count
+type Page struct {
+	NextCursor string
+	Total      int64
+}
@@ -0,0 +1,83 @@
+package query
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 12, 30, 0, 123456000, time.UTC)
+	tests := []struct {
+		name   string
+		sort   string
+		cursor Cursor
+		want   any
+	}{
+		{"id", "", Cursor{Sort: "id", Value: 42, ID: 42}, float64(42)},
+		{"amount", "-paid_amount", Cursor{Sort: "paid_amount", Desc: true, Value: 12.5, ID: 7}, 12.5},
+		{"time", "created_at", Cursor{Sort: "created_at", Value: createdAt, ID: 9}, createdAt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := url.Values{CursorKey: {EncodeCursor(tt.cursor)}}
+			if tt.sort != "" {
+				values.Set(SortKey, tt.sort)
+			}
+
+			q, err := Parse(values, testColumns)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			if q.cursor.ID != tt.cursor.ID || !sameValue(q.cursor.Value, tt.want) {
+				t.Errorf("cursor = %+v, want value %v and id %d", q.cursor, tt.want, tt.cursor.ID)
+			}
+		})
+	}
+}
+
+func TestCursorSortMismatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		sort   string
+		cursor Cursor
+	}{
+		{"other column", "paid_amount", Cursor{Sort: "created_at", Value: "2024-03-01T00:00:00Z", ID: 3}},
+		{"other direction", "-paid_amount", Cursor{Sort: "paid_amount", Value: 12.5, ID: 3}},
+		{"default order", "", Cursor{Sort: "id", Desc: true, Value: 3, ID: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := url.Values{CursorKey: {EncodeCursor(tt.cursor)}}
+			if tt.sort != "" {
+				values.Set(SortKey, tt.sort)
+			}
+
+			_, err := Parse(values, testColumns)
+			if err == nil || !strings.Contains(err.Error(), "cursor does not match the sort order") {
+				t.Fatalf("err = %v, want a sort mismatch", err)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"not base64", "%%%"},
+		{"not json", EncodeCursor(Cursor{})[:2]},
+		{"no id", EncodeCursor(Cursor{Sort: "id", Value: 1})},
+		{"no sort", EncodeCursor(Cursor{Value: 1, ID: 1})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.encoded); err == nil {
+				t.Fatal("DecodeCursor accepted a broken cursor")
+			}
+		})
+	}
+}
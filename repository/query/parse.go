@@ -0,0 +1,207 @@
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Kind int
+
+const (
+	String Kind = iota
+	Int
+	Float
+	Bool
+	Time
+)
+
+const (
+	SortKey   = "sort"
+	LimitKey  = "limit"
+	CursorKey = "cursor"
+
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Column describes a field clients may filter or sort on, Name is the database column
+type Column struct {
+	Name      string
+	Kind      Kind
+	Sortable  bool
+	Operators []Operator
+}
+
+// Columns is a per-model whitelist keyed by the name used in query strings
+type Columns map[string]Column
+
+// Filterable is implemented by models that can be listed with client supplied filters
+type Filterable interface {
+	FilterColumns() Columns
+}
+
+// Parse turns a query string such as `category=food&paid_amount[gte]=10&sort=-created_at&limit=20`
+// into a Query. Only whitelisted columns and operators are accepted and every value is bound as a
+// parameter, so nothing from the client is ever spliced into SQL.
+func Parse(values url.Values, columns Columns) (*Query, error) {
+	q := New().Limit(DefaultLimit)
+
+	for key, rawValues := range values {
+		if len(rawValues) == 0 {
+			continue
+		}
+		raw := rawValues[0]
+
+		switch key {
+		case SortKey, CursorKey:
+			continue
+		case LimitKey:
+			limit, err := strconv.Atoi(raw)
+			if err != nil || limit < 1 {
+				return nil, fmt.Errorf("limit must be a positive number")
+			}
+			q.Limit(min(limit, MaxLimit))
+			continue
+		}
+
+		name, operator := splitKey(key)
+		column, ok := columns[name]
+		if !ok {
+			return nil, fmt.Errorf("filtering on %q is not supported", name)
+		}
+
+		if !column.allows(operator) {
+			return nil, fmt.Errorf("operator %q is not supported for %q", operator, name)
+		}
+
+		value, err := column.parseValue(operator, raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %q: %v", name, err)
+		}
+
+		q.Where(column.Name, operator, value)
+	}
+
+	if sort := values.Get(SortKey); sort != "" {
+		desc := strings.HasPrefix(sort, "-")
+		name := strings.TrimPrefix(sort, "-")
+
+		column, ok := columns[name]
+		if !ok || !column.Sortable {
+			return nil, fmt.Errorf("sorting by %q is not supported", name)
+		}
+
+		q.OrderBy(column.Name, desc)
+	}
+
+	if encoded := values.Get(CursorKey); encoded != "" {
+		cursor, err := DecodeCursor(encoded)
+		if err != nil {
+			return nil, err
+		}
+
+		// a cursor compared against another column would fail in the database or skip rows
+		if cursor.Sort != q.SortColumn() || cursor.Desc != q.SortDesc() {
+			return nil, fmt.Errorf("cursor does not match the sort order, list again from the first page")
+		}
+
+		if column, ok := columns.byName(q.SortColumn()); ok && column.Kind == Time {
+			// timestamps travel as RFC 3339 strings inside the cursor
+			text, _ := cursor.Value.(string)
+			parsed, err := time.Parse(time.RFC3339Nano, text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cursor")
+			}
+			cursor.Value = parsed
+		}
+
+		q.After(cursor)
+	}
+
+	return q, nil
+}
+
+// splitKey reads `amount[gte]` as (amount, gte) and a bare `amount` as (amount, eq)
+func splitKey(key string) (string, Operator) {
+	open := strings.Index(key, "[")
+	if open < 0 || !strings.HasSuffix(key, "]") {
+		return key, Eq
+	}
+
+	return key[:open], Operator(key[open+1 : len(key)-1])
+}
+
+func (c Columns) byName(name string) (Column, bool) {
+	for _, column := range c {
+		if column.Name == name {
+			return column, true
+		}
+	}
+
+	return Column{}, false
+}
+
+func (c Column) allows(operator Operator) bool {
+	operators := c.Operators
+	if len(operators) == 0 {
+		operators = defaultOperators(c.Kind)
+	}
+
+	for _, allowed := range operators {
+		if allowed == operator {
+			return true
+		}
+	}
+
+	return false
+}
+
+func defaultOperators(kind Kind) []Operator {
+	switch kind {
+	case String:
+		return []Operator{Eq, Neq, In, NotIn, Like, Prefix}
+	case Bool:
+		return []Operator{Eq}
+	default:
+		return []Operator{Eq, Neq, In, NotIn, Gt, Gte, Lt, Lte}
+	}
+}
+
+func (c Column) parseValue(operator Operator, raw string) (any, error) {
+	if operator != In && operator != NotIn {
+		return c.parseScalar(raw)
+	}
+
+	parts := strings.Split(raw, ",")
+	values := make([]any, 0, len(parts))
+	for _, part := range parts {
+		value, err := c.parseScalar(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+func (c Column) parseScalar(raw string) (any, error) {
+	switch c.Kind {
+	case Int:
+		return strconv.ParseInt(raw, 10, 64)
+	case Float:
+		return strconv.ParseFloat(raw, 64)
+	case Bool:
+		return strconv.ParseBool(raw)
+	case Time:
+		if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+			return parsed, nil
+		}
+		return time.Parse(time.DateOnly, raw)
+	default:
+		return raw, nil
+	}
+}
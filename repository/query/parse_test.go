@@ -0,0 +1,117 @@
+package query
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testColumns = Columns{
+	"id":          {Name: "id", Kind: Int, Sortable: true},
+	"category":    {Name: "category", Kind: String},
+	"paid_amount": {Name: "paid_amount", Kind: Float, Sortable: true},
+	"is_paid":     {Name: "is_paid", Kind: Bool},
+	"created_at":  {Name: "created_at", Kind: Time, Sortable: true},
+	"status":      {Name: "status", Kind: String, Operators: []Operator{Eq}},
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query      string
+		conditions []condition
+		sortColumn string
+		desc       bool
+		limit      int
+	}{
+		{"", nil, "id", false, DefaultLimit},
+		{"category=food", []condition{{column: "category", operator: Eq, value: "food"}}, "id", false, DefaultLimit},
+		{"paid_amount[gte]=10.5", []condition{{column: "paid_amount", operator: Gte, value: 10.5}}, "id", false, DefaultLimit},
+		{"id[in]=1,2", []condition{{column: "id", operator: In, value: []any{int64(1), int64(2)}}}, "id", false, DefaultLimit},
+		{"is_paid=true", []condition{{column: "is_paid", operator: Eq, value: true}}, "id", false, DefaultLimit},
+		{
+			"created_at[lt]=2024-03-01",
+			[]condition{{column: "created_at", operator: Lt, value: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}},
+			"id", false, DefaultLimit,
+		},
+		{"sort=-created_at", nil, "created_at", true, DefaultLimit},
+		{"sort=paid_amount&limit=5", nil, "paid_amount", false, 5},
+		{"limit=1000", nil, "id", false, MaxLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			q, err := Parse(values, testColumns)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			if len(q.conditions) != len(tt.conditions) {
+				t.Fatalf("conditions = %+v, want %+v", q.conditions, tt.conditions)
+			}
+			for i, want := range tt.conditions {
+				got := q.conditions[i]
+				if got.column != want.column || got.operator != want.operator || !sameValue(got.value, want.value) {
+					t.Errorf("condition = %+v, want %+v", got, want)
+				}
+			}
+
+			if q.SortColumn() != tt.sortColumn || q.SortDesc() != tt.desc {
+				t.Errorf("sort = %s desc %t, want %s desc %t", q.SortColumn(), q.SortDesc(), tt.sortColumn, tt.desc)
+			}
+			if q.GetLimit() != tt.limit {
+				t.Errorf("limit = %d, want %d", q.GetLimit(), tt.limit)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"password=secret", "filtering on \"password\" is not supported"},
+		{"category[gte]=food", "operator \"gte\" is not supported"},
+		{"status[ne]=open", "operator \"ne\" is not supported"},
+		{"is_paid[like]=t", "operator \"like\" is not supported"},
+		{"paid_amount=ten", "invalid value for \"paid_amount\""},
+		{"id[in]=1,two", "invalid value for \"id\""},
+		{"created_at=yesterday", "invalid value for \"created_at\""},
+		{"sort=category", "sorting by \"category\" is not supported"},
+		{"sort=-password", "sorting by \"password\" is not supported"},
+		{"limit=0", "limit must be a positive number"},
+		{"limit=many", "limit must be a positive number"},
+		{"cursor=not-base64!", "invalid cursor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			_, err := Parse(values, testColumns)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func sameValue(got, want any) bool {
+	switch want := want.(type) {
+	case []any:
+		values, ok := got.([]any)
+		if !ok || len(values) != len(want) {
+			return false
+		}
+		for i := range want {
+			if values[i] != want[i] {
+				return false
+			}
+		}
+		return true
+	case time.Time:
+		parsed, ok := got.(time.Time)
+		return ok && parsed.Equal(want)
+	default:
+		return got == want
+	}
+}
@@ -0,0 +1,249 @@
+package query
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"reflect"
+	"strings"
+)
+
+type Operator string
+
+const (
+	Eq    Operator = "eq"
+	Neq   Operator = "ne"
+	In    Operator = "in"
+	NotIn Operator = "nin"
+	Gt    Operator = "gt"
+	Gte   Operator = "gte"
+	Lt    Operator = "lt"
+	Lte   Operator = "lte"
+	Like  Operator = "like"
+	// Prefix matches values starting with the given text, case-insensitively
+	Prefix Operator = "prefix"
+)
+
+// idColumn breaks ties between rows sharing a sort value, which keeps cursors stable
+const idColumn = "id"
+
+type condition struct {
+	column   string
+	operator Operator
+	value    any
+	// any set means the condition holds when one of them does
+	any []condition
+}
+
+type order struct {
+	column string
+	desc   bool
+}
+
+// Query is a typed replacement for hand written gorm scopes. Column names given to the
+// builder methods are trusted, so anything coming from a client must go through Parse first.
+type Query struct {
+	conditions []condition
+	order      *order
+	limit      int
+	cursor     *Cursor
+}
+
+func New() *Query {
+	return &Query{conditions: make([]condition, 0)}
+}
+
+func (q *Query) Where(column string, operator Operator, value any) *Query {
+	q.conditions = append(q.conditions, condition{column: column, operator: operator, value: value})
+	return q
+}
+
+func (q *Query) Eq(column string, value any) *Query {
+	return q.Where(column, Eq, value)
+}
+
+func (q *Query) Neq(column string, value any) *Query {
+	return q.Where(column, Neq, value)
+}
+
+func (q *Query) In(column string, values any) *Query {
+	return q.Where(column, In, values)
+}
+
+// Between keeps rows with from <= column <= to, a nil bound is left open
+func (q *Query) Between(column string, from, to any) *Query {
+	if from != nil {
+		q.Where(column, Gte, from)
+	}
+	if to != nil {
+		q.Where(column, Lte, to)
+	}
+
+	return q
+}
+
+// Like matches rows whose column contains value, case-insensitively
+func (q *Query) Like(column string, value string) *Query {
+	return q.Where(column, Like, value)
+}
+
+// StartsWithAny matches rows where at least one of columns begins with prefix
+func (q *Query) StartsWithAny(prefix string, columns ...string) *Query {
+	alternatives := make([]condition, 0, len(columns))
+	for _, column := range columns {
+		alternatives = append(alternatives, condition{column: column, operator: Prefix, value: prefix})
+	}
+
+	q.conditions = append(q.conditions, condition{any: alternatives})
+	return q
+}
+
+func (q *Query) OrderBy(column string, desc bool) *Query {
+	q.order = &order{column: column, desc: desc}
+	return q
+}
+
+func (q *Query) Limit(limit int) *Query {
+	q.limit = limit
+	return q
+}
+
+// After continues a listing from the row the cursor was taken from
+func (q *Query) After(cursor *Cursor) *Query {
+	q.cursor = cursor
+	return q
+}
+
+func (q *Query) GetLimit() int {
+	return q.limit
+}
+
+// SortColumn reports the column rows are ordered by, the primary key when no order was set
+func (q *Query) SortColumn() string {
+	if q.order == nil {
+		return idColumn
+	}
+
+	return q.order.column
+}
+
+// SortDesc reports whether rows are ordered from the largest sort value down
+func (q *Query) SortDesc() bool {
+	return q.order != nil && q.order.desc
+}
+
+// Scopes compiles the query into gorm scopes usable with any repository method
+func (q *Query) Scopes() []func(db *gorm.DB) *gorm.DB {
+	scopes := q.FilterScopes()
+
+	column := q.SortColumn()
+	desc := q.SortDesc()
+
+	if q.cursor != nil {
+		// row comparison walks (column, id) in the same direction as the ordering below
+		operator := ">"
+		if desc {
+			operator = "<"
+		}
+
+		expression := clause.Expr{
+			SQL:  "(?, ?) " + operator + " (?, ?)",
+			Vars: []any{clause.Column{Name: column}, clause.Column{Name: idColumn}, q.cursor.Value, q.cursor.ID},
+		}
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where(expression)
+		})
+	}
+
+	if q.order != nil || q.cursor != nil || q.limit > 0 {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc})
+			if column != idColumn {
+				db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: idColumn}, Desc: desc})
+			}
+
+			return db
+		})
+	}
+
+	if q.limit > 0 {
+		limit := q.limit
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Limit(limit)
+		})
+	}
+
+	return scopes
+}
+
+// FilterScopes only carries the conditions, which is what a total count must be run with
+func (q *Query) FilterScopes() []func(db *gorm.DB) *gorm.DB {
+	scopes := make([]func(db *gorm.DB) *gorm.DB, 0, len(q.conditions)+3)
+	for _, c := range q.conditions {
+		expression := c.expression()
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where(expression)
+		})
+	}
+
+	return scopes
+}
+
+func (c condition) expression() clause.Expression {
+	if len(c.any) > 0 {
+		expressions := make([]clause.Expression, 0, len(c.any))
+		for _, alternative := range c.any {
+			expressions = append(expressions, alternative.expression())
+		}
+
+		return clause.Or(expressions...)
+	}
+
+	column := clause.Column{Name: c.column}
+
+	switch c.operator {
+	case Neq:
+		return clause.Neq{Column: column, Value: c.value}
+	case In:
+		return clause.IN{Column: column, Values: toValues(c.value)}
+	case NotIn:
+		return clause.Not(clause.IN{Column: column, Values: toValues(c.value)})
+	case Gt:
+		return clause.Gt{Column: column, Value: c.value}
+	case Gte:
+		return clause.Gte{Column: column, Value: c.value}
+	case Lt:
+		return clause.Lt{Column: column, Value: c.value}
+	case Lte:
+		return clause.Lte{Column: column, Value: c.value}
+	case Like:
+		value, _ := c.value.(string)
+		return clause.Expr{SQL: `? ILIKE ? ESCAPE '\'`, Vars: []any{column, "%" + escapeLike(value) + "%"}}
+	case Prefix:
+		value, _ := c.value.(string)
+		return clause.Expr{SQL: `? ILIKE ? ESCAPE '\'`, Vars: []any{column, escapeLike(value) + "%"}}
+	default:
+		return clause.Eq{Column: column, Value: c.value}
+	}
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func toValues(value any) []any {
+	if values, ok := value.([]any); ok {
+		return values
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []any{value}
+	}
+
+	values := make([]any, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		values = append(values, rv.Index(i).Interface())
+	}
+
+	return values
+}
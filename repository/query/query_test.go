@@ -0,0 +1,22 @@
+package query
+
+import "testing"
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"coffee", "coffee"},
+		{"100%", `100\%`},
+		{"snake_case", `snake\_case`},
+		{`C:\temp`, `C:\\temp`},
+		{`\%_`, `\\\%\_`},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := escapeLike(tt.value); got != tt.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -2,14 +2,31 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
-	"log"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 	"main/pkg/apperror"
 	"main/pkg/db/postgres"
-	"main/util"
-	"net/http"
+	"main/pkg/logger"
+	"main/pkg/tracing"
+	"main/repository/query"
+	"maps"
+	"reflect"
+	"time"
 )
 
+// VersionColumn is the optimistic locking counter bumped by UpdateWithVersion
+const VersionColumn = "version"
+
+// DeletedAtColumn is the soft delete marker gorm keeps on models with a gorm.DeletedAt field
+const DeletedAtColumn = "deleted_at"
+
+// ErrVersionConflict reports that a row changed, or disappeared, after the caller read it
+var ErrVersionConflict = errors.New("record was modified concurrently")
+
 type Repository[T any] struct {
 	Db *postgres.DbCluster
 }
@@ -19,44 +36,73 @@ func (r *Repository[T]) GetAll(
 	filter map[string]interface{},
 	scopes ...func(db *gorm.DB) *gorm.DB,
 ) (results []T, err apperror.Error) {
-	logTag := util.LogPrefix(ctx, "Repository.GetAll")
+	ctx, span := r.startSpan(ctx, "Repository.GetAll")
+	defer span.End()
+
+	log := logger.With(ctx, "Repository.GetAll")
 
 	tx := r.Db.GetSlaveDB(ctx).Model(&results).Where(filter).Scopes(scopes...).Find(&results)
 	if tx.Error != nil {
-		log.Println(logTag, "Error while fetching records:", tx.Error)
+		log.Errorf("error while fetching records: %v", tx.Error)
 
-		return nil, apperror.New(tx.Error, http.StatusBadRequest)
+		return nil, dbError(tx.Error)
 	}
 
 	return results, apperror.Error{}
 }
 
+// Find lists the records matching a typed query, see query.Query
+func (r *Repository[T]) Find(ctx context.Context, q *query.Query) (results []T, err apperror.Error) {
+	ctx, span := r.startSpan(ctx, "Repository.Find")
+	defer span.End()
+
+	return r.GetAll(ctx, nil, q.Scopes()...)
+}
+
+// GetAllWithPagination returns one page of q along with the total number of matches and,
+// when more rows follow, the cursor to fetch them with
 func (r *Repository[T]) GetAllWithPagination(
 	ctx context.Context,
 	filter map[string]interface{},
+	q *query.Query,
 	scopes ...func(db *gorm.DB) *gorm.DB,
-) (results []T, count int64, err apperror.Error) {
-	logTag := util.LogPrefix(ctx, "Repository.GetAllWithPagination")
+) (results []T, page query.Page, err apperror.Error) {
+	ctx, span := r.startSpan(ctx, "Repository.GetAllWithPagination")
+	defer span.End()
 
-	db := r.Db.GetSlaveDB(ctx).Model(&results).Where(filter).Scopes(scopes...)
+	log := logger.With(ctx, "Repository.GetAllWithPagination")
 
-	if tx := db.Count(&count); tx.Error != nil {
-		log.Println(logTag, "Error counting records:", tx.Error)
+	db := r.Db.GetSlaveDB(ctx)
 
-		return nil, 0, apperror.New(tx.Error, http.StatusBadRequest)
+	if tx := db.Model(new(T)).Where(filter).Scopes(scopes...).Scopes(q.FilterScopes()...).Count(&page.Total); tx.Error != nil {
+		log.Errorf("error counting records: %v", tx.Error)
+
+		return nil, page, dbError(tx.Error)
+	}
+	if page.Total == 0 {
+		return make([]T, 0), page, apperror.Error{}
 	}
-	if count == 0 {
-		log.Println(logTag, "No records found")
-		return nil, 0, apperror.Error{}
+
+	// one extra row tells whether another page follows without a second query
+	limit := q.GetLimit()
+	probe := *q
+	if limit > 0 {
+		probe.Limit(limit + 1)
 	}
 
-	if tx := db.Find(&results); tx.Error != nil {
-		log.Println(logTag, "Error fetching paginated records:", tx.Error)
+	tx := db.Model(new(T)).Where(filter).Scopes(scopes...).Scopes(probe.Scopes()...).Find(&results)
+	if tx.Error != nil {
+		log.Errorf("error fetching paginated records: %v", tx.Error)
 
-		return nil, 0, apperror.New(tx.Error, http.StatusBadRequest)
+		return nil, page, dbError(tx.Error)
 	}
 
-	return results, count, apperror.Error{}
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+		page.NextCursor = nextCursor(ctx, tx.Statement.Schema, q, &results[limit-1])
+	}
+
+	return results, page, apperror.Error{}
 }
 
 func (r *Repository[T]) Get(
@@ -64,13 +110,19 @@ func (r *Repository[T]) Get(
 	filter map[string]interface{},
 	scopes ...func(db *gorm.DB) *gorm.DB,
 ) (result T, err apperror.Error) {
-	logTag := util.LogPrefix(ctx, "Repository.Get")
+	ctx, span := r.startSpan(ctx, "Repository.Get")
+	defer span.End()
+
+	log := logger.With(ctx, "Repository.Get")
 
 	tx := r.Db.GetSlaveDB(ctx).Model(&result).Where(filter).Scopes(scopes...).First(&result)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return result, apperror.Wrap(tx.Error, apperror.NotFound, "")
+	}
 	if tx.Error != nil {
-		log.Println(logTag, "Error fetching record:", tx.Error)
+		log.Errorf("error fetching record: %v", tx.Error)
 
-		return result, apperror.New(tx.Error, http.StatusNotFound)
+		return result, dbError(tx.Error)
 	}
 
 	return result, apperror.Error{}
@@ -81,38 +133,92 @@ func (r *Repository[T]) Delete(
 	filter map[string]interface{},
 	scopes ...func(db *gorm.DB) *gorm.DB,
 ) apperror.Error {
-	logTag := util.LogPrefix(ctx, "Repository.Delete")
+	ctx, span := r.startSpan(ctx, "Repository.Delete")
+	defer span.End()
+
+	log := logger.With(ctx, "Repository.Delete")
 
 	tx := r.Db.GetMasterDB(ctx).Model(new(T)).Where(filter).Scopes(scopes...).Delete(nil)
 	if tx.Error != nil {
-		log.Println(logTag, "failed to soft delete record:", tx.Error)
-		return apperror.New(tx.Error, http.StatusBadRequest)
+		log.Errorf("failed to soft delete record: %v", tx.Error)
+		return dbError(tx.Error)
+	}
+
+	return apperror.Error{}
+}
+
+// Restore clears the soft delete marker of the matching deleted rows and reports NotFound when there were none
+func (r *Repository[T]) Restore(
+	ctx context.Context,
+	filter map[string]interface{},
+	scopes ...func(db *gorm.DB) *gorm.DB,
+) apperror.Error {
+	ctx, span := r.startSpan(ctx, "Repository.Restore")
+	defer span.End()
+
+	log := logger.With(ctx, "Repository.Restore")
+
+	tx := r.Db.GetMasterDB(ctx).Model(new(T)).Scopes(OnlyDeleted).Where(filter).Scopes(scopes...).
+		Update(DeletedAtColumn, nil)
+	if tx.Error != nil {
+		log.Errorf("failed to restore record: %v", tx.Error)
+		return dbError(tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return apperror.Wrap(gorm.ErrRecordNotFound, apperror.NotFound, "")
 	}
 
 	return apperror.Error{}
 }
 
+// Purge permanently deletes the matching rows, soft-deleted ones included
+func (r *Repository[T]) Purge(
+	ctx context.Context,
+	filter map[string]interface{},
+	scopes ...func(db *gorm.DB) *gorm.DB,
+) (int64, apperror.Error) {
+	ctx, span := r.startSpan(ctx, "Repository.Purge")
+	defer span.End()
+
+	log := logger.With(ctx, "Repository.Purge")
+
+	tx := r.Db.GetMasterDB(ctx).Unscoped().Model(new(T)).Where(filter).Scopes(scopes...).Delete(nil)
+	if tx.Error != nil {
+		log.Errorf("failed to purge records: %v", tx.Error)
+		return 0, dbError(tx.Error)
+	}
+
+	return tx.RowsAffected, apperror.Error{}
+}
+
 func (r *Repository[T]) Create(ctx context.Context, data *T) apperror.Error {
-	logTag := util.LogPrefix(ctx, "Repository.Create")
+	ctx, span := r.startSpan(ctx, "Repository.Create")
+	defer span.End()
+
+	log := logger.With(ctx, "Repository.Create")
 
 	tx := r.Db.GetMasterDB(ctx).Model(data).Create(data)
 	if tx.Error != nil {
-		log.Println(logTag, "Error creating record:", tx.Error)
+		log.Errorf("error creating record: %v", tx.Error)
 
-		return apperror.New(tx.Error, http.StatusBadRequest)
+		return dbError(tx.Error)
 	}
 
 	return apperror.Error{}
 }
 
 func (r *Repository[T]) CreateMany(ctx context.Context, data []*T) apperror.Error {
-	logTag := util.LogPrefix(ctx, "Repository.CreateMany")
+	ctx, span := r.startSpan(ctx, "Repository.CreateMany")
+	defer span.End()
+
+	log := logger.With(ctx, "Repository.CreateMany")
 
 	tx := r.Db.GetMasterDB(ctx).Model(data).CreateInBatches(data, 1500)
 	if tx.Error != nil {
-		log.Println(logTag, "Error creating records in bulk:", tx.Error)
+		log.Errorf("error creating records in bulk: %v", tx.Error)
 
-		return apperror.New(tx.Error, http.StatusBadRequest)
+		return dbError(tx.Error)
 	}
 
 	return apperror.Error{}
@@ -123,29 +229,204 @@ func (r *Repository[T]) Update(
 	filter map[string]interface{},
 	updates any,
 ) apperror.Error {
-	logTag := util.LogPrefix(ctx, "Repository.Update")
+	ctx, span := r.startSpan(ctx, "Repository.Update")
+	defer span.End()
+
+	log := logger.With(ctx, "Repository.Update")
 
 	tx := r.Db.GetMasterDB(ctx).Model(new(T)).Where(filter).Updates(updates)
 	if tx.Error != nil {
-		log.Println(logTag, "Error updating record:", tx.Error)
+		log.Errorf("error updating record: %v", tx.Error)
 
-		return apperror.New(tx.Error, http.StatusBadRequest)
+		return dbError(tx.Error)
+	}
+
+	return apperror.Error{}
+}
+
+// UpdateReturning applies updates and returns the rows as the statement left them, so a decision taken on a value
+// computed by the database, such as a counter incremented with gorm.Expr, sees every concurrent write before it
+func (r *Repository[T]) UpdateReturning(
+	ctx context.Context,
+	filter map[string]interface{},
+	updates map[string]interface{},
+) (results []T, err apperror.Error) {
+	ctx, span := r.startSpan(ctx, "Repository.UpdateReturning")
+	defer span.End()
+
+	log := logger.With(ctx, "Repository.UpdateReturning")
+
+	tx := r.Db.GetMasterDB(ctx).Model(&results).Clauses(clause.Returning{}).Where(filter).Updates(updates)
+	if tx.Error != nil {
+		log.Errorf("error updating records: %v", tx.Error)
+
+		return nil, dbError(tx.Error)
+	}
+
+	return results, apperror.Error{}
+}
+
+// UpdateWithVersion applies updates only while the matching row still carries version and bumps the version in
+// the same statement, so of two writers holding the same version only the first one succeeds. The loser gets
+// ErrVersionConflict with 412. A zero version skips the check but still bumps the version.
+func (r *Repository[T]) UpdateWithVersion(
+	ctx context.Context,
+	filter map[string]interface{},
+	version uint64,
+	updates map[string]interface{},
+) apperror.Error {
+	ctx, span := r.startSpan(ctx, "Repository.UpdateWithVersion")
+	defer span.End()
+
+	log := logger.With(ctx, "Repository.UpdateWithVersion")
+
+	values := maps.Clone(updates)
+	values[VersionColumn] = gorm.Expr(VersionColumn + " + 1")
+
+	tx := r.Db.GetMasterDB(ctx).Model(new(T)).Where(filter)
+	if version > 0 {
+		tx = tx.Where(VersionColumn+" = ?", version)
+	}
+
+	tx = tx.Updates(values)
+	if tx.Error != nil {
+		log.Errorf("error updating versioned record: %v", tx.Error)
+
+		return dbError(tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		log.Warnf("no record matched version %d", version)
+
+		return apperror.Wrap(ErrVersionConflict, apperror.VersionConflict, "")
 	}
 
 	return apperror.Error{}
 }
 
 func (r *Repository[T]) UpdateMany(ctx context.Context, data []T) apperror.Error {
-	logTag := util.LogPrefix(ctx, "Repository.UpdateMany")
+	ctx, span := r.startSpan(ctx, "Repository.UpdateMany")
+	defer span.End()
+
+	log := logger.With(ctx, "Repository.UpdateMany")
+
+	// each item is its own statement, so run them together to avoid partial writes
+	return r.Transaction(ctx, func(txCtx context.Context) apperror.Error {
+		for _, item := range data {
+			tx := r.Db.GetMasterDB(txCtx).Save(&item)
+			if tx.Error != nil {
+				log.Errorf("error updating item in bulk: %v", tx.Error)
+
+				return dbError(tx.Error)
+			}
+		}
 
-	for _, item := range data {
-		tx := r.Db.GetMasterDB(ctx).Save(&item)
-		if tx.Error != nil {
-			log.Println(logTag, "Error updating item in bulk:", tx.Error)
+		return apperror.Error{}
+	})
+}
+
+// Transaction runs fn as a single unit of work on the master. Repository calls made with the
+// context handed to fn, from any repository, take part in the same transaction.
+func (r *Repository[T]) Transaction(
+	ctx context.Context,
+	fn func(ctx context.Context) apperror.Error,
+) apperror.Error {
+	ctx, span := r.startSpan(ctx, "Repository.Transaction")
+	defer span.End()
+
+	log := logger.With(ctx, "Repository.Transaction")
+
+	err := r.Db.Transaction(ctx, func(txCtx context.Context) error {
+		if fnErr := fn(txCtx); fnErr.Exists() {
+			return fnErr
+		}
 
-			return apperror.New(tx.Error, http.StatusBadRequest)
+		return nil
+	})
+	if err != nil {
+		var appErr apperror.Error
+		if errors.As(err, &appErr) {
+			return appErr
 		}
+
+		log.Errorf("error running transaction: %v", err)
+		return dbError(err)
 	}
 
 	return apperror.Error{}
 }
+
+// OnlyDeleted is a scope that turns a query to the soft-deleted rows, the trash, instead of the live ones
+func OnlyDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Where(DeletedAtColumn + " IS NOT NULL")
+}
+
+// DeletedBefore is a scope that keeps the rows soft-deleted before cutoff
+func DeletedBefore(cutoff time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return OnlyDeleted(db).Where(DeletedAtColumn+" < ?", cutoff)
+	}
+}
+
+// EqualFold is a scope that keeps the rows whose column equals value ignoring case. Pair it with an index on
+// LOWER(column), a plain index cannot serve it.
+func EqualFold(column, value string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(clause.Expr{SQL: "LOWER(?) = LOWER(?)", Vars: []any{clause.Column{Name: column}, value}})
+	}
+}
+
+// ForUpdate is a scope that locks the selected rows until the transaction ends, waiting for another transaction
+// holding them to finish first
+func ForUpdate(db *gorm.DB) *gorm.DB {
+	return db.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate})
+}
+
+// ForUpdateSkipLocked is a scope that locks the selected rows until the transaction ends and passes over the rows
+// another transaction holds, so concurrent workers claim disjoint batches
+func ForUpdateSkipLocked(db *gorm.DB) *gorm.DB {
+	return db.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked})
+}
+
+// startSpan names repository spans after the method and records which model they worked on
+func (r *Repository[T]) startSpan(ctx context.Context, spanName string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, spanName, attribute.String("db.model", reflect.TypeFor[T]().Name()))
+}
+
+// dbError keeps the translated gorm error wrapped, so callers can still match it with errors.Is,
+// while mapping constraint violations to the status they deserve
+func dbError(err error) apperror.Error {
+	switch {
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return apperror.Wrap(err, apperror.Conflict, "")
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return apperror.Wrap(err, apperror.InvalidReference, "")
+	case errors.Is(err, gorm.ErrCheckConstraintViolated):
+		return apperror.Wrap(err, apperror.UnprocessableEntity, "")
+	default:
+		return apperror.Wrap(err, apperror.Internal, "")
+	}
+}
+
+func nextCursor(ctx context.Context, modelSchema *schema.Schema, q *query.Query, last any) string {
+	if modelSchema == nil {
+		return ""
+	}
+
+	sortField := modelSchema.LookUpField(q.SortColumn())
+	idField := modelSchema.LookUpField("id")
+	if sortField == nil || idField == nil {
+		return ""
+	}
+
+	row := reflect.Indirect(reflect.ValueOf(last))
+	value, _ := sortField.ValueOf(ctx, row)
+	id, _ := idField.ValueOf(ctx, row)
+
+	rowID, ok := id.(uint64)
+	if !ok {
+		return ""
+	}
+
+	return query.EncodeCursor(query.Cursor{Sort: q.SortColumn(), Desc: q.SortDesc(), Value: value, ID: rowID})
+}
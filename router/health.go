@@ -0,0 +1,17 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"main/internal/health"
+)
+
+// RegisterHealthRoutes exposes the probes outside of /api/v1, without request logging or auth.
+// Debug routes reveal internal hosts and must only be enabled where the port is not public.
+func RegisterHealthRoutes(engine *gin.Engine, handler *health.Handler, debug bool) {
+	engine.GET("/healthz", handler.Liveness)
+	engine.GET("/readyz", handler.Readiness)
+
+	if debug {
+		engine.GET("/debug/cluster", handler.ClusterState)
+	}
+}
@@ -3,37 +3,86 @@ package router
 import (
 	"context"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/viper"
 	ctrl "main/internal/controller"
+	idempotencyService "main/internal/idempotency/service"
 	userService "main/internal/user/service"
 	"main/middleware"
 	opostgres "main/pkg/db/postgres"
+	"main/pkg/ratelimit"
 )
 
 func RegisterPublicRoutes(ctx context.Context, engine *gin.Engine) {
-	apiV1 := engine.Group("/api/v1", middleware.RequestLogger())
+	engine.Use(middleware.RequestID(), middleware.Tracing(), middleware.Metrics())
+	// metrics describe traffic and internal hosts without auth, they are served only where the port is not public
+	if viper.GetBool("server.metricsEndpoint") {
+		engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	}
+
+	apiV1 := engine.Group("/api/v1", middleware.RequestLogger(), middleware.ReadYourWrites(middleware.ReadYourWritesConfig{
+		PinDuration: viper.GetDuration("consistency.pinDuration"),
+		CookieName:  viper.GetString("consistency.cookieName"),
+		Secret:      []byte(viper.GetString("consistency.pinSecret")),
+	}), middleware.Errors())
 
 	userController := ctrl.Wire(ctx, opostgres.GetCluster().DbCluster)
 
+	// swap for a shared store to enforce the limits across instances
+	limitStore := ratelimit.NewMemoryStore()
+
 	// Public user routes
 	userRoutes := apiV1.Group("/users")
 	{
-		userRoutes.POST("/register", userController.RegisterUser)
-		userRoutes.POST("/login", userController.LoginUser)
-		userRoutes.POST("/activate", userController.ActivateUser)
-		userRoutes.POST("/send-activation", userController.SendActivationEmail)
+		userRoutes.POST("/register",
+			rateLimit(limitStore, "register.ip", middleware.ByIP),
+			userController.RegisterUser)
+		userRoutes.POST("/login",
+			rateLimit(limitStore, "login.ip", middleware.ByIP),
+			rateLimit(limitStore, "login.email", middleware.ByEmail),
+			userController.LoginUser)
+		userRoutes.POST("/activate",
+			rateLimit(limitStore, "activate.ip", middleware.ByIP),
+			rateLimit(limitStore, "activate.email", middleware.ByEmail),
+			userController.ActivateUser)
+		userRoutes.POST("/send-activation",
+			rateLimit(limitStore, "sendActivation.ip", middleware.ByIP),
+			rateLimit(limitStore, "sendActivation.email", middleware.ByEmail),
+			userController.SendActivationEmail)
 	}
 
 	// Auth middleware
 	authMiddleware := middleware.NewAuthMiddleware(userService.Wire(ctx, opostgres.GetCluster().DbCluster))
 
+	// replays retried writes that carry an Idempotency-Key
+	idempotency := middleware.Idempotency(
+		idempotencyService.Wire(ctx, opostgres.GetCluster().DbCluster),
+		int64(viper.GetSizeInBytes("idempotency.maxBodySize")),
+	)
+
 	// Protected routes
-	protectedRoutes := apiV1.Group("/", middleware.SanitizeQueryParams(), authMiddleware.Authenticate())
+	protectedRoutes := apiV1.Group("/", middleware.SanitizeQueryParams(), authMiddleware.Authenticate(), idempotency)
 	{
 		protectedRoutes.PUT("/users", userController.UpdateUserProfile)
-		protectedRoutes.GET("/users", userController.GetUsers)
+
+		// Contacts and user search
+		protectedRoutes.GET("/users/contacts", userController.ListContacts)
+		protectedRoutes.POST("/users/contacts",
+			rateLimit(limitStore, "addContact.user", middleware.ByUser),
+			userController.AddContact)
+		protectedRoutes.GET("/users/contacts/invites", userController.GetContactInvites)
+		protectedRoutes.POST("/users/contacts/invites/:invite_id/accept", userController.AcceptContactInvite)
+		protectedRoutes.DELETE("/users/contacts/invites/:invite_id", userController.DeclineContactInvite)
+		protectedRoutes.DELETE("/users/contacts/:user_id", userController.RemoveContact)
+		protectedRoutes.GET("/users/search",
+			rateLimit(limitStore, "userSearch.user", middleware.ByUser),
+			userController.SearchUsers)
+
+		// Monthly statements
+		protectedRoutes.GET("/users/me/statements/:month", userController.GetUserStatement)
 	}
 
-	groupRoutes := apiV1.Group("/groups", middleware.SanitizeQueryParams(), authMiddleware.Authenticate())
+	groupRoutes := apiV1.Group("/groups", middleware.SanitizeQueryParams(), authMiddleware.Authenticate(), idempotency)
 	{
 		// Group management
 		groupRoutes.POST("/", userController.CreateGroup)
@@ -44,12 +93,42 @@ func RegisterPublicRoutes(ctx context.Context, engine *gin.Engine) {
 
 		// Bills for group
 		groupRoutes.POST("/:group_id/users/:user_id/bills", userController.CreateGroupBillForUser)
+		groupRoutes.GET("/:group_id/bills/:bill_id", userController.GetGroupBill)
 		groupRoutes.PUT("/:group_id/bills/:bill_id", userController.UpdateGroupBill)
 		groupRoutes.DELETE("/:group_id/bills/:bill_id", userController.DeleteGroupBill)
+		groupRoutes.POST("/:group_id/bills/import", userController.ImportGroupBills)
 		groupRoutes.POST("/:group_id/assign/:user_id", userController.AssignUserToGroup)
 
+		// Trash
+		groupRoutes.GET("/trash", userController.GetDeletedGroups)
+		groupRoutes.POST("/:group_id/restore", userController.RestoreGroup)
+		groupRoutes.GET("/:group_id/bills/trash", userController.GetGroupBillTrash)
+		groupRoutes.POST("/:group_id/bills/:bill_id/restore", userController.RestoreGroupBill)
+
 		// Bill Split routes
 		groupRoutes.POST("/:group_id/splits", userController.CalculateBillSplits)
 		groupRoutes.PUT("/:group_id/splits", userController.RecalculateBillSplits)
+
+		// Payment reminders
+		groupRoutes.GET("/:group_id/reminder-settings", userController.GetReminderSetting)
+		groupRoutes.PUT("/:group_id/reminder-settings", userController.UpdateReminderSetting)
+		groupRoutes.POST("/:group_id/splits/:split_id/snooze", userController.SnoozeReminders)
+		groupRoutes.POST("/:group_id/splits/:split_id/nudge", userController.NudgeDebtor)
+
+		// Monthly statements
+		groupRoutes.GET("/:group_id/statements/:month", userController.GetGroupStatement)
+
+		// Settlements and activity
+		groupRoutes.POST("/:group_id/settlements", userController.RecordSettlement)
+		groupRoutes.GET("/:group_id/settlements", userController.GetGroupSettlements)
+		groupRoutes.GET("/:group_id/activity", userController.GetGroupActivity)
+		groupRoutes.GET("/:group_id/stream", userController.StreamGroup)
+
+		// Webhooks
+		groupRoutes.POST("/:group_id/webhooks", userController.CreateGroupWebhook)
+		groupRoutes.GET("/:group_id/webhooks", userController.GetGroupWebhooks)
+		groupRoutes.DELETE("/:group_id/webhooks/:webhook_id", userController.DeleteGroupWebhook)
+		groupRoutes.GET("/:group_id/webhooks/:webhook_id/deliveries", userController.GetGroupWebhookDeliveries)
+		groupRoutes.POST("/:group_id/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", userController.RedeliverGroupWebhook)
 	}
 }
@@ -0,0 +1,16 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"main/middleware"
+	"main/pkg/ratelimit"
+)
+
+// rateLimit reads rateLimit.<name> from the config, a missing entry disables that limit
+func rateLimit(store ratelimit.Store, name string, key middleware.RateKey) gin.HandlerFunc {
+	return middleware.RateLimit(ratelimit.New(store, name, ratelimit.Rate{
+		Limit:  viper.GetInt("rateLimit." + name + ".limit"),
+		Period: viper.GetDuration("rateLimit." + name + ".period"),
+	}), key)
+}
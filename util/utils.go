@@ -35,10 +35,10 @@ func GenerateRandomNumericCode(length int) (string, error) {
 	return otp, nil
 }
 
+var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+
 func IsValidEmail(email string) bool {
-	regex := `^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`
-	re := regexp.MustCompile(regex)
-	return re.MatchString(email)
+	return emailRegex.MatchString(email)
 }
 
 func ParseUint(val string) (uint64, error) {